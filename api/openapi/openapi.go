@@ -0,0 +1,8 @@
+package openapi
+
+import _ "embed"
+
+// Spec — OpenAPI-спецификация сервиса, встроенная в бинарник.
+//
+//go:embed openapi.yaml
+var Spec []byte
@@ -4,14 +4,25 @@ import (
 	"context"
 	"fmt"
 	"github.com/aliskhannn/pvz-service/internal/auth"
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/config"
+	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/delivery/http"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/db"
 	"github.com/aliskhannn/pvz-service/internal/infrastructure/jwt"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/mail"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/metrics"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/publisher"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/ratelimit"
 	"github.com/aliskhannn/pvz-service/internal/repository/postgres"
 	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/aliskhannn/pvz-service/internal/worker"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"log"
+	"os"
 )
 
 func main() {
@@ -25,16 +36,7 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.Name,
-		cfg.Database.SSLMode,
-	)
-
-	dbpool, err := pgxpool.New(context.Background(), dbURL)
+	dbpool, err := pgxpool.New(context.Background(), cfg.Database.DSN())
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -46,14 +48,147 @@ func main() {
 	pvzRepo := postgres.NewPVZRepository(dbpool)
 	receptionRepo := postgres.NewReceptionRepository(dbpool)
 	productRepo := postgres.NewProductRepository(dbpool)
+	assignmentRepo := postgres.NewAssignmentRepository(dbpool)
+	roleRepo := postgres.NewRoleRepository(dbpool)
+	manifestRepo := postgres.NewManifestRepository(dbpool)
+	transferRepo := postgres.NewTransferRepository(dbpool)
+	correctionRepo := postgres.NewReceptionCorrectionRepository(dbpool)
+	outboxRepo := postgres.NewOutboxRepository(dbpool)
+	txManager := postgres.NewTxManager(dbpool)
+	webhookRepo := postgres.NewWebhookRepository(dbpool)
+	statsRepo := postgres.NewStatsRepository(dbpool)
+	exportRepo := postgres.NewExportRepository(dbpool)
+	storageCellRepo := postgres.NewStorageCellRepository(dbpool)
+
+	authorizer, err := authz.Load(context.Background(), roleRepo)
+	if err != nil {
+		log.Fatalf("Failed to load roles: %v", err)
+	}
+
+	ipLimiter := ratelimit.NewMemoryLimiter(cfg.RateLimit.IPRequests, cfg.RateLimit.Period)
+	emailLimiter := ratelimit.NewMemoryLimiter(cfg.RateLimit.EmailRequests, cfg.RateLimit.Period)
+	codeLimiter := ratelimit.NewMemoryLimiter(cfg.RateLimit.PickupCodeRequests, cfg.RateLimit.Period)
+
+	mailer := mail.NewLogSender(nil)
+
+	passwordPolicy := auth.PasswordPolicy{
+		MinLength:      cfg.Auth.PasswordMinLength,
+		RequireUpper:   cfg.Auth.PasswordRequireUpper,
+		RequireLower:   cfg.Auth.PasswordRequireLower,
+		RequireDigit:   cfg.Auth.PasswordRequireDigit,
+		RequireSpecial: cfg.Auth.PasswordRequireSpecial,
+	}
+
+	authUC := usecase.NewAuthUseCase(userRepo, tokens, hasher, mailer, authorizer, usecase.AuthOptions{
+		Lockout: usecase.LockoutPolicy{
+			MaxAttempts: cfg.Lockout.MaxAttempts,
+			Duration:    cfg.Lockout.Duration,
+		},
+		PasswordPolicy:           passwordPolicy,
+		RequireEmailVerification: cfg.Auth.RequireEmailVerification,
+		VerificationTTL:          cfg.Auth.VerificationTTL,
+		VerificationURL:          cfg.Auth.VerificationURL,
+	})
+
+	err = authUC.EnsureBootstrapAdmin(context.Background(), cfg.Auth.BootstrapAdminEmail, cfg.Auth.BootstrapAdminPassword)
+	if err != nil {
+		log.Fatalf("Failed to create bootstrap admin: %v", err)
+	}
+	pvzUC := usecase.NewPvzUseCase(pvzRepo, authorizer, txManager)
+	eventBroker := broker.New(cfg.Events.StreamBuffer)
+	receptionUC := usecase.NewReceptionUseCase(receptionRepo, manifestRepo, assignmentRepo, authorizer, txManager, outboxRepo, eventBroker)
+	switch cfg.Capacity.Policy {
+	case "", constants.CapacityPolicyReject, constants.CapacityPolicyWarn:
+	default:
+		log.Fatalf("Unknown capacity policy %q, use reject or warn", cfg.Capacity.Policy)
+	}
+	productUC := usecase.NewProductUseCase(productRepo, storageCellRepo, assignmentRepo, authorizer, txManager, outboxRepo, eventBroker, cfg.Capacity.Policy)
+	adminUC := usecase.NewAdminUseCase(userRepo, assignmentRepo, authorizer, passwordPolicy)
+	manifestUC := usecase.NewManifestUseCase(manifestRepo, receptionRepo, assignmentRepo, authorizer)
+	transferUC := usecase.NewTransferUseCase(transferRepo, productRepo, assignmentRepo, authorizer, txManager, outboxRepo, eventBroker, cfg.Capacity.Policy)
+	correctionUC := usecase.NewReceptionCorrectionUseCase(correctionRepo, receptionRepo, manifestRepo, productRepo, storageCellRepo, authorizer, txManager, outboxRepo, eventBroker, cfg.Capacity.Policy)
+	webhookUC := usecase.NewWebhookUseCase(webhookRepo, authorizer)
+	liveFeedUC := usecase.NewLiveFeedUseCase(eventBroker, pvzRepo, assignmentRepo, authorizer)
+	statsUC := usecase.NewStatsUseCase(statsRepo, authorizer, cfg.Stats.CacheTTL)
+	exportUC := usecase.NewExportUseCase(exportRepo, authorizer)
+	storageCellUC := usecase.NewStorageCellUseCase(storageCellRepo, assignmentRepo, authorizer, txManager)
+	slaUC := usecase.NewReceptionSLAUseCase(receptionRepo, authorizer, domain.ReceptionSLA{
+		Default: cfg.SLA.ReceptionDuration,
+		ByCity:  cfg.SLA.ReceptionDurationByCity,
+	})
+	storageUC := usecase.NewStorageUseCase(productRepo, assignmentRepo, authorizer, domain.StoragePolicy{
+		DefaultDays: cfg.Storage.DefaultDays,
+		Days:        cfg.Storage.PeriodDays,
+	})
+
+	eventPublisher, err := newEventPublisher(cfg.Events)
+	if err != nil {
+		log.Fatalf("Failed to create event publisher: %v", err)
+	}
+
+	metricsRegistry := metrics.NewRegistry()
+	scheduler := worker.NewScheduler(db.NewAdvisoryLocker(dbpool))
+	scheduler.Add(worker.NewOverdueJob(storageUC, cfg.Storage.CheckInterval))
+	scheduler.Add(worker.NewOverdueMetricsJob(storageUC, metricsRegistry, cfg.Storage.MetricsInterval))
+	scheduler.Add(worker.NewReceptionSLAJob(slaUC, metricsRegistry, cfg.SLA.CheckInterval))
+	scheduler.Add(worker.NewAutoCloseJob(receptionUC, cfg.Scheduler.ReceptionIdleTimeout, cfg.Scheduler.AutoCloseInterval))
+	// Подписки на вебхуки получают события через тот же relay, что и основной publisher.
+	relayPublisher := publisher.NewMulti(eventPublisher, worker.NewWebhookFanout(webhookRepo))
+	webhookDeliverer := worker.NewWebhookDeliverer(webhookRepo, cfg.Webhooks.Timeout, cfg.Webhooks.BatchSize, cfg.Webhooks.MaxAttempts)
+	scheduler.Add(worker.NewOutboxRelayJob(worker.NewOutboxRelay(outboxRepo, relayPublisher, cfg.Events.BatchSize), cfg.Events.RelayInterval))
+	scheduler.Add(worker.NewWebhookDeliveryJob(webhookDeliverer, cfg.Webhooks.DeliveryInterval))
+	scheduler.Start(context.Background())
+	schedulerUC := usecase.NewSchedulerUseCase(scheduler, authorizer)
 
-	authUC := usecase.NewAuthUseCase(userRepo, tokens, hasher)
-	pvzUC := usecase.NewPvzUseCase(pvzRepo)
-	receptionUC := usecase.NewReceptionUseCase(receptionRepo)
-	productUC := usecase.NewProductUseCase(productRepo)
+	go func() {
+		log.Printf("Metrics server running on port %s", cfg.Server.MetricsPort)
+		if err := metrics.Serve(cfg.Server.MetricsPort, metricsRegistry); err != nil {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
 
-	router := http.NewRouter(tokens, authUC, pvzUC, receptionUC, productUC)
+	router := http.NewRouter(http.RouterDeps{
+		Config:       cfg,
+		JWTGenerator: tokens,
+		IPLimiter:    ipLimiter,
+		EmailLimiter: emailLimiter,
+		CodeLimiter:  codeLimiter,
+		Users:        userRepo,
+		AuthUC:       authUC,
+		PvzUC:        pvzUC,
+		ReceptionUC:  receptionUC,
+		ProductUC:    productUC,
+		AdminUC:      adminUC,
+		ManifestUC:   manifestUC,
+		TransferUC:   transferUC,
+		StorageUC:    storageUC,
+		SchedulerUC:  schedulerUC,
+		CorrectionUC: correctionUC,
+		WebhookUC:    webhookUC,
+		LiveFeedUC:   liveFeedUC,
+		StatsUC:      statsUC,
+		ExportUC:     exportUC,
+		SLAUC:        slaUC,
+		CellUC:       storageCellUC,
+	})
 
 	log.Printf("HTTP server running on port %s", cfg.Server.HTTPPort)
 	http.Start(cfg, router)
 }
+
+// newEventPublisher выбирает, куда relay отправляет события из outbox.
+func newEventPublisher(cfg config.Events) (publisher.Publisher, error) {
+	switch cfg.Publisher {
+	case "", constants.EventPublisherStdout:
+		return publisher.NewWriterPublisher(os.Stdout), nil
+	case constants.EventPublisherFile:
+		return publisher.NewFilePublisher(cfg.FilePath)
+	case constants.EventPublisherWebhook:
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("events.webhookUrl is required for webhook publisher")
+		}
+		return publisher.NewWebhookPublisher(cfg.WebhookURL, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown events publisher %q", cfg.Publisher)
+	}
+}
@@ -0,0 +1,70 @@
+// Команда pvz-import создает ПВЗ из CSV так же, как POST /pvz/import: все строки одной транзакцией
+// или ни одной. Запускается из корня репозитория, чтобы найти config/ и .env.
+//
+//	go run ./cmd/pvz-import -file pvz.csv [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/config"
+	"github.com/aliskhannn/pvz-service/internal/repository/postgres"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"io"
+	"log"
+	"os"
+)
+
+func main() {
+	file := flag.String("file", "-", "CSV с колонками city, address, opening_hours (hours); - — stdin")
+	dryRun := flag.Bool("dry-run", false, "только проверить файл, ничего не создавая")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", *file, err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	dbpool, err := pgxpool.New(context.Background(), cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer dbpool.Close()
+
+	importer := usecase.NewPVZImporter(postgres.NewPVZRepository(dbpool), postgres.NewTxManager(dbpool))
+
+	result, err := importer.Import(context.Background(), input, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	if len(result.Errors) > 0 {
+		for _, rowErr := range result.Errors {
+			fmt.Fprintf(os.Stderr, "line %d: %s\n", rowErr.Line, rowErr.Message)
+		}
+		fmt.Fprintf(os.Stderr, "%d errors, nothing was imported\n", len(result.Errors))
+		os.Exit(1)
+	}
+
+	if result.DryRun {
+		fmt.Printf("dry run: %d pvz are valid\n", len(result.PVZs))
+		return
+	}
+
+	for _, pvz := range result.PVZs {
+		fmt.Printf("%s\t%s\t%s\n", pvz.Id, pvz.City, pvz.Address)
+	}
+	fmt.Printf("%d pvz created\n", len(result.PVZs))
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"fmt"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"strings"
+	"unicode"
+)
+
+// PasswordPolicy описывает требования к сложности пароля.
+type PasswordPolicy struct {
+	MinLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+// Validate проверяет пароль и возвращает ErrWeakPassword с перечнем нарушенных требований.
+func (p PasswordPolicy) Validate(password string) error {
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSpecial = true
+		}
+	}
+
+	var violations []string
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("at least %d characters", p.MinLength))
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, "an upper-case letter")
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, "a lower-case letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "a digit")
+	}
+	if p.RequireSpecial && !hasSpecial {
+		violations = append(violations, "a special character")
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: password must contain %s", appErr.ErrWeakPassword, strings.Join(violations, ", "))
+	}
+
+	return nil
+}
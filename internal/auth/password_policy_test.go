@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"testing"
+
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:      8,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSpecial: true,
+	}
+
+	tests := []struct {
+		name        string
+		password    string
+		expectedMsg string
+	}{
+		{
+			name:     "Strong password",
+			password: "Str0ng!Pass",
+		},
+		{
+			name:     "Unicode letters count",
+			password: "Пароль1!",
+		},
+		{
+			name:        "Too short",
+			password:    "Ab1!",
+			expectedMsg: "password is too weak: password must contain at least 8 characters",
+		},
+		{
+			name:        "Missing several classes",
+			password:    "onlylowercase",
+			expectedMsg: "password is too weak: password must contain an upper-case letter, a digit, a special character",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+
+			if tt.expectedMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, appErr.ErrWeakPassword)
+			assert.EqualError(t, err, tt.expectedMsg)
+		})
+	}
+}
+
+func TestPasswordPolicy_ZeroValueAcceptsAnything(t *testing.T) {
+	assert.NoError(t, PasswordPolicy{}.Validate("x"))
+}
@@ -0,0 +1,97 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+)
+
+// Authorizer проверяет права пользователя по его роли.
+type Authorizer interface {
+	// Authorize возвращает ErrUserRequired без пользователя и ErrPermissionDenied, если у роли нет права.
+	Authorize(user *domain.User, perm Permission) error
+	Can(role string, perm Permission) bool
+	RoleExists(role string) bool
+}
+
+type roleAuthorizer struct {
+	roles map[string]map[Permission]struct{}
+}
+
+// New создает Authorizer по набору ролей: роль -> список прав.
+func New(roles map[string][]Permission) Authorizer {
+	a := &roleAuthorizer{roles: make(map[string]map[Permission]struct{}, len(roles))}
+	for role, perms := range roles {
+		set := make(map[Permission]struct{}, len(perms))
+		for _, perm := range perms {
+			set[perm] = struct{}{}
+		}
+		a.roles[role] = set
+	}
+
+	return a
+}
+
+// Load читает роли и их права из БД. Роли меняются миграциями, поэтому загружаются один раз при старте.
+func Load(ctx context.Context, repo repository.RoleRepository) (Authorizer, error) {
+	rolePerms, err := repo.GetRolePermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+
+	roles := make(map[string][]Permission, len(rolePerms))
+	for role, perms := range rolePerms {
+		roles[role] = make([]Permission, 0, len(perms))
+		for _, perm := range perms {
+			roles[role] = append(roles[role], Permission(perm))
+		}
+	}
+
+	return New(roles), nil
+}
+
+// DefaultRoles повторяет роли из миграций. Используется в тестах.
+func DefaultRoles() map[string][]Permission {
+	return map[string][]Permission{
+		constants.UserRoleEmployee: {
+			PermPVZRead, PermReceptionOpen, PermReceptionClose, PermProductAdd, PermProductDelete,
+			PermProductIssue, PermProductReturn, PermManifestRead, PermTransferSend, PermTransferAccept,
+		},
+		constants.UserRoleModerator: {
+			PermPVZCreate, PermPVZRead, PermPVZAssign, PermReportRead, PermUserManage,
+			PermManifestCreate, PermManifestRead, PermSchedulerRead, PermReceptionCorrect,
+			PermWebhookManage, PermStatsRead,
+		},
+		constants.UserRoleAuditor: {
+			PermPVZRead, PermReportRead, PermManifestRead,
+		},
+		constants.UserRoleRegionalManager: {
+			PermPVZRead, PermPVZAssign, PermReportRead, PermManifestRead,
+		},
+	}
+}
+
+func (a *roleAuthorizer) Authorize(user *domain.User, perm Permission) error {
+	if user == nil {
+		return appErr.ErrUserRequired
+	}
+
+	if !a.Can(user.Role, perm) {
+		return fmt.Errorf("%w: %s", appErr.ErrPermissionDenied, perm)
+	}
+
+	return nil
+}
+
+func (a *roleAuthorizer) Can(role string, perm Permission) bool {
+	_, ok := a.roles[role][perm]
+	return ok
+}
+
+func (a *roleAuthorizer) RoleExists(role string) bool {
+	_, ok := a.roles[role]
+	return ok
+}
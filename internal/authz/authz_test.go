@@ -0,0 +1,85 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	authorizer := New(DefaultRoles())
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		perm      Permission
+		expectErr error
+	}{
+		{
+			name: "Employee opens reception",
+			user: &domain.User{Role: constants.UserRoleEmployee},
+			perm: PermReceptionOpen,
+		},
+		{
+			name:      "Employee creates pvz",
+			user:      &domain.User{Role: constants.UserRoleEmployee},
+			perm:      PermPVZCreate,
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name: "Moderator creates pvz",
+			user: &domain.User{Role: constants.UserRoleModerator},
+			perm: PermPVZCreate,
+		},
+		{
+			name: "Auditor reads reports",
+			user: &domain.User{Role: constants.UserRoleAuditor},
+			perm: PermReportRead,
+		},
+		{
+			name:      "Auditor deletes product",
+			user:      &domain.User{Role: constants.UserRoleAuditor},
+			perm:      PermProductDelete,
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name: "Regional manager assigns pvz",
+			user: &domain.User{Role: constants.UserRoleRegionalManager},
+			perm: PermPVZAssign,
+		},
+		{
+			name:      "Unknown role",
+			user:      &domain.User{Role: "admin"},
+			perm:      PermPVZRead,
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Nil user",
+			perm:      PermPVZRead,
+			expectErr: appErr.ErrUserRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(tt.user, tt.perm)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_RoleExists(t *testing.T) {
+	authorizer := New(DefaultRoles())
+
+	assert.True(t, authorizer.RoleExists(constants.UserRoleAuditor))
+	assert.True(t, authorizer.RoleExists(constants.UserRoleRegionalManager))
+	assert.False(t, authorizer.RoleExists("admin"))
+}
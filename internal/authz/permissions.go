@@ -0,0 +1,25 @@
+package authz
+
+type Permission string
+
+const (
+	PermPVZCreate        Permission = "pvz:create"
+	PermPVZRead          Permission = "pvz:read"
+	PermPVZAssign        Permission = "pvz:assign"
+	PermReceptionOpen    Permission = "reception:open"
+	PermReceptionClose   Permission = "reception:close"
+	PermReceptionCorrect Permission = "reception:correct"
+	PermProductAdd       Permission = "product:add"
+	PermProductDelete    Permission = "product:delete"
+	PermProductIssue     Permission = "product:issue"
+	PermProductReturn    Permission = "product:return"
+	PermManifestCreate   Permission = "manifest:create"
+	PermManifestRead     Permission = "manifest:read"
+	PermTransferSend     Permission = "transfer:send"
+	PermTransferAccept   Permission = "transfer:accept"
+	PermReportRead       Permission = "report:read"
+	PermUserManage       Permission = "user:manage"
+	PermSchedulerRead    Permission = "scheduler:read"
+	PermWebhookManage    Permission = "webhook:manage"
+	PermStatsRead        Permission = "stats:read"
+)
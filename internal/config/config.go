@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 	"os"
@@ -8,16 +10,32 @@ import (
 )
 
 type Config struct {
-	Server   `yaml:"server"`
-	JWT      `yaml:"jwt"`
-	Database `yaml:"database"`
+	Server    `yaml:"server"`
+	JWT       `yaml:"jwt"`
+	Database  `yaml:"database"`
+	RateLimit `yaml:"rate_limit"`
+	Lockout   `yaml:"lockout"`
+	Auth      `yaml:"auth"`
+	Storage   `yaml:"storage"`
+	Scheduler `yaml:"scheduler"`
+	Events    `yaml:"events"`
+	Webhooks  `yaml:"webhooks"`
+	Stats     `yaml:"stats"`
+	SLA       `yaml:"sla"`
+	Capacity  `yaml:"capacity"`
 }
 
 type Server struct {
-	HTTPPort    string `yaml:"http_port"`
-	GRPCPort    string `yaml:"grpc_port"`
-	MetricsPort string `yaml:"metrics_port"`
-	Mode        string `yaml:"mode"`
+	HTTPPort         string `yaml:"http_port"`
+	GRPCPort         string `yaml:"grpc_port"`
+	MetricsPort      string `yaml:"metrics_port"`
+	Mode             string `yaml:"mode"`
+	DummyLoginSecret string
+}
+
+// IsDebug сообщает, запущен ли сервис в отладочном или тестовом режиме.
+func (s Server) IsDebug() bool {
+	return s.Mode == constants.ServerModeDebug || s.Mode == constants.ServerModeTest
 }
 
 type JWT struct {
@@ -34,6 +52,90 @@ type Database struct {
 	SSLMode  string `yaml:"ssl_mode"`
 }
 
+// DSN собирает строку подключения к Postgres.
+func (d Database) DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s", d.User, d.Password, d.Host, d.Port, d.Name, d.SSLMode)
+}
+
+type RateLimit struct {
+	IPRequests         int           `yaml:"ip_requests"`
+	EmailRequests      int           `yaml:"email_requests"`
+	PickupCodeRequests int           `yaml:"pickup_code_requests"`
+	Period             time.Duration `yaml:"period"`
+}
+
+type Lockout struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	Duration    time.Duration `yaml:"duration"`
+}
+
+type Auth struct {
+	PasswordMinLength        int           `yaml:"password_min_length"`
+	PasswordRequireUpper     bool          `yaml:"password_require_upper"`
+	PasswordRequireLower     bool          `yaml:"password_require_lower"`
+	PasswordRequireDigit     bool          `yaml:"password_require_digit"`
+	PasswordRequireSpecial   bool          `yaml:"password_require_special"`
+	RequireEmailVerification bool          `yaml:"require_email_verification"`
+	VerificationTTL          time.Duration `yaml:"verification_ttl"`
+	VerificationURL          string        `yaml:"verification_url"`
+	BootstrapAdminEmail      string
+	BootstrapAdminPassword   string
+}
+
+// Storage задает сроки хранения товаров в днях; для типов, которых нет в PeriodDays, действует DefaultDays.
+// Просроченные товары отмечаются раз в CheckInterval, метрика просрочки обновляется раз в MetricsInterval.
+type Storage struct {
+	DefaultDays     int            `yaml:"default_days"`
+	PeriodDays      map[string]int `yaml:"period_days"`
+	CheckInterval   time.Duration  `yaml:"check_interval"`
+	MetricsInterval time.Duration  `yaml:"metrics_interval"`
+}
+
+// Scheduler настраивает автозакрытие приемок, в которые дольше ReceptionIdleTimeout не поступали товары.
+type Scheduler struct {
+	ReceptionIdleTimeout time.Duration `yaml:"reception_idle_timeout"`
+	AutoCloseInterval    time.Duration `yaml:"auto_close_interval"`
+}
+
+// Events настраивает публикацию доменных событий из outbox.
+// Publisher — stdout, file (FilePath) или webhook (WebhookURL).
+// StreamBuffer — сколько событий живой ленты ждут медленного клиента, прежде чем он будет отключен.
+type Events struct {
+	Publisher     string        `yaml:"publisher"`
+	FilePath      string        `yaml:"file_path"`
+	WebhookURL    string        `yaml:"webhook_url"`
+	RelayInterval time.Duration `yaml:"relay_interval"`
+	BatchSize     int           `yaml:"batch_size"`
+	StreamBuffer  int           `yaml:"stream_buffer"`
+}
+
+// Webhooks настраивает доставку событий подписчикам: после MaxAttempts неудач доставка переходит в dead.
+type Webhooks struct {
+	DeliveryInterval time.Duration `yaml:"delivery_interval"`
+	BatchSize        int           `yaml:"batch_size"`
+	MaxAttempts      int           `yaml:"max_attempts"`
+	Timeout          time.Duration `yaml:"timeout"`
+}
+
+// Stats настраивает аналитику: отчеты кешируются на CacheTTL, 0 отключает кеш.
+type Stats struct {
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+// SLA задает допустимую длительность входящей приемки: ReceptionDuration по умолчанию
+// и значения для отдельных городов. Метрики открытых приемок обновляются раз в CheckInterval.
+type SLA struct {
+	ReceptionDuration       time.Duration            `yaml:"reception_duration"`
+	ReceptionDurationByCity map[string]time.Duration `yaml:"reception_duration_by_city"`
+	CheckInterval           time.Duration            `yaml:"check_interval"`
+}
+
+// Capacity задает, что делать с товаром сверх вместимости ПВЗ: reject — отклонить,
+// warn — принять с пометкой over_capacity.
+type Capacity struct {
+	Policy string `yaml:"policy"`
+}
+
 func LoadConfig() (*Config, error) {
 	_ = godotenv.Load()
 
@@ -49,6 +151,9 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	cfg.Server.DummyLoginSecret = os.Getenv("DUMMY_LOGIN_SECRET")
+	cfg.Auth.BootstrapAdminEmail = os.Getenv("BOOTSTRAP_ADMIN_EMAIL")
+	cfg.Auth.BootstrapAdminPassword = os.Getenv("BOOTSTRAP_ADMIN_PASSWORD")
 	cfg.JWT.Secret = os.Getenv("JWT_SECRET")
 	cfg.Database.User = os.Getenv("DB_USER")
 	cfg.Database.Password = os.Getenv("DB_PASSWORD")
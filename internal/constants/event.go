@@ -0,0 +1,17 @@
+package constants
+
+const (
+	EventReceptionOpened    = "reception.opened"
+	EventReceptionClosed    = "reception.closed"
+	EventReceptionReopened  = "reception.reopened"
+	EventProductAdded       = "product.added"
+	EventProductDeleted     = "product.deleted"
+	EventTransferDispatched = "transfer.dispatched"
+	EventTransferReceived   = "transfer.received"
+)
+
+const (
+	EventPublisherStdout  = "stdout"
+	EventPublisherFile    = "file"
+	EventPublisherWebhook = "webhook"
+)
@@ -0,0 +1,6 @@
+package constants
+
+const (
+	ManifestStatusPending = "pending"
+	ManifestStatusLinked  = "linked"
+)
@@ -5,3 +5,17 @@ const (
 	ProductsTypeCloth      = "одежда"
 	ProductTypeShoes       = "обувь"
 )
+
+// Жизненный цикл товара: received -> stored -> issued / returned_to_sender.
+// При перемещении между ПВЗ: stored -> in_transit -> received в приемке ПВЗ назначения.
+// В возвратной партии: stored -> returning -> returned_to_sender после закрытия партии.
+// Исправление закрытой приемки: stored -> removed_by_correction, запись о товаре сохраняется.
+const (
+	ProductStatusReceived            = "received"
+	ProductStatusStored              = "stored"
+	ProductStatusInTransit           = "in_transit"
+	ProductStatusReturning           = "returning"
+	ProductStatusIssued              = "issued"
+	ProductStatusReturnedToSender    = "returned_to_sender"
+	ProductStatusRemovedByCorrection = "removed_by_correction"
+)
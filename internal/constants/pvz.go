@@ -5,3 +5,9 @@ const (
 	PVZCitySaintPetersburg = "Санкт-Петербург"
 	PVZCityKazan           = "Казань"
 )
+
+// Политика приема товаров сверх вместимости ПВЗ.
+const (
+	CapacityPolicyReject = "reject"
+	CapacityPolicyWarn   = "warn"
+)
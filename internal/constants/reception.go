@@ -4,3 +4,16 @@ const (
 	ReceptionStatusInProgress = "in_progress"
 	ReceptionStatusClose      = "close"
 )
+
+// Приемка бывает входящей (товары поступают в ПВЗ) и возвратной (товары отправляются обратно на склад).
+const (
+	ReceptionDirectionInbound = "inbound"
+	ReceptionDirectionReturn  = "return"
+)
+
+// Действия в истории исправлений закрытой приемки.
+const (
+	ReceptionAuditReopen        = "reopen"
+	ReceptionAuditAddProduct    = "add_product"
+	ReceptionAuditRemoveProduct = "remove_product"
+)
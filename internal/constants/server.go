@@ -0,0 +1,7 @@
+package constants
+
+const (
+	ServerModeDebug   = "debug"
+	ServerModeTest    = "test"
+	ServerModeRelease = "release"
+)
@@ -0,0 +1,11 @@
+package constants
+
+const (
+	StatsGroupByCity = "city"
+	StatsGroupByPVZ  = "pvz"
+	StatsGroupByType = "type"
+
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week"
+	StatsBucketMonth = "month"
+)
@@ -0,0 +1,6 @@
+package constants
+
+const (
+	TransferStatusDispatched = "dispatched"
+	TransferStatusReceived   = "received"
+)
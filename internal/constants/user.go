@@ -1,6 +1,8 @@
 package constants
 
 const (
-	UserRoleEmployee  = "employee"
-	UserRoleModerator = "moderator"
+	UserRoleEmployee        = "employee"
+	UserRoleModerator       = "moderator"
+	UserRoleAuditor         = "auditor"
+	UserRoleRegionalManager = "regional_manager"
 )
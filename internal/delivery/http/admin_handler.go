@@ -0,0 +1,232 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+)
+
+type AdminHandler struct {
+	adminUseCase usecase.AdminUseCase
+}
+
+func NewAdminHandler(adminUseCase usecase.AdminUseCase) *AdminHandler {
+	return &AdminHandler{
+		adminUseCase: adminUseCase,
+	}
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=employee moderator auditor regional_manager"`
+}
+
+type ResetPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+type ResetPasswordResponse struct {
+	Password string `json:"password"`
+}
+
+type UserPVZsResponse struct {
+	PVZIds []uuid.UUID `json:"pvz_ids"`
+}
+
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	query := r.URL.Query()
+
+	page := 1
+	limit := 10
+	var err error
+
+	if pageStr := query.Get("page"); pageStr != "" {
+		page, err = strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid page")
+			return
+		}
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 100 {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	users, err := h.adminUseCase.ListUsers(r.Context(), user, query.Get("search"), page, limit)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, users)
+}
+
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, userId, ok := h.actorAndTarget(w, r)
+	if !ok {
+		return
+	}
+
+	target, err := h.adminUseCase.GetUser(r.Context(), user, userId)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, target)
+}
+
+func (h *AdminHandler) ChangeRole(w http.ResponseWriter, r *http.Request) {
+	user, userId, ok := h.actorAndTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var req ChangeRoleRequest
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	if err := h.adminUseCase.ChangeUserRole(r.Context(), user, userId, req.Role); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, true)
+}
+
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, false)
+}
+
+func (h *AdminHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	user, userId, ok := h.actorAndTarget(w, r)
+	if !ok {
+		return
+	}
+
+	// Тело необязательно: без пароля будет сгенерирован временный.
+	var req ResetPasswordRequest
+	if r.ContentLength != 0 {
+		if err := request.DecodeJSON(r, &req); err != nil {
+			response.WriteRequestError(w, err)
+			return
+		}
+	}
+
+	password, err := h.adminUseCase.ResetUserPassword(r.Context(), user, userId, req.Password)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, ResetPasswordResponse{Password: password})
+}
+
+func (h *AdminHandler) GetUserPVZs(w http.ResponseWriter, r *http.Request) {
+	user, userId, ok := h.actorAndTarget(w, r)
+	if !ok {
+		return
+	}
+
+	pvzIds, err := h.adminUseCase.GetUserPVZs(r.Context(), user, userId)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, UserPVZsResponse{PVZIds: pvzIds})
+}
+
+func (h *AdminHandler) AssignPVZ(w http.ResponseWriter, r *http.Request) {
+	user, userId, pvzId, ok := h.actorTargetAndPVZ(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.adminUseCase.AssignPVZ(r.Context(), user, userId, pvzId); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) UnassignPVZ(w http.ResponseWriter, r *http.Request) {
+	user, userId, pvzId, ok := h.actorTargetAndPVZ(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.adminUseCase.UnassignPVZ(r.Context(), user, userId, pvzId); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	user, userId, ok := h.actorAndTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.adminUseCase.SetUserDisabled(r.Context(), user, userId, disabled); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) actorAndTarget(w http.ResponseWriter, r *http.Request) (*domain.User, uuid.UUID, bool) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return nil, uuid.Nil, false
+	}
+
+	userId, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return nil, uuid.Nil, false
+	}
+
+	return user, userId, true
+}
+
+func (h *AdminHandler) actorTargetAndPVZ(w http.ResponseWriter, r *http.Request) (*domain.User, uuid.UUID, uuid.UUID, bool) {
+	user, userId, ok := h.actorAndTarget(w, r)
+	if !ok {
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+
+	return user, userId, pvzId, true
+}
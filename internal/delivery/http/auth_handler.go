@@ -1,30 +1,42 @@
 package http
 
 import (
-	"encoding/json"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
 	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
 	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/ratelimit"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
 	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/google/uuid"
 	"net/http"
+	"strings"
 )
 
 type AuthHandler struct {
-	authUseCase usecase.AuthUseCase
+	authUseCase  usecase.AuthUseCase
+	emailLimiter ratelimit.Limiter
 }
 
-func NewAuthHandler(authUseCase usecase.AuthUseCase) *AuthHandler {
+func NewAuthHandler(authUseCase usecase.AuthUseCase, emailLimiter ratelimit.Limiter) *AuthHandler {
 	return &AuthHandler{
-		authUseCase: authUseCase,
+		authUseCase:  authUseCase,
+		emailLimiter: emailLimiter,
 	}
 }
 
 type DummyLoginRequest struct {
-	Role string `json:"role"`
+	Role   string      `json:"role" validate:"required,oneof=employee moderator auditor regional_manager"`
+	PVZIds []uuid.UUID `json:"pvz_ids"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
 }
 
 type TokenResponse struct {
@@ -38,18 +50,12 @@ func (h *AuthHandler) DummyLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req DummyLoginRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		response.WriteJSONError(w, http.StatusBadRequest, "invalid request")
-		return
-	}
-
-	if req.Role == "" {
-		response.WriteJSONError(w, http.StatusBadRequest, "role is required")
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
 		return
 	}
 
-	token, err := h.authUseCase.DummyLogin(r.Context(), req.Role)
+	token, err := h.authUseCase.DummyLogin(r.Context(), req.Role, req.PVZIds)
 	if err != nil {
 		status := response.MapErrorToStatusCode(err)
 		response.WriteJSONError(w, status, err.Error())
@@ -66,21 +72,18 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var loginReq LoginRequest
-	err := json.NewDecoder(r.Body).Decode(&loginReq)
-	if err != nil {
-		response.WriteJSONError(w, http.StatusBadRequest, "invalid request")
+	if err := request.DecodeJSON(r, &loginReq); err != nil {
+		response.WriteRequestError(w, err)
 		return
 	}
 
-	if loginReq.Email == "" || loginReq.Password == "" {
-		response.WriteJSONError(w, http.StatusBadRequest, "email and password are required")
+	if !h.allowEmail(w, r, "login", loginReq.Email) {
 		return
 	}
 
 	token, err := h.authUseCase.Login(r.Context(), loginReq.Email, loginReq.Password)
 	if err != nil {
-		status := response.MapErrorToStatusCode(err)
-		response.WriteJSONError(w, status, err.Error())
+		response.WriteError(w, err)
 		return
 	}
 
@@ -94,18 +97,19 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var user domain.User
-	err := json.NewDecoder(r.Body).Decode(&user)
-	if err != nil {
-		response.WriteJSONError(w, http.StatusBadRequest, "invalid request")
+	if err := request.DecodeJSON(r, &user); err != nil {
+		response.WriteRequestError(w, err)
 		return
 	}
 
-	if user.Email == "" || user.Password == "" || user.Role == "" {
-		response.WriteJSONError(w, http.StatusBadRequest, "email, password and role are required")
+	if !h.allowEmail(w, r, "register", user.Email) {
 		return
 	}
 
-	err = h.authUseCase.Register(r.Context(), &user)
+	// Анонимный запрос допустим: actor нужен только для создания модераторов.
+	actor, _ := middleware.GetUserFromContext(r.Context())
+
+	err := h.authUseCase.Register(r.Context(), &user, actor)
 	if err != nil {
 		status := response.MapErrorToStatusCode(err)
 		response.WriteJSONError(w, status, err.Error())
@@ -114,3 +118,62 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	response.WriteJSONResponse(w, http.StatusCreated, user.Email)
 }
+
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.WriteJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	err := h.authUseCase.VerifyEmail(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ResendVerification всегда отвечает 202, чтобы по ответу нельзя было проверить, зарегистрирован ли email.
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.WriteJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	var req ResendVerificationRequest
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	if !h.allowEmail(w, r, "resend_verification", req.Email) {
+		return
+	}
+
+	err := h.authUseCase.ResendVerification(r.Context(), req.Email)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// allowEmail ограничивает частоту попыток для одного email независимо от IP клиента.
+func (h *AuthHandler) allowEmail(w http.ResponseWriter, r *http.Request, action, email string) bool {
+	key := action + ":" + strings.ToLower(email)
+
+	allowed, retryAfter, err := h.emailLimiter.Allow(r.Context(), key)
+	if err != nil {
+		response.WriteJSONError(w, http.StatusInternalServerError, appErr.ErrInternal.Error())
+		return false
+	}
+
+	if !allowed {
+		response.WriteTooManyRequests(w, retryAfter)
+		return false
+	}
+
+	return true
+}
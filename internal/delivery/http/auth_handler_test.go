@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/ratelimit"
 	"github.com/aliskhannn/pvz-service/internal/usecase"
 	"github.com/aliskhannn/pvz-service/internal/usecase/mocks"
 	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
@@ -16,14 +20,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestAuthHandler_DummyLogin(t *testing.T) {
 	userRepo := &repository_mocks.MockUserRepository{}
 	tokens := &mocks.MockJWTGenerator{}
 	hasher := &mocks.MockPasswordHasher{}
-	authUC := usecase.NewAuthUseCase(userRepo, tokens, hasher)
-	handler := NewAuthHandler(authUC)
+	authUC := usecase.NewAuthUseCase(userRepo, tokens, hasher, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), usecase.AuthOptions{})
+	handler := NewAuthHandler(authUC, ratelimit.NewMemoryLimiter(100, time.Minute))
 
 	tests := []struct {
 		name           string
@@ -45,14 +50,19 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 			name:           "Empty role",
 			body:           DummyLoginRequest{Role: ""},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"error": "role is required"},
+			expectedBody: response.ErrorResponse{
+				Message: "validation error",
+				Errors:  []request.FieldError{{Field: "role", Message: "is required"}},
+			},
 		},
 		{
 			name:           "Invalid role",
 			body:           DummyLoginRequest{Role: "invalid"},
-			tokenErr:       appErr.ErrInvalidRole,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"error": appErr.ErrInvalidRole.Error()},
+			expectedBody: response.ErrorResponse{
+				Message: "validation error",
+				Errors:  []request.FieldError{{Field: "role", Message: "must be one of: employee moderator auditor regional_manager"}},
+			},
 		},
 	}
 
@@ -63,7 +73,7 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			if tt.token != "" || tt.tokenErr != nil {
-				tokens.On("CreateToken", mock.Anything, "employee").
+				tokens.On("CreateDummyToken", mock.Anything, "employee", mock.Anything).
 					Return(tt.token, tt.tokenErr).
 					Once()
 			}
@@ -71,9 +81,8 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 			handler.DummyLogin(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
-			var resp interface{}
-			json.NewDecoder(w.Body).Decode(&resp)
-			assert.Equal(t, tt.expectedBody, resp)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
 		})
 	}
 }
@@ -82,8 +91,8 @@ func TestAuthHandler_Login(t *testing.T) {
 	userRepo := &repository_mocks.MockUserRepository{}
 	tokens := &mocks.MockJWTGenerator{}
 	hasher := &mocks.MockPasswordHasher{}
-	authUC := usecase.NewAuthUseCase(userRepo, tokens, hasher)
-	handler := NewAuthHandler(authUC)
+	authUC := usecase.NewAuthUseCase(userRepo, tokens, hasher, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), usecase.AuthOptions{})
+	handler := NewAuthHandler(authUC, ratelimit.NewMemoryLimiter(100, time.Minute))
 
 	tests := []struct {
 		name           string
@@ -111,22 +120,28 @@ func TestAuthHandler_Login(t *testing.T) {
 			name:           "Missing fields",
 			body:           LoginRequest{Email: "", Password: ""},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"error": "email and password are required"},
+			expectedBody: response.ErrorResponse{
+				Message: "validation error",
+				Errors: []request.FieldError{
+					{Field: "email", Message: "is required"},
+					{Field: "password", Message: "is required"},
+				},
+			},
 		},
 		{
 			name:           "User not found",
 			body:           LoginRequest{Email: "test@example.com", Password: "password"},
 			userErr:        pgx.ErrNoRows,
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"error": appErr.ErrGettingUser.Error()},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   response.ErrorResponse{Message: appErr.ErrGettingUser.Error()},
 		},
 		{
 			name:           "Invalid password",
 			body:           LoginRequest{Email: "test@example.com", Password: "password"},
 			user:           &domain.User{Id: uuid.New(), Email: "test@example.com", Password: "hashed", Role: "employee"},
 			hashErr:        errors.New("invalid password"),
-			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   map[string]string{"error": appErr.ErrInvalidAuthFields.Error()},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.ErrorResponse{Message: appErr.ErrInvalidAuthFields.Error()},
 		},
 	}
 
@@ -145,13 +160,15 @@ func TestAuthHandler_Login(t *testing.T) {
 			if tt.token != "" || tt.tokenErr != nil {
 				tokens.On("CreateToken", mock.Anything, mock.Anything).Return(tt.token, tt.tokenErr).Once()
 			}
+			if tt.token != "" && tt.tokenErr == nil {
+				userRepo.On("UpdateLastLogin", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			}
 
 			handler.Login(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
-			var resp interface{}
-			json.NewDecoder(w.Body).Decode(&resp)
-			assert.Equal(t, tt.expectedBody, resp)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
 		})
 	}
 }
@@ -160,8 +177,8 @@ func TestAuthHandler_Register(t *testing.T) {
 	userRepo := &repository_mocks.MockUserRepository{}
 	tokens := &mocks.MockJWTGenerator{}
 	hasher := &mocks.MockPasswordHasher{}
-	authUC := usecase.NewAuthUseCase(userRepo, tokens, hasher)
-	handler := NewAuthHandler(authUC)
+	authUC := usecase.NewAuthUseCase(userRepo, tokens, hasher, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), usecase.AuthOptions{})
+	handler := NewAuthHandler(authUC, ratelimit.NewMemoryLimiter(100, time.Minute))
 
 	tests := []struct {
 		name           string
@@ -184,7 +201,14 @@ func TestAuthHandler_Register(t *testing.T) {
 			name:           "Missing fields",
 			body:           domain.User{Email: "", Password: "", Role: ""},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"error": "email, password and role are required"},
+			expectedBody: response.ErrorResponse{
+				Message: "validation error",
+				Errors: []request.FieldError{
+					{Field: "email", Message: "is required"},
+					{Field: "password", Message: "is required"},
+					{Field: "role", Message: "is required"},
+				},
+			},
 		},
 		{
 			name:           "User exists",
@@ -192,15 +216,16 @@ func TestAuthHandler_Register(t *testing.T) {
 			existingUser:   &domain.User{Email: "test@example.com"},
 			existingErr:    nil,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"error": appErr.ErrUserEmailExists.Error()},
+			expectedBody:   response.ErrorResponse{Message: appErr.ErrUserEmailExists.Error()},
 		},
 		{
 			name:           "Invalid role",
 			body:           domain.User{Email: "test@example.com", Password: "password", Role: "invalid"},
-			existingErr:    pgx.ErrNoRows,
-			createErr:      appErr.ErrInvalidRole,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"error": appErr.ErrInvalidRole.Error()},
+			expectedBody: response.ErrorResponse{
+				Message: "validation error",
+				Errors:  []request.FieldError{{Field: "role", Message: "must be one of: employee moderator auditor regional_manager"}},
+			},
 		},
 	}
 
@@ -220,9 +245,8 @@ func TestAuthHandler_Register(t *testing.T) {
 			handler.Register(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
-			var resp interface{}
-			json.NewDecoder(w.Body).Decode(&resp)
-			assert.Equal(t, tt.expectedBody, resp)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
 		})
 	}
 }
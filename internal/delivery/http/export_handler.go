@@ -0,0 +1,152 @@
+package http
+
+import (
+	"encoding/csv"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// exportFlushRows — через сколько строк выгрузка сбрасывается клиенту.
+const exportFlushRows = 1000
+
+// utf8BOM нужен, чтобы Excel открыл кириллицу в CSV без выбора кодировки.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+var receptionExportHeader = []string{
+	"reception_id", "reception_status", "reception_opened_at", "reception_closed_at",
+	"pvz_id", "city", "address",
+	"product_id", "product_type", "barcode", "product_status", "product_added_at",
+}
+
+type ExportHandler struct {
+	exportUseCase usecase.ExportUseCase
+}
+
+func NewExportHandler(exportUseCase usecase.ExportUseCase) *ExportHandler {
+	return &ExportHandler{
+		exportUseCase: exportUseCase,
+	}
+}
+
+func (h *ExportHandler) ExportReceptions(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	query := r.URL.Query()
+
+	var from, to time.Time
+	var err error
+
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err = time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid 'from' date, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	if toStr := query.Get("to"); toStr != "" {
+		to, err = time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid 'to' date, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+
+	// Заголовки отправляются с первой строкой, чтобы ошибки проверки и доступа успели уйти обычным JSON.
+	var writer *csv.Writer
+	start := func() error {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="receptions.csv"`)
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := w.Write(utf8BOM); err != nil {
+			return err
+		}
+
+		writer = csv.NewWriter(w)
+		return writer.Write(receptionExportHeader)
+	}
+
+	rows := 0
+	err = h.exportUseCase.ExportReceptions(r.Context(), from, to, query.Get("city"), user, func(row *domain.ReceptionExportRow) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Write(receptionExportRecord(row)); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushRows == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			_ = rc.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		if writer == nil {
+			response.WriteError(w, err)
+			return
+		}
+
+		// Часть файла уже отправлена: обрываем соединение, чтобы клиент не принял неполную выгрузку за целую.
+		panic(http.ErrAbortHandler)
+	}
+
+	if writer == nil {
+		if err := start(); err != nil {
+			return
+		}
+	}
+	writer.Flush()
+}
+
+func receptionExportRecord(row *domain.ReceptionExportRow) []string {
+	closedAt := ""
+	if row.ReceptionClosedAt != nil {
+		closedAt = row.ReceptionClosedAt.Format(time.DateTime)
+	}
+
+	return []string{
+		row.ReceptionId.String(),
+		row.ReceptionStatus,
+		row.ReceptionOpenedAt.Format(time.DateTime),
+		closedAt,
+		row.PVZId.String(),
+		row.City,
+		csvSafe(row.Address),
+		row.ProductId.String(),
+		row.ProductType,
+		csvSafe(row.Barcode),
+		row.ProductStatus,
+		row.ProductAddedAt.Format(time.DateTime),
+	}
+}
+
+// csvSafe не дает табличному редактору принять введенное пользователем значение за формулу:
+// к значениям, начинающимся с =, +, -, @, табуляции или возврата каретки, добавляется апостроф.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
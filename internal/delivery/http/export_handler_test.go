@@ -0,0 +1,129 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/usecase/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportHandler_ExportReceptions(t *testing.T) {
+	openedAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	rows := []*domain.ReceptionExportRow{
+		{
+			ReceptionId:       uuid.New(),
+			ReceptionStatus:   "close",
+			ReceptionOpenedAt: openedAt,
+			ReceptionClosedAt: &openedAt,
+			PVZId:             uuid.New(),
+			City:              "Казань",
+			ProductId:         uuid.New(),
+			ProductType:       "обувь",
+			ProductStatus:     "stored",
+			ProductAddedAt:    openedAt,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		target         string
+		rows           []*domain.ReceptionExportRow
+		ucErr          error
+		expectedStatus int
+		expectedLines  int
+		expectAbort    bool
+	}{
+		{
+			name:           "Valid request",
+			target:         "/exports/receptions?from=2025-04-01&to=2025-04-30&city=Казань",
+			rows:           rows,
+			expectedStatus: http.StatusOK,
+			expectedLines:  2,
+		},
+		{
+			name:           "Empty export still has header",
+			target:         "/exports/receptions",
+			expectedStatus: http.StatusOK,
+			expectedLines:  1,
+		},
+		{
+			name:           "Invalid date",
+			target:         "/exports/receptions?from=01.04.2025",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Usecase error before first row",
+			target:         "/exports/receptions",
+			ucErr:          appErr.ErrInvalidCity,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Error after first row aborts response",
+			target:      "/exports/receptions",
+			rows:        rows,
+			ucErr:       errors.New("db error"),
+			expectAbort: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &mocks.MockExportUseCase{Rows: tt.rows}
+			mockUseCase.On("ExportReceptions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.ucErr)
+			handler := NewExportHandler(mockUseCase)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserContextKey, &domain.User{Role: "auditor"}))
+			rr := httptest.NewRecorder()
+
+			if tt.expectAbort {
+				assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+					handler.ExportReceptions(rr, req)
+				})
+				return
+			}
+
+			handler.ExportReceptions(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+			body := strings.TrimPrefix(rr.Body.String(), string(utf8BOM))
+			records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+			assert.NoError(t, err)
+			assert.Len(t, records, tt.expectedLines)
+			assert.Equal(t, receptionExportHeader, records[0])
+		})
+	}
+}
+
+func TestReceptionExportRecord_EscapesFormulas(t *testing.T) {
+	row := &domain.ReceptionExportRow{
+		Address: `=HYPERLINK("http://evil","click")`,
+		Barcode: "-2+3",
+	}
+
+	record := receptionExportRecord(row)
+
+	assert.Equal(t, `'=HYPERLINK("http://evil","click")`, record[6])
+	assert.Equal(t, "'-2+3", record[9])
+
+	// Обычные значения не меняются.
+	assert.Equal(t, "ул. Ленина, 1", csvSafe("ул. Ленина, 1"))
+	assert.Equal(t, "", csvSafe(""))
+	assert.Equal(t, "'@SUM(A1)", csvSafe("@SUM(A1)"))
+}
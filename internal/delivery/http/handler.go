@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/aliskhannn/pvz-service/internal/config"
 	"github.com/aliskhannn/pvz-service/internal/domain/token"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/ratelimit"
 	"github.com/aliskhannn/pvz-service/internal/middleware"
 	"github.com/aliskhannn/pvz-service/internal/usecase"
 	"github.com/go-chi/chi/v5"
@@ -11,37 +12,159 @@ import (
 	"net/http"
 )
 
-func NewRouter(
-	jwtGenerator token.Generator,
-	authUC usecase.AuthUseCase,
-	pvzUC usecase.PvzUseCase,
-	receptionUC usecase.ReceptionUseCase,
-	productUC usecase.ProductUseCase,
-) http.Handler {
+type RouterDeps struct {
+	Config       *config.Config
+	JWTGenerator token.Generator
+	IPLimiter    ratelimit.Limiter
+	EmailLimiter ratelimit.Limiter
+	CodeLimiter  ratelimit.Limiter
+	Users        middleware.UserProvider
+	AuthUC       usecase.AuthUseCase
+	PvzUC        usecase.PvzUseCase
+	ReceptionUC  usecase.ReceptionUseCase
+	ProductUC    usecase.ProductUseCase
+	AdminUC      usecase.AdminUseCase
+	ManifestUC   usecase.ManifestUseCase
+	TransferUC   usecase.TransferUseCase
+	StorageUC    usecase.StorageUseCase
+	SchedulerUC  usecase.SchedulerUseCase
+	CorrectionUC usecase.ReceptionCorrectionUseCase
+	WebhookUC    usecase.WebhookUseCase
+	LiveFeedUC   usecase.LiveFeedUseCase
+	StatsUC      usecase.StatsUseCase
+	ExportUC     usecase.ExportUseCase
+	SLAUC        usecase.ReceptionSLAUseCase
+	CellUC       usecase.StorageCellUseCase
+}
+
+func NewRouter(deps RouterDeps) http.Handler {
 	r := chi.NewRouter()
 
-	authHandler := NewAuthHandler(authUC)
-	pvzHandler := NewPVZHandler(pvzUC)
-	receptionHandler := NewReceptionHandler(receptionUC)
-	productHandler := NewProductHandler(productUC)
+	authHandler := NewAuthHandler(deps.AuthUC, deps.EmailLimiter)
+	pvzHandler := NewPVZHandler(deps.PvzUC)
+	receptionHandler := NewReceptionHandler(deps.ReceptionUC)
+	productHandler := NewProductHandler(deps.ProductUC, deps.CodeLimiter)
+	adminHandler := NewAdminHandler(deps.AdminUC)
+	manifestHandler := NewManifestHandler(deps.ManifestUC)
+	transferHandler := NewTransferHandler(deps.TransferUC)
+	storageHandler := NewStorageHandler(deps.StorageUC)
+	schedulerHandler := NewSchedulerHandler(deps.SchedulerUC)
+	correctionHandler := NewReceptionCorrectionHandler(deps.CorrectionUC)
+	webhookHandler := NewWebhookHandler(deps.WebhookUC)
+	liveFeedHandler := NewLiveFeedHandler(deps.LiveFeedUC)
+	statsHandler := NewStatsHandler(deps.StatsUC)
+	exportHandler := NewExportHandler(deps.ExportUC)
+	slaHandler := NewReceptionSLAHandler(deps.SLAUC)
+	cellHandler := NewStorageCellHandler(deps.CellUC)
+	docsHandler := NewDocsHandler()
+
+	authConfig := middleware.AuthConfig{
+		AllowDummy: deps.Config.Server.IsDebug(),
+		Users:      deps.Users,
+	}
+	authMiddleware := middleware.AuthMiddleware(deps.JWTGenerator, authConfig)
+
+	r.Get("/openapi.yaml", docsHandler.OpenAPISpec)
+	r.Get("/swagger", docsHandler.SwaggerUI)
+
+	// Тестовая авторизация доступна только в режимах debug/test.
+	if deps.Config.Server.IsDebug() {
+		r.With(middleware.SharedSecret("X-Dummy-Login-Secret", deps.Config.Server.DummyLoginSecret)).
+			Post("/dummyLogin", authHandler.DummyLogin)
+	}
+	r.With(
+		middleware.RateLimitByIP(deps.IPLimiter, "register"),
+		middleware.OptionalAuthMiddleware(deps.JWTGenerator, authConfig),
+	).Post("/register", authHandler.Register)
+	r.With(middleware.RateLimitByIP(deps.IPLimiter, "verify")).Get("/verify-email", authHandler.VerifyEmail)
+	r.With(middleware.RateLimitByIP(deps.IPLimiter, "resend_verification")).Post("/resend-verification", authHandler.ResendVerification)
+	r.With(middleware.RateLimitByIP(deps.IPLimiter, "login")).Post("/login", authHandler.Login)
+
+	r.With(middleware.RateLimitByIP(deps.IPLimiter, "orders")).Get("/orders/{productId}", productHandler.GetPickupInfo)
 
-	r.Post("/dummyLogin", authHandler.DummyLogin)
-	r.Post("/register", authHandler.Register)
-	r.Post("/login", authHandler.Login)
+	// Статический путь объявлен отдельно, иначе его перехватит маршрут /pvz/{pvzId}.
+	r.With(authMiddleware).Post("/pvz/import", pvzHandler.ImportPVZs)
 
-	r.With(middleware.AuthMiddleware(jwtGenerator)).Route("/pvz", func(r chi.Router) {
+	r.With(authMiddleware).Route("/pvz", func(r chi.Router) {
 		r.Post("/", pvzHandler.CreatePVZ)
 		r.Get("/", pvzHandler.GetAllPVZsWithReceptions)
 	})
 
-	r.With(middleware.AuthMiddleware(jwtGenerator)).Route("/pvz/{pvzId}", func(r chi.Router) {
+	// EventSource в браузере не передает заголовки, поэтому токен живой ленты можно передать в access_token.
+	streamAuth := chi.Chain(middleware.TokenFromQuery("access_token"), authMiddleware)
+	r.With(streamAuth...).Get("/pvz/{pvzId}/events", liveFeedHandler.StreamPVZEvents)
+	r.With(streamAuth...).Get("/events", liveFeedHandler.StreamCityEvents)
+
+	r.With(authMiddleware).Route("/pvz/{pvzId}", func(r chi.Router) {
 		r.Post("/close_last_reception", receptionHandler.CloseLastReception)
+		r.Post("/close_last_return", receptionHandler.CloseLastReturn)
 		r.Post("/delete_last_product", productHandler.DeleteLatProductFromReception)
+		r.Get("/inventory", productHandler.GetInventory)
+		r.Get("/overdue", storageHandler.GetOverdueProducts)
+		r.Put("/capacity", pvzHandler.SetPVZCapacity)
+		r.Post("/cells", cellHandler.CreateCells)
+		r.Get("/cells", cellHandler.GetCells)
+		r.Delete("/cells/{cellId}", cellHandler.DeleteCell)
+		r.Get("/locate", cellHandler.LocateProduct)
+		r.Get("/transfers", transferHandler.GetPVZTransfers)
+	})
+
+	r.With(authMiddleware).Route("/receptions", func(r chi.Router) {
+		r.Post("/", receptionHandler.CreateReception)
+		r.Get("/sla_breaches", slaHandler.GetSLABreaches)
+		r.Get("/{receptionId}/discrepancy_report", manifestHandler.GetDiscrepancyReport)
+		r.Post("/{receptionId}/reopen", correctionHandler.ReopenReception)
+		r.Post("/{receptionId}/products", correctionHandler.AddProduct)
+		r.Post("/{receptionId}/products/{productId}/remove", correctionHandler.RemoveProduct)
+		r.Get("/{receptionId}/audit", correctionHandler.GetAudit)
+	})
+
+	r.With(authMiddleware).Route("/returns", func(r chi.Router) {
+		r.Post("/", receptionHandler.CreateReturnReception)
+		r.Post("/products", receptionHandler.AddProductToReturn)
+	})
+
+	r.With(authMiddleware).Route("/transfers", func(r chi.Router) {
+		r.Post("/", transferHandler.CreateTransfer)
+		r.Get("/{transferId}", transferHandler.GetTransfer)
+		r.Post("/{transferId}/receive", transferHandler.ReceiveTransfer)
+	})
+
+	r.With(authMiddleware).Route("/manifests", func(r chi.Router) {
+		r.Post("/", manifestHandler.CreateManifest)
+		r.Get("/{manifestId}", manifestHandler.GetManifest)
+	})
+
+	r.With(authMiddleware).Route("/products", func(r chi.Router) {
+		r.Post("/", productHandler.AddProductToReception)
+		r.Post("/{productId}/issue", productHandler.IssueProduct)
+		r.Post("/{productId}/return_to_sender", productHandler.ReturnProductToSender)
+	})
+
+	r.With(authMiddleware).Route("/admin/users", func(r chi.Router) {
+		r.Get("/", adminHandler.ListUsers)
+		r.Get("/{userId}", adminHandler.GetUser)
+		r.Put("/{userId}/role", adminHandler.ChangeRole)
+		r.Post("/{userId}/disable", adminHandler.DisableUser)
+		r.Post("/{userId}/enable", adminHandler.EnableUser)
+		r.Post("/{userId}/reset_password", adminHandler.ResetPassword)
+		r.Get("/{userId}/pvz", adminHandler.GetUserPVZs)
+		r.Put("/{userId}/pvz/{pvzId}", adminHandler.AssignPVZ)
+		r.Delete("/{userId}/pvz/{pvzId}", adminHandler.UnassignPVZ)
+	})
+
+	r.With(authMiddleware).Route("/admin/webhooks", func(r chi.Router) {
+		r.Post("/", webhookHandler.CreateSubscription)
+		r.Get("/", webhookHandler.GetSubscriptions)
+		r.Delete("/{webhookId}", webhookHandler.DeleteSubscription)
+		r.Get("/{webhookId}/deliveries", webhookHandler.GetDeliveries)
 	})
 
-	r.With(middleware.AuthMiddleware(jwtGenerator)).Post("/receptions", receptionHandler.CreateReception)
+	r.With(authMiddleware).Get("/admin/scheduler/jobs", schedulerHandler.GetJobs)
 
-	r.With(middleware.AuthMiddleware(jwtGenerator)).Post("/products", productHandler.AddProductToReception)
+	r.With(authMiddleware).Get("/stats/intake", statsHandler.GetIntake)
+	r.With(authMiddleware).Get("/stats/productivity", statsHandler.GetProductivity)
+	r.With(authMiddleware).Get("/exports/receptions", exportHandler.ExportReceptions)
 
 	return r
 }
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/config"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/ratelimit"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/aliskhannn/pvz-service/internal/usecase/mocks"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRouter_DummyLogin(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		secret         string
+		header         string
+		expectedStatus int
+	}{
+		{
+			name:           "Debug mode",
+			mode:           constants.ServerModeDebug,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Test mode with valid secret",
+			mode:           constants.ServerModeTest,
+			secret:         "secret",
+			header:         "secret",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Test mode with wrong secret",
+			mode:           constants.ServerModeTest,
+			secret:         "secret",
+			header:         "wrong",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Release mode",
+			mode:           constants.ServerModeRelease,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &mocks.MockJWTGenerator{}
+			tokens.On("CreateDummyToken", mock.Anything, constants.UserRoleEmployee, mock.Anything).Return("dummy-token", nil).Maybe()
+
+			authUC := usecase.NewAuthUseCase(&repository_mocks.MockUserRepository{}, tokens, &mocks.MockPasswordHasher{}, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), usecase.AuthOptions{})
+			limiter := ratelimit.NewMemoryLimiter(100, time.Minute)
+
+			router := NewRouter(RouterDeps{
+				Config:       &config.Config{Server: config.Server{Mode: tt.mode, DummyLoginSecret: tt.secret}},
+				JWTGenerator: tokens,
+				IPLimiter:    limiter,
+				EmailLimiter: limiter,
+				AuthUC:       authUC,
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/dummyLogin", bytes.NewBufferString(`{"role":"employee"}`))
+			if tt.header != "" {
+				req.Header.Set("X-Dummy-Login-Secret", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+const (
+	// sseHeartbeat — как часто отправляется комментарий, чтобы прокси не закрывали простаивающее соединение.
+	sseHeartbeat = 15 * time.Second
+	// sseWriteTimeout ограничивает запись одного события: клиент, который не читает, отключается.
+	sseWriteTimeout = 10 * time.Second
+)
+
+type LiveFeedHandler struct {
+	liveFeedUseCase usecase.LiveFeedUseCase
+}
+
+func NewLiveFeedHandler(liveFeedUseCase usecase.LiveFeedUseCase) *LiveFeedHandler {
+	return &LiveFeedHandler{
+		liveFeedUseCase: liveFeedUseCase,
+	}
+}
+
+func (h *LiveFeedHandler) StreamPVZEvents(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	sub, err := h.liveFeedUseCase.SubscribePVZ(r.Context(), pvzId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+	defer sub.Close()
+
+	stream(w, r, sub)
+}
+
+func (h *LiveFeedHandler) StreamCityEvents(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	sub, err := h.liveFeedUseCase.SubscribeCity(r.Context(), r.URL.Query().Get("city"), user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+	defer sub.Close()
+
+	stream(w, r, sub)
+}
+
+// stream отдает события подписки в формате Server-Sent Events, пока клиент не отключится.
+// Если клиент не успевает читать, брокер закрывает подписку: клиенту уходит событие lagged,
+// и соединение закрывается — EventSource переподключится сам.
+func stream(w http.ResponseWriter, r *http.Request, sub *broker.Subscription) {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(format string, args ...any) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !write(": connected\n\n") {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if !write(": ping\n\n") {
+				return
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Lagged() {
+					write("event: lagged\ndata: {\"message\":\"client is too slow, reconnect\"}\n\n")
+				}
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if !write("id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data) {
+				return
+			}
+		}
+	}
+}
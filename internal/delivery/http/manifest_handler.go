@@ -0,0 +1,87 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+type ManifestHandler struct {
+	manifestUseCase usecase.ManifestUseCase
+}
+
+func NewManifestHandler(manifestUseCase usecase.ManifestUseCase) *ManifestHandler {
+	return &ManifestHandler{
+		manifestUseCase: manifestUseCase,
+	}
+}
+
+func (h *ManifestHandler) CreateManifest(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	var manifest domain.Manifest
+	if err := request.DecodeJSON(r, &manifest); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	if err := h.manifestUseCase.CreateManifest(r.Context(), &manifest, user); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusCreated, manifest)
+}
+
+func (h *ManifestHandler) GetManifest(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	manifestId, err := uuid.Parse(chi.URLParam(r, "manifestId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	manifest, err := h.manifestUseCase.GetManifest(r.Context(), manifestId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, manifest)
+}
+
+func (h *ManifestHandler) GetDiscrepancyReport(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	receptionId, err := uuid.Parse(chi.URLParam(r, "receptionId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	report, err := h.manifestUseCase.GetDiscrepancyReport(r.Context(), receptionId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, report)
+}
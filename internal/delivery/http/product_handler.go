@@ -1,8 +1,10 @@
 package http
 
 import (
-	"encoding/json"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
 	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/ratelimit"
 	"github.com/aliskhannn/pvz-service/internal/middleware"
 	"github.com/aliskhannn/pvz-service/internal/usecase"
 	"github.com/go-chi/chi/v5"
@@ -12,63 +14,73 @@ import (
 
 type ProductHandler struct {
 	productUseCase usecase.ProductUseCase
+	codeLimiter    ratelimit.Limiter
 }
 
-func NewProductHandler(productUseCase usecase.ProductUseCase) *ProductHandler {
+func NewProductHandler(productUseCase usecase.ProductUseCase, codeLimiter ratelimit.Limiter) *ProductHandler {
 	return &ProductHandler{
 		productUseCase: productUseCase,
+		codeLimiter:    codeLimiter,
 	}
 }
 
 type AddRequest struct {
-	PVZId uuid.UUID `json:"pvz_id"`
-	Type  string    `json:"type"`
+	PVZId   uuid.UUID `json:"pvz_id" validate:"required,uuid"`
+	Type    string    `json:"type" validate:"required,oneof=электроника одежда обувь"`
+	Barcode string    `json:"barcode" validate:"omitempty,max=64"`
+	Volume  *float64  `json:"volume" validate:"omitempty,gt=0"`
+	// CellCode — отсканированный адрес ячейки; без него ячейка подбирается автоматически.
+	CellCode string `json:"cell_code" validate:"omitempty,max=64"`
+}
+
+type IssueRequest struct {
+	PickupCode string `json:"pickup_code" validate:"required"`
 }
 
 func (h *ProductHandler) AddProductToReception(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		response.WriteJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
 		return
 	}
 
 	user, ok := middleware.GetUserFromContext(r.Context())
 	if !ok || user == nil {
-		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
 		return
 	}
 
 	var req AddRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
 	}
 
-	err = h.productUseCase.AddProductToReception(r.Context(), req.PVZId, req.Type, user)
+	product, err := h.productUseCase.AddProductToReception(r.Context(), req.PVZId, req.Type, req.Barcode, req.CellCode, req.Volume, user)
 	if err != nil {
 		status := response.MapErrorToStatusCode(err)
 		response.WriteJSONError(w, status, err.Error())
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	response.WriteJSONResponse(w, http.StatusCreated, product)
 }
 
 func (h *ProductHandler) DeleteLatProductFromReception(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		response.WriteJSONError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
 		return
 	}
 
 	user, ok := middleware.GetUserFromContext(r.Context())
 	if !ok || user == nil {
-		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
 		return
 	}
 
 	pvzIdParam := chi.URLParam(r, "pvzId")
 	id, err := uuid.Parse(pvzIdParam)
 	if err != nil {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
 		return
 	}
 
@@ -81,3 +93,115 @@ func (h *ProductHandler) DeleteLatProductFromReception(w http.ResponseWriter, r
 
 	w.WriteHeader(http.StatusOK)
 }
+
+func (h *ProductHandler) IssueProduct(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	productId, err := uuid.Parse(chi.URLParam(r, "productId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	var req IssueRequest
+	if err = request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	if !h.allowPickupCode(w, r, productId) {
+		return
+	}
+
+	if err = h.productUseCase.IssueProduct(r.Context(), productId, req.PickupCode, user); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProductHandler) ReturnProductToSender(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	productId, err := uuid.Parse(chi.URLParam(r, "productId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	if err = h.productUseCase.ReturnProductToSender(r.Context(), productId, user); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProductHandler) GetInventory(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	inventory, err := h.productUseCase.GetInventory(r.Context(), pvzId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, inventory)
+}
+
+// GetPickupInfo — публичный эндпоинт для клиента: вместо JWT заказ защищен кодом получения.
+func (h *ProductHandler) GetPickupInfo(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "productId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	if !h.allowPickupCode(w, r, productId) {
+		return
+	}
+
+	info, err := h.productUseCase.GetPickupInfo(r.Context(), productId, r.URL.Query().Get("code"))
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, info)
+}
+
+// allowPickupCode ограничивает число проверок кода для одного заказа, чтобы код
+// нельзя было подобрать перебором даже с разных IP.
+func (h *ProductHandler) allowPickupCode(w http.ResponseWriter, r *http.Request, productId uuid.UUID) bool {
+	allowed, retryAfter, err := h.codeLimiter.Allow(r.Context(), "pickup:"+productId.String())
+	if err != nil {
+		response.WriteJSONError(w, http.StatusInternalServerError, appErr.ErrInternal.Error())
+		return false
+	}
+
+	if !allowed {
+		response.WriteTooManyRequests(w, retryAfter)
+		return false
+	}
+
+	return true
+}
@@ -7,9 +7,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/ratelimit"
 	"github.com/aliskhannn/pvz-service/internal/usecase/mocks"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,7 +22,7 @@ const UserContextKey = "user"
 
 func TestProductHandler_AddProductToReception(t *testing.T) {
 	mockUseCase := new(mocks.MockProductUseCase)
-	handler := NewProductHandler(mockUseCase)
+	handler := NewProductHandler(mockUseCase, ratelimit.NewMemoryLimiter(100, time.Minute))
 
 	tests := []struct {
 		name           string
@@ -33,14 +36,14 @@ func TestProductHandler_AddProductToReception(t *testing.T) {
 			name:   "Valid request",
 			method: http.MethodPost,
 			body: map[string]interface{}{
-				"pvz_id":       uuid.New().String(),
-				"product_type": "electronics",
+				"pvz_id": uuid.New().String(),
+				"type":   "электроника",
 			},
 			user: &domain.User{
 				Role: "employee",
 			},
 			mockSetup: func() {
-				mockUseCase.On("AddProductToReception", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				mockUseCase.On("AddProductToReception", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&domain.Product{}, nil)
 			},
 			expectedStatus: http.StatusCreated,
 		},
@@ -57,8 +60,8 @@ func TestProductHandler_AddProductToReception(t *testing.T) {
 			name:   "Invalid product type",
 			method: http.MethodPost,
 			body: map[string]interface{}{
-				"pvz_id":       uuid.New().String(),
-				"product_type": "invalid",
+				"pvz_id": uuid.New().String(),
+				"type":   "invalid",
 			},
 			user: &domain.User{
 				Role: "employee",
@@ -89,7 +92,7 @@ func TestProductHandler_AddProductToReception(t *testing.T) {
 
 func TestProductHandler_DeleteLatProductFromReception(t *testing.T) {
 	mockUseCase := new(mocks.MockProductUseCase)
-	handler := NewProductHandler(mockUseCase)
+	handler := NewProductHandler(mockUseCase, ratelimit.NewMemoryLimiter(100, time.Minute))
 
 	tests := []struct {
 		name           string
@@ -101,7 +104,7 @@ func TestProductHandler_DeleteLatProductFromReception(t *testing.T) {
 	}{
 		{
 			name:   "Valid request",
-			method: http.MethodDelete,
+			method: http.MethodPost,
 			pvzId:  uuid.New().String(),
 			user: &domain.User{
 				Role: "employee",
@@ -113,7 +116,7 @@ func TestProductHandler_DeleteLatProductFromReception(t *testing.T) {
 		},
 		{
 			name:   "Invalid method",
-			method: http.MethodPost,
+			method: http.MethodDelete,
 			pvzId:  uuid.New().String(),
 			user:   &domain.User{},
 			mockSetup: func() {
@@ -122,7 +125,7 @@ func TestProductHandler_DeleteLatProductFromReception(t *testing.T) {
 		},
 		{
 			name:   "Invalid PVZ ID",
-			method: http.MethodDelete,
+			method: http.MethodPost,
 			pvzId:  "invalid-uuid",
 			user: &domain.User{
 				Role: "employee",
@@ -137,8 +140,12 @@ func TestProductHandler_DeleteLatProductFromReception(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			req := httptest.NewRequest(tt.method, "/api/v1/products/"+tt.pvzId, nil)
-			ctx := context.WithValue(req.Context(), UserContextKey, tt.user)
+			req := httptest.NewRequest(tt.method, "/pvz/"+tt.pvzId+"/delete_last_product", nil)
+			// pvzId берется из параметров маршрута chi.
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("pvzId", tt.pvzId)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+			ctx = context.WithValue(ctx, UserContextKey, tt.user)
 			req = req.WithContext(ctx)
 
 			rec := httptest.NewRecorder()
@@ -1,16 +1,27 @@
 package http
 
 import (
-	"encoding/json"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
 	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/aliskhannn/pvz-service/internal/middleware"
 	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"net/http"
 	"strconv"
 	"time"
 )
 
+// maxPVZImportSize ограничивает размер CSV при импорте ПВЗ.
+const maxPVZImportSize = 5 << 20
+
+type CapacityRequest struct {
+	CapacityItems  *int     `json:"capacity_items" validate:"omitempty,gt=0"`
+	CapacityVolume *float64 `json:"capacity_volume" validate:"omitempty,gt=0"`
+}
+
 type PVZHandler struct {
 	pvzUseCase usecase.PvzUseCase
 }
@@ -33,19 +44,13 @@ func (h *PVZHandler) CreatePVZ(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if user.Role != "moderator" {
-		response.WriteJSONError(w, http.StatusForbidden, "access denied")
-		return
-	}
-
 	var pvz domain.PVZ
-	err := json.NewDecoder(r.Body).Decode(&pvz)
-	if err != nil {
-		response.WriteJSONError(w, http.StatusBadRequest, "invalid request")
+	if err := request.DecodeJSON(r, &pvz); err != nil {
+		response.WriteRequestError(w, err)
 		return
 	}
 
-	err = h.pvzUseCase.CreatePVZ(r.Context(), &pvz, user)
+	err := h.pvzUseCase.CreatePVZ(r.Context(), &pvz, user)
 	if err != nil {
 		status := response.MapErrorToStatusCode(err)
 		response.WriteJSONError(w, status, err.Error())
@@ -128,3 +133,75 @@ func (h *PVZHandler) GetAllPVZsWithReceptions(w http.ResponseWriter, r *http.Req
 
 	response.WriteJSONResponse(w, http.StatusOK, pvzs)
 }
+
+// ImportPVZs создает ПВЗ из CSV в теле запроса. При ошибках в строках возвращает 400 с их списком,
+// ничего не создавая; с dryRun=true только проверяет файл.
+func (h *PVZHandler) ImportPVZs(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dryRun"); dryRunStr != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid dryRun")
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxPVZImportSize)
+
+	result, err := h.pvzUseCase.ImportPVZs(r.Context(), body, dryRun, user)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.WriteJSONError(w, http.StatusRequestEntityTooLarge, "file is too large")
+			return
+		}
+
+		response.WriteError(w, err)
+		return
+	}
+
+	switch {
+	case len(result.Errors) > 0:
+		response.WriteJSONResponse(w, http.StatusBadRequest, result)
+	case dryRun:
+		response.WriteJSONResponse(w, http.StatusOK, result)
+	default:
+		response.WriteJSONResponse(w, http.StatusCreated, result)
+	}
+}
+
+// SetPVZCapacity задает вместимость ПВЗ. Отсутствующее или null поле снимает ограничение.
+func (h *PVZHandler) SetPVZCapacity(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	var req CapacityRequest
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	pvz, err := h.pvzUseCase.SetPVZCapacity(r.Context(), pvzId, req.CapacityItems, req.CapacityVolume, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, pvz)
+}
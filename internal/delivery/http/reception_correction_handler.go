@@ -0,0 +1,141 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+type ReceptionCorrectionHandler struct {
+	correctionUseCase usecase.ReceptionCorrectionUseCase
+}
+
+func NewReceptionCorrectionHandler(correctionUseCase usecase.ReceptionCorrectionUseCase) *ReceptionCorrectionHandler {
+	return &ReceptionCorrectionHandler{
+		correctionUseCase: correctionUseCase,
+	}
+}
+
+type CorrectionReasonRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type CorrectionAddProductRequest struct {
+	Type    string `json:"type" validate:"required,oneof=электроника одежда обувь"`
+	Barcode string `json:"barcode" validate:"omitempty,max=64"`
+	Reason  string `json:"reason" validate:"required,max=500"`
+}
+
+func (h *ReceptionCorrectionHandler) ReopenReception(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	receptionId, err := uuid.Parse(chi.URLParam(r, "receptionId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	var req CorrectionReasonRequest
+	if err = request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	if err = h.correctionUseCase.ReopenReception(r.Context(), receptionId, req.Reason, user); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ReceptionCorrectionHandler) AddProduct(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	receptionId, err := uuid.Parse(chi.URLParam(r, "receptionId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	var req CorrectionAddProductRequest
+	if err = request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	product, err := h.correctionUseCase.AddProduct(r.Context(), receptionId, req.Type, req.Barcode, req.Reason, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusCreated, product)
+}
+
+func (h *ReceptionCorrectionHandler) RemoveProduct(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	receptionId, err := uuid.Parse(chi.URLParam(r, "receptionId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	productId, err := uuid.Parse(chi.URLParam(r, "productId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	var req CorrectionReasonRequest
+	if err = request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	if err = h.correctionUseCase.RemoveProduct(r.Context(), receptionId, productId, req.Reason, user); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ReceptionCorrectionHandler) GetAudit(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	receptionId, err := uuid.Parse(chi.URLParam(r, "receptionId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	entries, err := h.correctionUseCase.GetAudit(r.Context(), receptionId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, entries)
+}
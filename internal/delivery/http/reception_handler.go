@@ -1,7 +1,7 @@
 package http
 
 import (
-	"encoding/json"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
 	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
 	"github.com/aliskhannn/pvz-service/internal/middleware"
 	"github.com/aliskhannn/pvz-service/internal/usecase"
@@ -21,7 +21,7 @@ func NewReceptionHandler(receptionUseCase usecase.ReceptionUseCase) *ReceptionHa
 }
 
 type CreateRequest struct {
-	PVZId uuid.UUID `json:"pvz_id"`
+	PVZId uuid.UUID `json:"pvz_id" validate:"required,uuid"`
 }
 
 func (h *ReceptionHandler) CreateReception(w http.ResponseWriter, r *http.Request) {
@@ -37,9 +37,8 @@ func (h *ReceptionHandler) CreateReception(w http.ResponseWriter, r *http.Reques
 	}
 
 	var req CreateRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		response.WriteJSONError(w, http.StatusBadRequest, "Invalid Request")
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
 		return
 	}
 
@@ -81,3 +80,77 @@ func (h *ReceptionHandler) CloseLastReception(w http.ResponseWriter, r *http.Req
 
 	w.WriteHeader(http.StatusOK)
 }
+
+func (h *ReceptionHandler) CreateReturnReception(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	var req CreateRequest
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	reception, err := h.receptionUseCase.CreateReturnReception(r.Context(), req.PVZId, user)
+	if err != nil {
+		status := response.MapErrorToStatusCode(err)
+		response.WriteJSONError(w, status, err.Error())
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusCreated, reception)
+}
+
+type AddToReturnRequest struct {
+	PVZId     uuid.UUID `json:"pvz_id" validate:"required"`
+	ProductId uuid.UUID `json:"product_id" validate:"required"`
+}
+
+func (h *ReceptionHandler) AddProductToReturn(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	var req AddToReturnRequest
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	err := h.receptionUseCase.AddProductToReturn(r.Context(), req.PVZId, req.ProductId, user)
+	if err != nil {
+		status := response.MapErrorToStatusCode(err)
+		response.WriteJSONError(w, status, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ReceptionHandler) CloseLastReturn(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	err = h.receptionUseCase.CloseLastReturn(r.Context(), id, user)
+	if err != nil {
+		status := response.MapErrorToStatusCode(err)
+		response.WriteJSONError(w, status, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"net/http"
+)
+
+type ReceptionSLAHandler struct {
+	slaUseCase usecase.ReceptionSLAUseCase
+}
+
+func NewReceptionSLAHandler(slaUseCase usecase.ReceptionSLAUseCase) *ReceptionSLAHandler {
+	return &ReceptionSLAHandler{
+		slaUseCase: slaUseCase,
+	}
+}
+
+func (h *ReceptionSLAHandler) GetSLABreaches(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	breaches, err := h.slaUseCase.GetSLABreaches(r.Context(), r.URL.Query().Get("city"), user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, breaches)
+}
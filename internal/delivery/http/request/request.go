@@ -0,0 +1,144 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+var validate = newValidator()
+
+// FieldError описывает ошибку валидации конкретного поля запроса.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError возвращается, когда тело запроса не прошло валидацию.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+
+	return "validation error: " + strings.Join(parts, "; ")
+}
+
+// DecodeJSON декодирует тело запроса в dst и проверяет его по тегам validate.
+func DecodeJSON(r *http.Request, dst interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	return Validate(dst)
+}
+
+// Validate проверяет структуру по тегам validate.
+func Validate(v interface{}) error {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, FieldError{
+			Field:   fe.Field(),
+			Message: fieldMessage(fe),
+		})
+	}
+
+	return &ValidationError{Fields: fields}
+}
+
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &ValidationError{Fields: []FieldError{{
+			Field:   typeErr.Field,
+			Message: "must be of type " + typeErr.Type.String(),
+		}}}
+	}
+
+	if errors.Is(err, io.EOF) {
+		return &ValidationError{Fields: []FieldError{{
+			Field:   "body",
+			Message: "is required",
+		}}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &ValidationError{Fields: []FieldError{{
+			Field:   "body",
+			Message: "malformed JSON",
+		}}}
+	}
+
+	return &ValidationError{Fields: []FieldError{{
+		Field:   "body",
+		Message: err.Error(),
+	}}}
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "uuid":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "min":
+		return fmt.Sprintf("must be at least %s characters long", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	default:
+		return "failed on the '" + fe.Tag() + "' rule"
+	}
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// В ошибках используем имена полей из JSON, а не из Go-структур.
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+
+	// uuid.UUID — это массив байт, поэтому валидируем его строковое
+	// представление; нулевой UUID считается пустым значением.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		id, ok := field.Interface().(uuid.UUID)
+		if !ok || id == uuid.Nil {
+			return ""
+		}
+		return id.String()
+	}, uuid.UUID{})
+
+	return v
+}
@@ -0,0 +1,77 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	PVZId uuid.UUID `json:"pvz_id" validate:"required,uuid"`
+	Email string    `json:"email" validate:"required,email"`
+	Type  string    `json:"type" validate:"oneof=a b"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	validId := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedFields []FieldError
+	}{
+		{
+			name: "Valid request",
+			body: `{"pvz_id":"` + validId + `","email":"test@example.com","type":"a"}`,
+		},
+		{
+			name: "Missing fields",
+			body: `{"type":"a"}`,
+			expectedFields: []FieldError{
+				{Field: "pvz_id", Message: "is required"},
+				{Field: "email", Message: "is required"},
+			},
+		},
+		{
+			name: "Invalid values",
+			body: `{"pvz_id":"` + validId + `","email":"not-an-email","type":"c"}`,
+			expectedFields: []FieldError{
+				{Field: "email", Message: "must be a valid email"},
+				{Field: "type", Message: "must be one of: a b"},
+			},
+		},
+		{
+			name:           "Wrong field type",
+			body:           `{"pvz_id":"` + validId + `","email":42}`,
+			expectedFields: []FieldError{{Field: "email", Message: "must be of type string"}},
+		},
+		{
+			name:           "Malformed JSON",
+			body:           `{"email":`,
+			expectedFields: []FieldError{{Field: "body", Message: "malformed JSON"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var dst testRequest
+			err := DecodeJSON(req, &dst)
+
+			if tt.expectedFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedFields, validationErr.Fields)
+		})
+	}
+}
@@ -1,27 +1,59 @@
 package response
 
 import (
+	"errors"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
 	"net/http"
 )
 
 // MapErrorToStatusCode маппит бизнес-ошибки на HTTP-статусы.
+// Ошибка может быть обернута с дополнительным контекстом, поэтому ищем известную по всей цепочке.
 func MapErrorToStatusCode(err error) int {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if status, ok := statusCode(err); ok {
+			return status
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+func statusCode(err error) (int, bool) {
 	switch err {
 	// 401 Unauthorized
 	case appErr.ErrUnauthorized,
 		appErr.ErrUserRequired:
-		return http.StatusUnauthorized
+		return http.StatusUnauthorized, true
 
 	// 403 Forbidden
 	case appErr.ErrForbidden,
+		appErr.ErrPermissionDenied,
 		appErr.ErrOnlyEmployeeAllowed,
-		appErr.ErrOnlyModeratorAllowed:
-		return http.StatusForbidden
+		appErr.ErrOnlyModeratorAllowed,
+		appErr.ErrEmailNotVerified,
+		appErr.ErrUserDisabled,
+		appErr.ErrPVZAccessDenied:
+		return http.StatusForbidden, true
+
+	// 429 Too Many Requests
+	case appErr.ErrTooManyRequests,
+		appErr.ErrAccountLocked:
+		return http.StatusTooManyRequests, true
 
 	// 404 Not Found
-	case appErr.ErrNotFound:
-		return http.StatusNotFound
+	case appErr.ErrNotFound,
+		appErr.ErrUserNotFound,
+		appErr.ErrPVZNotFound,
+		appErr.ErrAssignmentNotFound,
+		appErr.ErrProductNotFound,
+		appErr.ErrOrderNotFound,
+		appErr.ErrManifestNotFound,
+		appErr.ErrReceptionNotFound,
+		appErr.ErrDiscrepancyReportNotFound,
+		appErr.ErrTransferNotFound,
+		appErr.ErrWebhookNotFound,
+		appErr.ErrStorageCellNotFound:
+		return http.StatusNotFound, true
 
 	// 400 Bad Request — валидация, дубликаты, отсутствие полей, бизнес-ошибки клиента
 	case appErr.ErrBadRequest,
@@ -32,13 +64,56 @@ func MapErrorToStatusCode(err error) int {
 		appErr.ErrInvalidRole,
 		appErr.ErrMissingAuthFields,
 		appErr.ErrInvalidAuthFields,
+		appErr.ErrWeakPassword,
+		appErr.ErrInvalidVerification,
+		appErr.ErrCannotModifySelf,
+		appErr.ErrAssignNonEmployee,
 		appErr.ErrPVZIdRequired,
 		appErr.ErrPVZRequired,
 		appErr.ErrInvalidCity,
+		appErr.ErrInvalidPVZCapacity,
+		appErr.ErrInvalidVolume,
+		appErr.ErrPVZCapacityExceeded,
+		appErr.ErrStorageCellsRequired,
+		appErr.ErrInvalidStorageCell,
+		appErr.ErrDuplicateStorageCell,
+		appErr.ErrStorageCellFull,
+		appErr.ErrStorageCellNotSuitable,
+		appErr.ErrStorageCellNotEmpty,
+		appErr.ErrProductIdOrBarcodeRequired,
 		appErr.ErrPVZHasOpenReception,
+		appErr.ErrPVZHasOpenReturn,
+		appErr.ErrNoOpenReturn,
 		appErr.ErrPVZIdAndProductTypeRequired,
-		appErr.ErrInvalidProductType:
-		return http.StatusBadRequest
+		appErr.ErrInvalidProductType,
+		appErr.ErrProductIdRequired,
+		appErr.ErrProductNotStored,
+		appErr.ErrPickupCodeRequired,
+		appErr.ErrInvalidPickupCode,
+		appErr.ErrManifestRequired,
+		appErr.ErrManifestItemsRequired,
+		appErr.ErrDuplicateManifestBarcode,
+		appErr.ErrReceptionNotClosed,
+		appErr.ErrCorrectionReasonRequired,
+		appErr.ErrReceptionNotCorrectable,
+		appErr.ErrNewerReceptionExists,
+		appErr.ErrProductNotInReception,
+		appErr.ErrTransferSamePVZ,
+		appErr.ErrTransferProductsRequired,
+		appErr.ErrTransferProductsUnavailable,
+		appErr.ErrTransferAlreadyReceived,
+		appErr.ErrInvalidWebhookURL,
+		appErr.ErrInvalidEventType,
+		appErr.ErrInvalidDeliveryStatus,
+		appErr.ErrInvalidStatsGroupBy,
+		appErr.ErrInvalidStatsBucket,
+		appErr.ErrInvalidStatsPeriod,
+		appErr.ErrInvalidExportPeriod,
+		appErr.ErrInvalidPVZImportHeader,
+		appErr.ErrPVZImportEmpty,
+		appErr.ErrPVZImportTooLarge,
+		appErr.ErrNoOpenReception:
+		return http.StatusBadRequest, true
 
 	// 500 Internal Server Error — технические ошибки
 	case appErr.ErrInternal,
@@ -46,18 +121,51 @@ func MapErrorToStatusCode(err error) int {
 		appErr.ErrCreatingUser,
 		appErr.ErrGettingUser,
 		appErr.ErrCreatingToken,
+		appErr.ErrSendingEmail,
+		appErr.ErrGettingUsers,
+		appErr.ErrUpdatingUser,
 		appErr.ErrCreatingPVZ,
 		appErr.ErrGettingPVZs,
+		appErr.ErrUpdatingPVZ,
+		appErr.ErrCreatingStorageCells,
+		appErr.ErrGettingStorageCells,
+		appErr.ErrDeletingStorageCell,
+		appErr.ErrLocatingProduct,
+		appErr.ErrCheckingPVZAccess,
+		appErr.ErrUpdatingAssignments,
+		appErr.ErrGettingAssignments,
 		appErr.ErrGettingReceptions,
 		appErr.ErrCreatingReception,
 		appErr.ErrClosingLastReception,
+		appErr.ErrAddingProductToReturn,
+		appErr.ErrClosingLastReturn,
+		appErr.ErrReopeningReception,
+		appErr.ErrCorrectingReception,
+		appErr.ErrGettingReceptionAudit,
 		appErr.ErrGettingProducts,
 		appErr.ErrCreatingProduct,
-		appErr.ErrDeletingLastProduct:
-		return http.StatusInternalServerError
+		appErr.ErrDeletingLastProduct,
+		appErr.ErrUpdatingProduct,
+		appErr.ErrCreatingManifest,
+		appErr.ErrGettingManifest,
+		appErr.ErrGettingDiscrepancyReport,
+		appErr.ErrCreatingTransfer,
+		appErr.ErrReceivingTransfer,
+		appErr.ErrGettingTransfers,
+		appErr.ErrGettingInventory,
+		appErr.ErrGettingOverdueProducts,
+		appErr.ErrCreatingWebhook,
+		appErr.ErrGettingWebhooks,
+		appErr.ErrDeletingWebhook,
+		appErr.ErrGettingWebhookDeliveries,
+		appErr.ErrGettingStats,
+		appErr.ErrGettingProductivity,
+		appErr.ErrGettingSLABreaches,
+		appErr.ErrExportingReceptions,
+		appErr.ErrImportingPVZs:
+		return http.StatusInternalServerError, true
 
-	// fallback на 500
 	default:
-		return http.StatusInternalServerError
+		return 0, false
 	}
 }
@@ -2,11 +2,18 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type ErrorResponse struct {
-	Message string `json:"message"`
+	Message string               `json:"message"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
 }
 
 func WriteJSONError(w http.ResponseWriter, status int, message string) {
@@ -15,6 +22,50 @@ func WriteJSONError(w http.ResponseWriter, status int, message string) {
 	json.NewEncoder(w).Encode(ErrorResponse{Message: message})
 }
 
+// WriteError пишет ошибку бизнес-логики с соответствующим HTTP-статусом.
+// Для ошибок с временем повтора дополнительно выставляется заголовок Retry-After.
+func WriteError(w http.ResponseWriter, err error) {
+	var retryErr *appErr.RetryAfterError
+	if errors.As(err, &retryErr) {
+		SetRetryAfter(w, retryErr.RetryAfter)
+		err = retryErr.Err
+	}
+
+	WriteJSONError(w, MapErrorToStatusCode(err), err.Error())
+}
+
+// WriteTooManyRequests отвечает 429 с заголовком Retry-After.
+func WriteTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	SetRetryAfter(w, retryAfter)
+	WriteJSONError(w, http.StatusTooManyRequests, appErr.ErrTooManyRequests.Error())
+}
+
+// SetRetryAfter выставляет заголовок Retry-After в целых секундах, но не меньше одной.
+func SetRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
+
+// WriteRequestError отвечает 400 с подробностями по полям, если запрос не прошел валидацию.
+func WriteRequestError(w http.ResponseWriter, err error) {
+	var validationErr *request.ValidationError
+	if !errors.As(err, &validationErr) {
+		WriteJSONError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Message: "validation error",
+		Errors:  validationErr.Fields,
+	})
+}
+
 func WriteJSONResponse(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"net/http"
+)
+
+type SchedulerHandler struct {
+	schedulerUseCase usecase.SchedulerUseCase
+}
+
+func NewSchedulerHandler(schedulerUseCase usecase.SchedulerUseCase) *SchedulerHandler {
+	return &SchedulerHandler{
+		schedulerUseCase: schedulerUseCase,
+	}
+}
+
+func (h *SchedulerHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	jobs, err := h.schedulerUseCase.GetJobs(r.Context(), user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, jobs)
+}
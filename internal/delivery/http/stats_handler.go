@@ -0,0 +1,103 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+type StatsHandler struct {
+	statsUseCase usecase.StatsUseCase
+}
+
+func NewStatsHandler(statsUseCase usecase.StatsUseCase) *StatsHandler {
+	return &StatsHandler{
+		statsUseCase: statsUseCase,
+	}
+}
+
+func (h *StatsHandler) GetIntake(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	query := r.URL.Query()
+
+	var from, to time.Time
+	var err error
+
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err = time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid 'from' date, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	if toStr := query.Get("to"); toStr != "" {
+		to, err = time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid 'to' date, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	stats, err := h.statsUseCase.GetIntake(r.Context(), query.Get("groupBy"), query.Get("bucket"), from, to, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, stats)
+}
+
+func (h *StatsHandler) GetProductivity(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	query := r.URL.Query()
+
+	var from, to time.Time
+	var pvzId uuid.UUID
+	var err error
+
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err = time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid 'from' date, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	if toStr := query.Get("to"); toStr != "" {
+		to, err = time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid 'to' date, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	if pvzIdStr := query.Get("pvzId"); pvzIdStr != "" {
+		pvzId, err = uuid.Parse(pvzIdStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+	}
+
+	report, err := h.statsUseCase.GetProductivity(r.Context(), from, to, pvzId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, report)
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+type StorageCellHandler struct {
+	cellUseCase usecase.StorageCellUseCase
+}
+
+func NewStorageCellHandler(cellUseCase usecase.StorageCellUseCase) *StorageCellHandler {
+	return &StorageCellHandler{
+		cellUseCase: cellUseCase,
+	}
+}
+
+type CellRequest struct {
+	Rack         string   `json:"rack" validate:"required,max=16"`
+	Shelf        string   `json:"shelf" validate:"required,max=16"`
+	Cell         string   `json:"cell" validate:"required,max=16"`
+	Capacity     *int     `json:"capacity" validate:"omitempty,gt=0"`
+	ProductTypes []string `json:"product_types" validate:"omitempty,dive,oneof=электроника одежда обувь"`
+}
+
+type CreateCellsRequest struct {
+	Cells []CellRequest `json:"cells" validate:"required,min=1,max=1000,dive"`
+}
+
+func (h *StorageCellHandler) CreateCells(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	var req CreateCellsRequest
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	cells := make([]*domain.StorageCell, 0, len(req.Cells))
+	for _, cell := range req.Cells {
+		cells = append(cells, &domain.StorageCell{
+			Rack:         cell.Rack,
+			Shelf:        cell.Shelf,
+			Cell:         cell.Cell,
+			Capacity:     cell.Capacity,
+			ProductTypes: cell.ProductTypes,
+		})
+	}
+
+	created, err := h.cellUseCase.CreateCells(r.Context(), pvzId, cells, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusCreated, created)
+}
+
+func (h *StorageCellHandler) GetCells(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	cells, err := h.cellUseCase.GetCells(r.Context(), pvzId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, cells)
+}
+
+func (h *StorageCellHandler) DeleteCell(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	cellId, err := uuid.Parse(chi.URLParam(r, "cellId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	if err := h.cellUseCase.DeleteCell(r.Context(), pvzId, cellId, user); err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LocateProduct ищет ячейку товара ПВЗ по productId или barcode.
+func (h *StorageCellHandler) LocateProduct(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	query := r.URL.Query()
+
+	var productId uuid.UUID
+	if productIdStr := query.Get("productId"); productIdStr != "" {
+		productId, err = uuid.Parse(productIdStr)
+		if err != nil {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid productId")
+			return
+		}
+	}
+
+	locations, err := h.cellUseCase.LocateProduct(r.Context(), pvzId, productId, query.Get("barcode"), user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, locations)
+}
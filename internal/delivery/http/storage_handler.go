@@ -0,0 +1,42 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+type StorageHandler struct {
+	storageUseCase usecase.StorageUseCase
+}
+
+func NewStorageHandler(storageUseCase usecase.StorageUseCase) *StorageHandler {
+	return &StorageHandler{
+		storageUseCase: storageUseCase,
+	}
+}
+
+func (h *StorageHandler) GetOverdueProducts(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	products, err := h.storageUseCase.GetOverdueProducts(r.Context(), pvzId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, products)
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/request"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/middleware"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+)
+
+type TransferHandler struct {
+	transferUseCase usecase.TransferUseCase
+}
+
+func NewTransferHandler(transferUseCase usecase.TransferUseCase) *TransferHandler {
+	return &TransferHandler{
+		transferUseCase: transferUseCase,
+	}
+}
+
+type CreateTransferRequest struct {
+	SourcePVZId      uuid.UUID   `json:"source_pvz_id" validate:"required"`
+	DestinationPVZId uuid.UUID   `json:"destination_pvz_id" validate:"required"`
+	ProductIds       []uuid.UUID `json:"product_ids" validate:"required,min=1,max=500"`
+}
+
+func (h *TransferHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	var req CreateTransferRequest
+	if err := request.DecodeJSON(r, &req); err != nil {
+		response.WriteRequestError(w, err)
+		return
+	}
+
+	transfer, err := h.transferUseCase.CreateTransfer(r.Context(), req.SourcePVZId, req.DestinationPVZId, req.ProductIds, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusCreated, transfer)
+}
+
+func (h *TransferHandler) ReceiveTransfer(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	transferId, err := uuid.Parse(chi.URLParam(r, "transferId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	transfer, err := h.transferUseCase.ReceiveTransfer(r.Context(), transferId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, transfer)
+}
+
+func (h *TransferHandler) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	transferId, err := uuid.Parse(chi.URLParam(r, "transferId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	transfer, err := h.transferUseCase.GetTransfer(r.Context(), transferId, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, transfer)
+}
+
+func (h *TransferHandler) GetPVZTransfers(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		response.WriteJSONError(w, http.StatusUnauthorized, "Unauthorized User")
+		return
+	}
+
+	pvzId, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		response.WriteJSONError(w, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	query := r.URL.Query()
+
+	page := 1
+	limit := 10
+
+	if pageStr := query.Get("page"); pageStr != "" {
+		page, err = strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid page")
+			return
+		}
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 100 {
+			response.WriteJSONError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	transfers, err := h.transferUseCase.GetPVZTransfers(r.Context(), pvzId, page, limit, user)
+	if err != nil {
+		response.WriteError(w, err)
+		return
+	}
+
+	response.WriteJSONResponse(w, http.StatusOK, transfers)
+}
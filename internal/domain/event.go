@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Event — доменное событие для внешних систем. Доставка не реже одного раза,
+// поэтому получатель должен отбрасывать повторы по Id.
+type Event struct {
+	Id          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	PVZId       uuid.UUID       `json:"pvz_id"`
+	AggregateId uuid.UUID       `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// OutboxEvent — событие в outbox вместе с состоянием доставки.
+type OutboxEvent struct {
+	Event
+	Seq           int64
+	Attempts      int
+	NextAttemptAt time.Time
+}
+
+// ReceptionEventPayload — данные событий открытия, закрытия и переоткрытия приемки.
+type ReceptionEventPayload struct {
+	ReceptionId uuid.UUID `json:"reception_id"`
+	PVZId       uuid.UUID `json:"pvz_id"`
+	Direction   string    `json:"direction"`
+	AutoClosed  bool      `json:"auto_closed,omitempty"`
+	CloseReason string    `json:"close_reason,omitempty"`
+	// CorrectionReason — причина, указанная модератором при переоткрытии.
+	CorrectionReason string `json:"correction_reason,omitempty"`
+}
+
+// ProductEventPayload — данные событий добавления и удаления товара. Код получения в событие не попадает.
+type ProductEventPayload struct {
+	ProductId   uuid.UUID `json:"product_id"`
+	ReceptionId uuid.UUID `json:"reception_id"`
+	PVZId       uuid.UUID `json:"pvz_id"`
+	Type        string    `json:"type"`
+	Barcode     string    `json:"barcode,omitempty"`
+	DateTime    time.Time `json:"date_time"`
+	// CorrectionReason заполняется, если товар добавлен в закрытую приемку или удален из нее исправлением.
+	CorrectionReason string `json:"correction_reason,omitempty"`
+}
+
+// TransferEventPayload — данные событий отправки и приема перемещения. В отличие от событий товаров,
+// здесь есть перевыпущенные коды получения: по событию приема клиентам сообщают новые коды.
+// ReceptionId заполняется только при приеме.
+type TransferEventPayload struct {
+	TransferId        uuid.UUID           `json:"transfer_id"`
+	SourcePVZId       uuid.UUID           `json:"source_pvz_id"`
+	DestinationPVZId  uuid.UUID           `json:"destination_pvz_id"`
+	ReceptionId       *uuid.UUID          `json:"reception_id,omitempty"`
+	ProductIds        []uuid.UUID         `json:"product_ids"`
+	PickupCodeChanges []*PickupCodeChange `json:"pickup_code_changes,omitempty"`
+}
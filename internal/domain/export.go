@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ReceptionExportFilter — выгрузка товаров из приемок, открытых в [From, To). Пустой City — все города.
+type ReceptionExportFilter struct {
+	From time.Time
+	To   time.Time
+	City string
+}
+
+// ReceptionExportRow — строка выгрузки: товар вместе с приемкой и ПВЗ, в который он поступил.
+type ReceptionExportRow struct {
+	ReceptionId       uuid.UUID
+	ReceptionStatus   string
+	ReceptionOpenedAt time.Time
+	ReceptionClosedAt *time.Time
+	PVZId             uuid.UUID
+	City              string
+	Address           string
+	ProductId         uuid.UUID
+	ProductType       string
+	Barcode           string
+	ProductStatus     string
+	ProductAddedAt    time.Time
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// JobStatus — состояние фоновой задачи планировщика в этом экземпляре сервиса. Skipped — запуски,
+// которые пропущены, потому что в этом интервале задачу уже выполнил другой экземпляр.
+type JobStatus struct {
+	Name           string     `json:"name"`
+	Interval       string     `json:"interval"`
+	Running        bool       `json:"running"`
+	Runs           int        `json:"runs"`
+	Skipped        int        `json:"skipped"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Manifest — ожидаемая поставка (ASN): список товаров, которые должны прийти в ПВЗ.
+type Manifest struct {
+	Id          uuid.UUID       `json:"id"`
+	PVZId       uuid.UUID       `json:"pvz_id" validate:"required"`
+	ExternalId  string          `json:"external_id,omitempty" validate:"omitempty,max=128"`
+	ReceptionId *uuid.UUID      `json:"reception_id,omitempty"`
+	Status      string          `json:"status"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Items       []*ManifestItem `json:"items" validate:"required,min=1,dive"`
+}
+
+type ManifestItem struct {
+	Barcode string `json:"barcode" validate:"required,max=64"`
+	Type    string `json:"type" validate:"required,oneof=электроника одежда обувь"`
+}
+
+// DiscrepancyReport — расхождения между манифестом и фактически принятыми товарами.
+type DiscrepancyReport struct {
+	ReceptionId    uuid.UUID `json:"reception_id"`
+	ManifestId     uuid.UUID `json:"manifest_id"`
+	Expected       int       `json:"expected"`
+	Received       int       `json:"received"`
+	Missing        []string  `json:"missing"`
+	Unexpected     []string  `json:"unexpected"`
+	Duplicates     []string  `json:"duplicates"`
+	WithoutBarcode int       `json:"without_barcode"`
+	CreatedAt      time.Time `json:"created_at"`
+}
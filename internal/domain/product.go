@@ -6,9 +6,43 @@ import (
 )
 
 type Product struct {
-	Id          uuid.UUID `json:"id" validate:"uuid"`
-	DateTime    time.Time `json:"date_time"`
-	Type        string    `json:"type" validate:"required,oneof=Электроника Одежда Обувь"`
-	PVZId       uuid.UUID `json:"pvz_id" validate:"required,uuid"`
-	ReceptionId uuid.UUID `json:"reception_id"`
+	Id              uuid.UUID  `json:"id"`
+	DateTime        time.Time  `json:"date_time"`
+	Type            string     `json:"type" validate:"required,oneof=электроника одежда обувь"`
+	PVZId           uuid.UUID  `json:"pvz_id" validate:"required,uuid"`
+	ReceptionId     uuid.UUID  `json:"reception_id"`
+	Barcode         string     `json:"barcode,omitempty"`
+	Status          string     `json:"status"`
+	PickupCode      string     `json:"pickup_code,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	CreatedBy       *uuid.UUID `json:"created_by,omitempty"`
+	// Volume — объем товара в литрах, если известен.
+	Volume *float64 `json:"volume,omitempty"`
+	// OverCapacity — товар принят сверх вместимости ПВЗ (политика warn).
+	OverCapacity bool `json:"over_capacity,omitempty"`
+	// CellId и CellCode — ячейка хранения, в которую положен товар.
+	CellId   *uuid.UUID `json:"cell_id,omitempty"`
+	CellCode string     `json:"cell_code,omitempty"`
+}
+
+// PickupInfo — сведения о заказе, которые клиент видит по номеру заказа и коду получения.
+type PickupInfo struct {
+	ProductId       uuid.UUID  `json:"product_id"`
+	Type            string     `json:"type"`
+	Status          string     `json:"status"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	PVZId           uuid.UUID  `json:"pvz_id"`
+	City            string     `json:"city"`
+	Address         string     `json:"address,omitempty"`
+	OpeningHours    string     `json:"opening_hours,omitempty"`
+	PickupCode      string     `json:"-"`
+}
+
+// Inventory — товары, которые сейчас физически находятся в ПВЗ.
+type Inventory struct {
+	PVZId    uuid.UUID      `json:"pvz_id"`
+	Total    int            `json:"total"`
+	ByType   map[string]int `json:"by_type"`
+	ByStatus map[string]int `json:"by_status"`
+	Products []*Product     `json:"products"`
 }
@@ -2,12 +2,75 @@ package domain
 
 import (
 	"github.com/google/uuid"
+	"math"
 	"time"
 )
 
 type PVZ struct {
-	Id               uuid.UUID    `json:"id" validate:"uuid"`
-	RegistrationDate time.Time    `json:"registration_date"`
-	City             string       `json:"city" validate:"required,oneof=Москва Санкт-Петербург Казань"`
-	Receptions       []*Reception `json:"receptions" validate:"required"`
+	Id               uuid.UUID     `json:"id"`
+	RegistrationDate time.Time     `json:"registration_date"`
+	City             string        `json:"city" validate:"required,oneof=Москва Санкт-Петербург Казань"`
+	Address          string        `json:"address,omitempty"`
+	OpeningHours     string        `json:"opening_hours,omitempty"`
+	CapacityItems    *int          `json:"capacity_items,omitempty" validate:"omitempty,gt=0"`
+	CapacityVolume   *float64      `json:"capacity_volume,omitempty" validate:"omitempty,gt=0"`
+	Occupancy        *PVZOccupancy `json:"occupancy,omitempty"`
+	Receptions       []*Reception  `json:"receptions"`
+}
+
+// PVZOccupancy — товары, которые сейчас находятся в ПВЗ: в открытой приемке, на хранении
+// или в возвратной партии. Volume — их суммарный объем в литрах без товаров с неизвестным объемом.
+// Utilization — доля занятой вместимости по наиболее заполненному измерению, если вместимость задана.
+type PVZOccupancy struct {
+	Items       int      `json:"items"`
+	Volume      float64  `json:"volume"`
+	Utilization *float64 `json:"utilization,omitempty"`
+}
+
+// ExceedsCapacity сообщает, превысит ли заполненность ПВЗ его вместимость, если добавить
+// items товаров общим объемом volume. ПВЗ без вместимости или без данных о заполненности не ограничен.
+func (p *PVZ) ExceedsCapacity(items int, volume float64) bool {
+	if p.Occupancy == nil {
+		return false
+	}
+
+	if p.CapacityItems != nil && p.Occupancy.Items+items > *p.CapacityItems {
+		return true
+	}
+
+	return p.CapacityVolume != nil && p.Occupancy.Volume+volume > *p.CapacityVolume
+}
+
+// Utilization возвращает долю занятой вместимости по наиболее заполненному измерению
+// или nil, если вместимость не задана.
+func (p *PVZ) Utilization() *float64 {
+	if p.Occupancy == nil || (p.CapacityItems == nil && p.CapacityVolume == nil) {
+		return nil
+	}
+
+	var utilization float64
+	if p.CapacityItems != nil {
+		utilization = float64(p.Occupancy.Items) / float64(*p.CapacityItems)
+	}
+	if p.CapacityVolume != nil {
+		utilization = max(utilization, p.Occupancy.Volume / *p.CapacityVolume)
+	}
+
+	utilization = math.Round(utilization*1000) / 1000
+
+	return &utilization
+}
+
+// PVZImportError — ошибка в строке CSV при импорте ПВЗ. Line — номер строки файла, заголовок — строка 1.
+type PVZImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// PVZImportResult — итог импорта ПВЗ. Если есть ошибки, ни один ПВЗ не создается; в пробном
+// запуске ПВЗ только проверяются и возвращаются без id.
+type PVZImportResult struct {
+	DryRun bool             `json:"dry_run"`
+	PVZs   []*PVZ           `json:"pvzs"`
+	Errors []PVZImportError `json:"errors"`
 }
@@ -6,9 +6,27 @@ import (
 )
 
 type Reception struct {
-	Id       uuid.UUID  `json:"id" validate:"uuid"`
-	DateTime time.Time  `json:"date_time" validate:"required"`
-	PVZId    uuid.UUID  `json:"pvz_id" validate:"required, uuid"`
-	Products []*Product `json:"products" validate:"required"`
-	Status   string     `json:"status" validate:"required,oneof=in_progress close"`
+	Id          uuid.UUID  `json:"id"`
+	DateTime    time.Time  `json:"date_time"`
+	PVZId       uuid.UUID  `json:"pvz_id" validate:"required,uuid"`
+	Products    []*Product `json:"products"`
+	Status      string     `json:"status" validate:"omitempty,oneof=in_progress close"`
+	Direction   string     `json:"direction" validate:"omitempty,oneof=inbound return"`
+	ManifestId  *uuid.UUID `json:"manifest_id,omitempty"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	AutoClosed  bool       `json:"auto_closed,omitempty"`
+	CloseReason string     `json:"close_reason,omitempty"`
+}
+
+// ReceptionAuditEntry — запись истории исправлений закрытой приемки.
+type ReceptionAuditEntry struct {
+	Id          uuid.UUID  `json:"id"`
+	ReceptionId uuid.UUID  `json:"reception_id"`
+	Action      string     `json:"action"`
+	ProductId   *uuid.UUID `json:"product_id,omitempty"`
+	ProductType string     `json:"product_type,omitempty"`
+	Barcode     string     `json:"barcode,omitempty"`
+	Reason      string     `json:"reason"`
+	UserId      *uuid.UUID `json:"user_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
 }
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// ReceptionSLA — допустимая длительность входящей приемки по городам; для городов без своего
+// значения действует Default.
+type ReceptionSLA struct {
+	Default time.Duration
+	ByCity  map[string]time.Duration
+}
+
+// For возвращает SLA приемки в городе. Регистр названия не учитывается: ключи конфига приходят в нижнем регистре.
+func (s ReceptionSLA) For(city string) time.Duration {
+	if sla, ok := s.ByCity[city]; ok {
+		return sla
+	}
+
+	for name, sla := range s.ByCity {
+		if strings.EqualFold(name, city) {
+			return sla
+		}
+	}
+
+	return s.Default
+}
+
+// OpenReception — открытая входящая приемка, ее возраст и SLA города. OverdueSeconds больше нуля,
+// если приемка открыта дольше SLA.
+type OpenReception struct {
+	ReceptionId    uuid.UUID `json:"reception_id"`
+	PVZId          uuid.UUID `json:"pvz_id"`
+	City           string    `json:"city"`
+	OpenedAt       time.Time `json:"opened_at"`
+	Products       int       `json:"products"`
+	OpenSeconds    int64     `json:"open_seconds"`
+	SLASeconds     int64     `json:"sla_seconds"`
+	OverdueSeconds int64     `json:"overdue_seconds"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// IntakeFilter — параметры отчета о поступлении товаров за период [From, To).
+type IntakeFilter struct {
+	GroupBy string
+	Bucket  string
+	From    time.Time
+	To      time.Time
+}
+
+// IntakeStat — сколько приемок и товаров поступило в группе Key за период, начинающийся в Bucket.
+// Key — город, id ПВЗ или тип товара в зависимости от группировки.
+type IntakeStat struct {
+	Bucket     time.Time `json:"bucket"`
+	Key        string    `json:"key"`
+	Receptions int       `json:"receptions"`
+	Products   int       `json:"products"`
+}
+
+// ProductivityFilter — отчет о работе сотрудников за период [From, To). Нулевой PVZId — все ПВЗ.
+type ProductivityFilter struct {
+	From  time.Time
+	To    time.Time
+	PVZId uuid.UUID
+}
+
+// EmployeeProductivity — сколько товаров сотрудник отсканировал и удалил в ПВЗ за период.
+// Смена — календарный день (UTC) с хотя бы одним действием; средний интервал считается между
+// соседними сканированиями одной смены и пуст, если таких пар нет.
+type EmployeeProductivity struct {
+	UserId                 uuid.UUID `json:"user_id"`
+	Email                  string    `json:"email"`
+	PVZId                  uuid.UUID `json:"pvz_id"`
+	Shifts                 int       `json:"shifts"`
+	Scanned                int       `json:"scanned"`
+	Deleted                int       `json:"deleted"`
+	DeletionRate           float64   `json:"deletion_rate"`
+	AvgScanIntervalSeconds *float64  `json:"avg_scan_interval_seconds"`
+}
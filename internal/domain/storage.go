@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// StoragePolicy — сроки хранения товаров в ПВЗ в днях по типам товара.
+type StoragePolicy struct {
+	DefaultDays int
+	Days        map[string]int
+}
+
+// PeriodFor возвращает срок хранения товара заданного типа.
+func (p StoragePolicy) PeriodFor(productType string) time.Duration {
+	days, ok := p.Days[productType]
+	if !ok {
+		days = p.DefaultDays
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// OverdueProduct — товар на хранении, срок хранения которого истек; его нужно вернуть отправителю.
+type OverdueProduct struct {
+	Id          uuid.UUID `json:"id"`
+	Type        string    `json:"type"`
+	Barcode     string    `json:"barcode,omitempty"`
+	ReceptionId uuid.UUID `json:"reception_id"`
+	StoredSince time.Time `json:"stored_since"`
+	DueAt       time.Time `json:"due_at"`
+	OverdueAt   time.Time `json:"overdue_at"`
+}
+
+// OverdueCount — число просроченных товаров в ПВЗ.
+type OverdueCount struct {
+	PVZId uuid.UUID
+	City  string
+	Count int
+}
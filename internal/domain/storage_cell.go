@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// StorageCell — ячейка хранения в ПВЗ. Code — адрес ячейки «стеллаж-полка-ячейка».
+// Пустой ProductTypes — ячейка принимает товары любого типа, Capacity nil — без ограничения.
+// Occupied — число товаров, которые сейчас лежат в ячейке.
+type StorageCell struct {
+	Id           uuid.UUID `json:"id"`
+	PVZId        uuid.UUID `json:"pvz_id"`
+	Code         string    `json:"code"`
+	Rack         string    `json:"rack"`
+	Shelf        string    `json:"shelf"`
+	Cell         string    `json:"cell"`
+	Capacity     *int      `json:"capacity,omitempty"`
+	ProductTypes []string  `json:"product_types"`
+	Occupied     int       `json:"occupied"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Accepts сообщает, можно ли класть в ячейку товары этого типа.
+func (c *StorageCell) Accepts(productType string) bool {
+	return len(c.ProductTypes) == 0 || slices.Contains(c.ProductTypes, productType)
+}
+
+// IsFull сообщает, заполнена ли ячейка до своей вместимости.
+func (c *StorageCell) IsFull() bool {
+	return c.Capacity != nil && c.Occupied >= *c.Capacity
+}
+
+// ProductLocation — где в ПВЗ лежит товар. Ячейка пуста, если товар принят без нее.
+type ProductLocation struct {
+	ProductId uuid.UUID  `json:"product_id"`
+	Type      string     `json:"type"`
+	Barcode   string     `json:"barcode,omitempty"`
+	Status    string     `json:"status"`
+	PVZId     uuid.UUID  `json:"pvz_id"`
+	CellId    *uuid.UUID `json:"cell_id,omitempty"`
+	CellCode  string     `json:"cell_code,omitempty"`
+}
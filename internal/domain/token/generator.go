@@ -7,5 +7,6 @@ import (
 
 type Generator interface {
 	CreateToken(userId uuid.UUID, role string) (string, error)
+	CreateDummyToken(userId uuid.UUID, role string, pvzIds []uuid.UUID) (string, error)
 	ValidateToken(tokenString string) (*jwt.Claims, error)
 }
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Transfer — перемещение товаров на хранении из одного ПВЗ в другой.
+type Transfer struct {
+	Id               uuid.UUID       `json:"id"`
+	SourcePVZId      uuid.UUID       `json:"source_pvz_id"`
+	DestinationPVZId uuid.UUID       `json:"destination_pvz_id"`
+	Status           string          `json:"status"`
+	DispatchedAt     time.Time       `json:"dispatched_at"`
+	ReceivedAt       *time.Time      `json:"received_at,omitempty"`
+	ReceptionId      *uuid.UUID      `json:"reception_id,omitempty"`
+	Items            []*TransferItem `json:"items"`
+	// PickupCodeChanges заполняется только в ответе на прием перемещения.
+	PickupCodeChanges []*PickupCodeChange `json:"pickup_code_changes,omitempty"`
+	// OverCapacity — перемещение принято сверх вместимости ПВЗ назначения (политика warn).
+	OverCapacity bool `json:"over_capacity,omitempty"`
+}
+
+// TransferItem хранит исходную приемку товара, чтобы история не терялась после перемещения.
+type TransferItem struct {
+	ProductId         uuid.UUID `json:"product_id"`
+	SourceReceptionId uuid.UUID `json:"source_reception_id"`
+}
+
+// PickupCodeChange — новый код получения товара. Выдается при приеме перемещения, если прежний код
+// уже занят в ПВЗ назначения; клиенту нужно сообщить новый код.
+type PickupCodeChange struct {
+	ProductId  uuid.UUID `json:"product_id"`
+	PickupCode string    `json:"pickup_code"`
+}
@@ -1,10 +1,30 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 type User struct {
-	Id       uuid.UUID `json:"id" validate:"uuid"`
-	Email    string    `json:"email" validate:"required,email"`
-	Password string    `json:"password,omitempty" validate:"required,min=6"`
-	Role     string    `json:"role" validate:"required,oneof=employee moderator"`
+	Id                  uuid.UUID  `json:"id"`
+	Email               string     `json:"email" validate:"required,email"`
+	Password            string     `json:"password,omitempty" validate:"required"`
+	Role                string     `json:"role" validate:"required,oneof=employee moderator auditor regional_manager"`
+	EmailVerified       bool       `json:"email_verified"`
+	Disabled            bool       `json:"disabled"`
+	LastLoginAt         *time.Time `json:"last_login_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	FailedLoginAttempts int        `json:"-"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+	// Dummy и PVZIds заполняются из dummy-токена: такого пользователя нет в БД,
+	// поэтому его ПВЗ берутся из токена, а не из таблицы закреплений.
+	Dummy  bool        `json:"-"`
+	PVZIds []uuid.UUID `json:"-"`
+}
+
+type UserList struct {
+	Users []*User `json:"users"`
+	Total int     `json:"total"`
+	Page  int     `json:"page"`
+	Limit int     `json:"limit"`
 }
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// WebhookSubscription — подписка партнера на доменные события. Пустые EventTypes и PVZIds — без фильтра.
+// Secret показывается только при создании: им подписываются доставки.
+type WebhookSubscription struct {
+	Id         uuid.UUID   `json:"id"`
+	URL        string      `json:"url"`
+	Secret     string      `json:"secret,omitempty"`
+	EventTypes []string    `json:"event_types"`
+	PVZIds     []uuid.UUID `json:"pvz_ids"`
+	CreatedBy  *uuid.UUID  `json:"created_by,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// WebhookDelivery — попытки доставить одно событие одному подписчику.
+type WebhookDelivery struct {
+	Id             uuid.UUID       `json:"id"`
+	SubscriptionId uuid.UUID       `json:"subscription_id"`
+	EventId        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"-"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+
+	// URL и Secret подписки заполняются для отправки и в ответ API не попадают.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
@@ -1,15 +1,20 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
-	ErrUnauthorized  = errors.New("unauthorized")
-	ErrForbidden     = errors.New("forbidden")
-	ErrBadRequest    = errors.New("bad request")
-	ErrValidation    = errors.New("validation error")
-	ErrNotFound      = errors.New("not found")
-	ErrAlreadyExists = errors.New("already exists")
-	ErrInternal      = errors.New("internal error")
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrForbidden        = errors.New("forbidden")
+	ErrBadRequest       = errors.New("bad request")
+	ErrValidation       = errors.New("validation error")
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrInternal         = errors.New("internal error")
+	ErrTooManyRequests  = errors.New("too many requests")
+	ErrPermissionDenied = errors.New("permission denied")
 
 	ErrUserRequired         = errors.New("user is required")
 	ErrUserAlreadyExists    = errors.New("user already exists")
@@ -23,6 +28,16 @@ var (
 	ErrCreatingToken        = errors.New("error creating token")
 	ErrMissingAuthFields    = errors.New("email, password or role is required")
 	ErrInvalidAuthFields    = errors.New("invalid email, password or type")
+	ErrAccountLocked        = errors.New("account is temporarily locked due to too many failed login attempts")
+	ErrWeakPassword         = errors.New("password is too weak")
+	ErrEmailNotVerified     = errors.New("email is not verified")
+	ErrInvalidVerification  = errors.New("invalid or expired verification token")
+	ErrSendingEmail         = errors.New("error sending email")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrUserDisabled         = errors.New("user is disabled")
+	ErrCannotModifySelf     = errors.New("moderator cannot change own role or status")
+	ErrGettingUsers         = errors.New("error getting users")
+	ErrUpdatingUser         = errors.New("error updating user")
 
 	ErrPVZIdRequired = errors.New("pvz id is required")
 	ErrPVZRequired   = errors.New("pvz is required")
@@ -30,14 +45,120 @@ var (
 	ErrCreatingPVZ   = errors.New("error creating pvz")
 	ErrGettingPVZs   = errors.New("error getting pvzs")
 
-	ErrGettingReceptions    = errors.New("error getting receptions")
-	ErrPVZHasOpenReception  = errors.New("pvz already has an open reception")
-	ErrCreatingReception    = errors.New("error creating reception")
-	ErrClosingLastReception = errors.New("error closing last reception")
+	ErrInvalidPVZCapacity  = errors.New("pvz capacity must be positive")
+	ErrInvalidVolume       = errors.New("product volume must be positive")
+	ErrPVZCapacityExceeded = errors.New("pvz capacity exceeded")
+	ErrUpdatingPVZ         = errors.New("error updating pvz")
+
+	ErrStorageCellsRequired       = errors.New("at least one storage cell is required")
+	ErrInvalidStorageCell         = errors.New("storage cell rack, shelf and cell are required and must not contain '-' or spaces")
+	ErrDuplicateStorageCell       = errors.New("storage cell with this code already exists")
+	ErrStorageCellNotFound        = errors.New("storage cell not found")
+	ErrStorageCellFull            = errors.New("storage cell is full")
+	ErrStorageCellNotSuitable     = errors.New("storage cell does not accept this product type")
+	ErrStorageCellNotEmpty        = errors.New("storage cell is not empty")
+	ErrProductIdOrBarcodeRequired = errors.New("product id or barcode is required")
+	ErrCreatingStorageCells       = errors.New("error creating storage cells")
+	ErrGettingStorageCells        = errors.New("error getting storage cells")
+	ErrDeletingStorageCell        = errors.New("error deleting storage cell")
+	ErrLocatingProduct            = errors.New("error locating product")
+
+	ErrPVZNotFound         = errors.New("pvz not found")
+	ErrPVZAccessDenied     = errors.New("employee is not assigned to this pvz")
+	ErrAssignNonEmployee   = errors.New("only employees can be assigned to pvz")
+	ErrAssignmentNotFound  = errors.New("employee is not assigned to this pvz")
+	ErrCheckingPVZAccess   = errors.New("error checking pvz access")
+	ErrUpdatingAssignments = errors.New("error updating pvz assignments")
+	ErrGettingAssignments  = errors.New("error getting pvz assignments")
+
+	ErrGettingReceptions     = errors.New("error getting receptions")
+	ErrPVZHasOpenReception   = errors.New("pvz already has an open reception")
+	ErrCreatingReception     = errors.New("error creating reception")
+	ErrClosingLastReception  = errors.New("error closing last reception")
+	ErrPVZHasOpenReturn      = errors.New("pvz already has an open return")
+	ErrNoOpenReturn          = errors.New("pvz has no open return")
+	ErrAddingProductToReturn = errors.New("error adding product to return")
+	ErrClosingLastReturn     = errors.New("error closing last return")
 
 	ErrGettingProducts             = errors.New("error getting products")
 	ErrPVZIdAndProductTypeRequired = errors.New("pvz id and product type is required")
 	ErrInvalidProductType          = errors.New("invalid product type")
 	ErrCreatingProduct             = errors.New("error creating product")
 	ErrDeletingLastProduct         = errors.New("error deleting last product from reception")
+	ErrProductIdRequired           = errors.New("product id is required")
+	ErrProductNotFound             = errors.New("product not found")
+	ErrProductNotStored            = errors.New("product is not stored at the pvz")
+	ErrPickupCodeRequired          = errors.New("pickup code is required")
+	ErrInvalidPickupCode           = errors.New("invalid pickup code")
+	ErrUpdatingProduct             = errors.New("error updating product")
+	ErrGettingInventory            = errors.New("error getting inventory")
+	ErrOrderNotFound               = errors.New("order not found")
+	ErrGettingOverdueProducts      = errors.New("error getting overdue products")
+
+	ErrManifestRequired          = errors.New("manifest is required")
+	ErrManifestItemsRequired     = errors.New("manifest must contain at least one item")
+	ErrDuplicateManifestBarcode  = errors.New("manifest contains duplicate barcodes")
+	ErrManifestNotFound          = errors.New("manifest not found")
+	ErrCreatingManifest          = errors.New("error creating manifest")
+	ErrGettingManifest           = errors.New("error getting manifest")
+	ErrReceptionNotFound         = errors.New("reception not found")
+	ErrReceptionNotClosed        = errors.New("reception is not closed yet")
+	ErrDiscrepancyReportNotFound = errors.New("reception has no manifest, discrepancy report is not available")
+	ErrGettingDiscrepancyReport  = errors.New("error getting discrepancy report")
+
+	ErrTransferNotFound            = errors.New("transfer not found")
+	ErrTransferSamePVZ             = errors.New("source and destination pvz must differ")
+	ErrTransferProductsRequired    = errors.New("transfer must contain at least one product")
+	ErrTransferProductsUnavailable = errors.New("some products are not stored at the source pvz")
+	ErrTransferAlreadyReceived     = errors.New("transfer is already received")
+	ErrNoOpenReception             = errors.New("pvz has no open reception")
+	ErrCreatingTransfer            = errors.New("error creating transfer")
+	ErrReceivingTransfer           = errors.New("error receiving transfer")
+	ErrGettingTransfers            = errors.New("error getting transfers")
+
+	ErrCorrectionReasonRequired = errors.New("correction reason is required")
+	ErrReceptionNotCorrectable  = errors.New("only inbound receptions can be reopened or corrected")
+	ErrNewerReceptionExists     = errors.New("pvz already has a newer or open reception")
+	ErrProductNotInReception    = errors.New("product is not stored in this reception")
+	ErrReopeningReception       = errors.New("error reopening reception")
+	ErrCorrectingReception      = errors.New("error correcting reception")
+	ErrGettingReceptionAudit    = errors.New("error getting reception audit")
+
+	ErrInvalidWebhookURL        = errors.New("webhook url must be an absolute http or https url")
+	ErrInvalidEventType         = errors.New("unknown event type")
+	ErrInvalidDeliveryStatus    = errors.New("invalid delivery status")
+	ErrWebhookNotFound          = errors.New("webhook subscription not found")
+	ErrCreatingWebhook          = errors.New("error creating webhook subscription")
+	ErrGettingWebhooks          = errors.New("error getting webhook subscriptions")
+	ErrDeletingWebhook          = errors.New("error deleting webhook subscription")
+	ErrGettingWebhookDeliveries = errors.New("error getting webhook deliveries")
+
+	ErrInvalidStatsGroupBy = errors.New("groupBy must be one of city, pvz, type")
+	ErrInvalidStatsBucket  = errors.New("bucket must be one of day, week, month")
+	ErrInvalidStatsPeriod  = errors.New("invalid stats period")
+	ErrGettingStats        = errors.New("error getting stats")
+	ErrGettingProductivity = errors.New("error getting productivity report")
+	ErrGettingSLABreaches  = errors.New("error getting reception sla breaches")
+
+	ErrInvalidExportPeriod = errors.New("export 'from' date must not be after 'to'")
+	ErrExportingReceptions = errors.New("error exporting receptions")
+
+	ErrInvalidPVZImportHeader = errors.New("csv header must contain city and address columns")
+	ErrPVZImportEmpty         = errors.New("csv contains no pvz rows")
+	ErrPVZImportTooLarge      = errors.New("csv contains too many rows")
+	ErrImportingPVZs          = errors.New("error importing pvzs")
 )
+
+// RetryAfterError оборачивает ошибку и сообщает, через сколько можно повторить запрос.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
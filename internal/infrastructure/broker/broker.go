@@ -0,0 +1,113 @@
+package broker
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"sync"
+)
+
+const defaultBuffer = 64
+
+// Broker раздает доменные события подписчикам внутри процесса. Publish не блокируется:
+// подписчик, который не успевает читать и заполнил буфер, отключается, чтобы медленный
+// клиент не задерживал остальных.
+type Broker struct {
+	buffer int
+
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+func New(buffer int) *Broker {
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+
+	return &Broker{
+		buffer: buffer,
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription — подписка на события, прошедшие filter. Канал Events закрывается при Close
+// или при отключении медленного подписчика; в последнем случае Lagged возвращает true.
+type Subscription struct {
+	broker *Broker
+	filter func(event *domain.Event) bool
+	events chan *domain.Event
+
+	once   sync.Once
+	lagged bool
+}
+
+// Subscribe подписывает на события, для которых filter возвращает true; nil — на все.
+func (b *Broker) Subscribe(filter func(event *domain.Event) bool) *Subscription {
+	sub := &Subscription{
+		broker: b,
+		filter: filter,
+		events: make(chan *domain.Event, b.buffer),
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Publish отправляет событие подходящим подписчикам.
+func (b *Broker) Publish(events ...*domain.Event) {
+	b.mu.RLock()
+	var slow []*Subscription
+	for _, event := range events {
+		for sub := range b.subs {
+			if sub.filter != nil && !sub.filter(event) {
+				continue
+			}
+
+			select {
+			case sub.events <- event:
+			default:
+				slow = append(slow, sub)
+			}
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range slow {
+		sub.close(true)
+	}
+}
+
+// Subscribers возвращает число активных подписок.
+func (b *Broker) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.subs)
+}
+
+func (s *Subscription) Events() <-chan *domain.Event {
+	return s.events
+}
+
+// Lagged сообщает, что подписка закрыта из-за переполнения буфера. Читать после закрытия Events.
+func (s *Subscription) Lagged() bool {
+	s.broker.mu.RLock()
+	defer s.broker.mu.RUnlock()
+
+	return s.lagged
+}
+
+func (s *Subscription) Close() {
+	s.close(false)
+}
+
+func (s *Subscription) close(lagged bool) {
+	s.once.Do(func() {
+		s.broker.mu.Lock()
+		delete(s.broker.subs, s)
+		s.lagged = lagged
+		close(s.events)
+		s.broker.mu.Unlock()
+	})
+}
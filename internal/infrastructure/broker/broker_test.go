@@ -0,0 +1,52 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	b := New(2)
+	pvzId := uuid.New()
+
+	all := b.Subscribe(nil)
+	filtered := b.Subscribe(func(event *domain.Event) bool { return event.PVZId == pvzId })
+
+	own := &domain.Event{Id: uuid.New(), PVZId: pvzId}
+	other := &domain.Event{Id: uuid.New(), PVZId: uuid.New()}
+	b.Publish(own, other)
+
+	assert.Equal(t, own, <-all.Events())
+	assert.Equal(t, other, <-all.Events())
+	assert.Equal(t, own, <-filtered.Events())
+	assert.Len(t, filtered.Events(), 0)
+
+	filtered.Close()
+	filtered.Close()
+	_, open := <-filtered.Events()
+	assert.False(t, open)
+	assert.False(t, filtered.Lagged())
+	assert.Equal(t, 1, b.Subscribers())
+}
+
+func TestBroker_SlowSubscriberIsDropped(t *testing.T) {
+	b := New(1)
+	slow := b.Subscribe(nil)
+	fast := b.Subscribe(nil)
+
+	b.Publish(&domain.Event{Id: uuid.New()})
+	<-fast.Events()
+	b.Publish(&domain.Event{Id: uuid.New()})
+
+	// Буфер медленного подписчика заполнен: он отключается, остальные получают событие.
+	<-slow.Events()
+	_, open := <-slow.Events()
+	assert.False(t, open)
+	assert.True(t, slow.Lagged())
+
+	assert.Len(t, fast.Events(), 1)
+	assert.Equal(t, 1, b.Subscribers())
+}
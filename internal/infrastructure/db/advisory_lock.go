@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"hash/fnv"
+	"time"
+)
+
+// AdvisoryLocker выполняет задачу один раз за интервал на все экземпляры сервиса. Запуск отмечается
+// в таблице scheduler_runs, а session-level advisory lock не дает долгому запуску пересечься со следующим.
+type AdvisoryLocker struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdvisoryLocker(pool *pgxpool.Pool) *AdvisoryLocker {
+	return &AdvisoryLocker{pool: pool}
+}
+
+// TryRun вызывает fn, если в текущем интервале задачу name еще не запускал ни один экземпляр
+// и блокировка по имени свободна. Иначе возвращает false.
+func (l *AdvisoryLocker) TryRun(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) (bool, error) {
+	// Блокировка живет в сессии, поэтому захват и освобождение идут через одно соединение.
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	key := lockKey(name)
+
+	var locked bool
+	if err = conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	defer func() {
+		// ctx может быть уже отменен, а блокировку нужно снять до возврата соединения в пул.
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, key)
+	}()
+
+	// Номер интервала считается по часам БД, чтобы расхождение часов реплик не давало лишних запусков.
+	claim := `
+		INSERT INTO scheduler_runs (name, slot, started_at)
+		VALUES ($1, floor(extract(epoch FROM now()) / $2)::bigint, now())
+		ON CONFLICT (name) DO UPDATE
+		SET slot = EXCLUDED.slot, started_at = EXCLUDED.started_at
+		WHERE scheduler_runs.slot < EXCLUDED.slot
+	`
+
+	cmdTag, err := conn.Exec(ctx, claim, name, interval.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim job run: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	return true, fn(ctx)
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("pvz-service:" + name))
+	return int64(h.Sum64())
+}
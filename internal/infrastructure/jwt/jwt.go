@@ -17,16 +17,32 @@ func NewJWTGenerator() *TokenGenerator {
 type Claims struct {
 	UserId uuid.UUID `json:"user_id"`
 	Role   string    `json:"role"`
+	// Dummy помечает токены, выпущенные через /dummyLogin без реального пользователя.
+	Dummy bool `json:"dummy,omitempty"`
+	// PVZIds — ПВЗ, к которым допущен dummy-сотрудник. Для реальных пользователей закрепления хранятся в БД.
+	PVZIds []uuid.UUID `json:"pvz_ids,omitempty"`
 	jwt.RegisteredClaims
 }
 
 func (g *TokenGenerator) CreateToken(userId uuid.UUID, role string) (string, error) {
-	claims := &Claims{
+	return g.sign(&Claims{
 		UserId: userId,
 		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-		},
+	})
+}
+
+func (g *TokenGenerator) CreateDummyToken(userId uuid.UUID, role string, pvzIds []uuid.UUID) (string, error) {
+	return g.sign(&Claims{
+		UserId: userId,
+		Role:   role,
+		Dummy:  true,
+		PVZIds: pvzIds,
+	})
+}
+
+func (g *TokenGenerator) sign(claims *Claims) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
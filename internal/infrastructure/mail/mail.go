@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"log"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender отправляет письма пользователям. Реализация выбирается при старте
+// сервиса: для локального запуска достаточно LogSender, в проде — SMTP или
+// HTTP API почтового провайдера.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type logSender struct {
+	logger *log.Logger
+}
+
+// NewLogSender возвращает Sender, который только пишет письма в лог.
+func NewLogSender(logger *log.Logger) Sender {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &logSender{logger: logger}
+}
+
+func (s *logSender) Send(ctx context.Context, msg Message) error {
+	s.logger.Printf("mail to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry хранит метрики сервиса и отдает их в текстовом формате Prometheus.
+type Registry struct {
+	mu     sync.RWMutex
+	gauges []*GaugeVec
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// NewGaugeVec регистрирует gauge с заданными метками.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	gauge := &GaugeVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		samples: make(map[string]*sample),
+	}
+
+	r.mu.Lock()
+	r.gauges = append(r.gauges, gauge)
+	r.mu.Unlock()
+
+	return gauge
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		for _, gauge := range r.gauges {
+			gauge.write(w)
+		}
+	})
+}
+
+// Serve отдает метрики на /metrics по отдельному адресу.
+func Serve(addr string, registry *Registry) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+
+	return http.ListenAndServe(addr, mux)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type sample struct {
+	labelValues []string
+	value       float64
+}
+
+type GaugeVec struct {
+	name    string
+	help    string
+	labels  []string
+	mu      sync.Mutex
+	samples map[string]*sample
+}
+
+// Set задает значение для набора значений меток в порядке, заданном при регистрации.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	if len(labelValues) != len(g.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", g.name, len(g.labels), len(labelValues)))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	g.samples[key] = &sample{labelValues: labelValues, value: value}
+}
+
+// Reset удаляет все значения, например перед полным пересчетом.
+func (g *GaugeVec) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.samples = make(map[string]*sample)
+}
+
+func (g *GaugeVec) write(w http.ResponseWriter) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", g.name, g.help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", g.name)
+
+	keys := make([]string, 0, len(g.samples))
+	for key := range g.samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := g.samples[key]
+		fmt.Fprintf(w, "%s%s %s\n", g.name, formatLabels(g.labels, s.labelValues), strconv.FormatFloat(s.value, 'g', -1, 64))
+	}
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(values[i]))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Handler(t *testing.T) {
+	registry := NewRegistry()
+	gauge := registry.NewGaugeVec("pvz_overdue_products", "Overdue products per PVZ.", "pvz_id", "city")
+
+	gauge.Set(3, "b", "Москва")
+	gauge.Set(1, "a", `Ка"зань`)
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	expected := "# HELP pvz_overdue_products Overdue products per PVZ.\n" +
+		"# TYPE pvz_overdue_products gauge\n" +
+		"pvz_overdue_products{pvz_id=\"a\",city=\"Ка\\\"зань\"} 1\n" +
+		"pvz_overdue_products{pvz_id=\"b\",city=\"Москва\"} 3\n"
+	assert.Equal(t, expected, rec.Body.String())
+
+	gauge.Reset()
+	rec = httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, "# HELP pvz_overdue_products Overdue products per PVZ.\n# TYPE pvz_overdue_products gauge\n", rec.Body.String())
+}
+
+func TestGaugeVec_SetPanicsOnLabelMismatch(t *testing.T) {
+	gauge := NewRegistry().NewGaugeVec("test", "test", "a")
+
+	assert.Panics(t, func() { gauge.Set(1) })
+}
@@ -0,0 +1,121 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Publisher доставляет доменные события во внешние системы. Реализация выбирается
+// при старте сервиса в секции events конфига. Ошибка означает, что событие
+// нужно отправить повторно.
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.Event) error
+}
+
+type multiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMulti публикует событие через все publishers. Событие считается опубликованным, только если
+// его приняли все, поэтому при повторе его получат и те, кто уже принял.
+func NewMulti(publishers ...Publisher) Publisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	var errs []error
+	for _, pub := range p.publishers {
+		if err := pub.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+type writerPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterPublisher возвращает Publisher, который пишет события в w по одному JSON на строку.
+func NewWriterPublisher(w io.Writer) Publisher {
+	return &writerPublisher{w: w}
+}
+
+// NewFilePublisher дописывает события в файл path.
+func NewFilePublisher(path string) (Publisher, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("events file could not be opened: %w", err)
+	}
+
+	return NewWriterPublisher(f), nil
+}
+
+func (p *writerPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.w.Write(append(data, '\n'))
+	return err
+}
+
+const defaultWebhookTimeout = 10 * time.Second
+
+type webhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookPublisher возвращает Publisher, который отправляет событие POST-запросом на url.
+// Ответ не 2xx считается ошибкой. Заголовок X-Event-Id позволяет получателю отбрасывать повторы.
+func NewWebhookPublisher(url string, client *http.Client) Publisher {
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+
+	return &webhookPublisher{url: url, client: client}
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", event.Id.String())
+	req.Header.Set("X-Event-Type", event.Type)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEvent() *domain.Event {
+	return &domain.Event{
+		Id:          uuid.New(),
+		Type:        "reception.opened",
+		PVZId:       uuid.New(),
+		AggregateId: uuid.New(),
+		OccurredAt:  time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC),
+		Payload:     json.RawMessage(`{"direction":"inbound"}`),
+	}
+}
+
+func TestWriterPublisher_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	event := testEvent()
+
+	err := NewWriterPublisher(&buf).Publish(context.Background(), event)
+	assert.NoError(t, err)
+
+	var got domain.Event
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, event.Id, got.Id)
+	assert.JSONEq(t, string(event.Payload), string(got.Payload))
+	assert.Equal(t, byte('\n'), buf.Bytes()[buf.Len()-1])
+}
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectError bool
+	}{
+		{name: "Success", status: http.StatusAccepted},
+		{name: "Server error", status: http.StatusInternalServerError, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := testEvent()
+
+			var received domain.Event
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, event.Id.String(), r.Header.Get("X-Event-Id"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookPublisher(server.URL, server.Client()).Publish(context.Background(), event)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, event.Id, received.Id)
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign("secret", 1746360000, body)
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, Verify("secret", 1746360000, body, signature))
+	assert.False(t, Verify("other", 1746360000, body, signature))
+	assert.False(t, Verify("secret", 1746360001, body, signature))
+}
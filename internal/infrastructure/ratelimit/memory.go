@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const cleanupInterval = time.Minute
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// memoryLimiter — token bucket в памяти процесса: у каждого ключа есть burst
+// токенов, которые восполняются равномерно со скоростью burst за period.
+type memoryLimiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	burst       float64
+	rate        float64
+	now         func() time.Time
+	lastCleanup time.Time
+}
+
+func NewMemoryLimiter(burst int, period time.Duration) Limiter {
+	return newMemoryLimiter(burst, period, time.Now)
+}
+
+func newMemoryLimiter(burst int, period time.Duration, now func() time.Time) *memoryLimiter {
+	return &memoryLimiter{
+		buckets:     make(map[string]*bucket),
+		burst:       float64(burst),
+		rate:        float64(burst) / period.Seconds(),
+		now:         now,
+		lastCleanup: now(),
+	}
+}
+
+func (l *memoryLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.cleanup(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.tokens = math.Min(l.burst, b.tokens+elapsed*l.rate)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait, nil
+}
+
+// cleanup удаляет полностью восполненные корзины, чтобы карта не росла бесконечно.
+func (l *memoryLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < cleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.lastSeen).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
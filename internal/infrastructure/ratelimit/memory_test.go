@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	now := time.Now()
+	limiter := newMemoryLimiter(2, time.Minute, func() time.Time { return now })
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := limiter.Allow(ctx, "key")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, retryAfter, err := limiter.Allow(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 30*time.Second, retryAfter)
+
+	allowed, _, err = limiter.Allow(ctx, "other-key")
+	assert.NoError(t, err)
+	assert.True(t, allowed, "keys must be limited independently")
+
+	now = now.Add(30 * time.Second)
+	allowed, _, err = limiter.Allow(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, allowed, "token must be refilled after retryAfter")
+}
+
+func TestMemoryLimiter_Cleanup(t *testing.T) {
+	now := time.Now()
+	limiter := newMemoryLimiter(1, time.Second, func() time.Time { return now })
+
+	_, _, _ = limiter.Allow(context.Background(), "key")
+	assert.Len(t, limiter.buckets, 1)
+
+	now = now.Add(cleanupInterval)
+	_, _, _ = limiter.Allow(context.Background(), "other-key")
+	assert.NotContains(t, limiter.buckets, "key")
+}
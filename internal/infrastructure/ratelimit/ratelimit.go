@@ -0,0 +1,15 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limiter ограничивает частоту запросов по произвольному ключу (IP, email и т.д.).
+// Реализация может хранить состояние в памяти процесса или во внешнем
+// хранилище (например, Redis), чтобы лимиты разделялись между репликами.
+type Limiter interface {
+	// Allow расходует одну попытку для ключа. Если лимит исчерпан, возвращает
+	// false и время, через которое можно повторить запрос.
+	Allow(ctx context.Context, key string) (bool, time.Duration, error)
+}
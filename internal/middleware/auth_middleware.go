@@ -2,18 +2,35 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/aliskhannn/pvz-service/internal/domain/token"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"net/http"
 	"strings"
 )
 
+// UserProvider загружает актуальное состояние пользователя по id из токена.
+type UserProvider interface {
+	GetUserById(ctx context.Context, userId uuid.UUID) (*domain.User, error)
+}
+
+type AuthConfig struct {
+	// AllowDummy разрешает токены, выпущенные через /dummyLogin.
+	AllowDummy bool
+	// Users, если задан, используется на каждый запрос: отключенные и удаленные
+	// пользователи отклоняются даже с валидным токеном, роль берется из БД.
+	Users UserProvider
+}
+
 func GetUserFromContext(ctx context.Context) (*domain.User, bool) {
 	user, ok := ctx.Value("user").(*domain.User)
 	return user, ok
 }
 
-func AuthMiddleware(tokenGen token.Generator) func(http.Handler) http.Handler {
+// AuthMiddleware проверяет JWT и кладет пользователя в контекст запроса.
+func AuthMiddleware(tokenGen token.Generator, cfg AuthConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -22,22 +39,33 @@ func AuthMiddleware(tokenGen token.Generator) func(http.Handler) http.Handler {
 				return
 			}
 
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "not authorized", http.StatusUnauthorized)
+			user, status, message := authenticate(r.Context(), tokenGen, cfg, authHeader)
+			if user == nil {
+				http.Error(w, message, status)
 				return
 			}
 
-			tokenString := parts[1]
-			claims, err := tokenGen.ValidateToken(tokenString)
-			if err != nil {
-				http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
+			ctx := context.WithValue(r.Context(), "user", user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OptionalAuthMiddleware работает как AuthMiddleware, но пропускает запросы без
+// заголовка Authorization анонимно. Невалидный токен по-прежнему отклоняется.
+func OptionalAuthMiddleware(tokenGen token.Generator, cfg AuthConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			user := &domain.User{
-				Id:   claims.UserId,
-				Role: claims.Role,
+			user, status, message := authenticate(r.Context(), tokenGen, cfg, authHeader)
+			if user == nil {
+				http.Error(w, message, status)
+				return
 			}
 
 			ctx := context.WithValue(r.Context(), "user", user)
@@ -45,3 +73,49 @@ func AuthMiddleware(tokenGen token.Generator) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+func authenticate(ctx context.Context, tokenGen token.Generator, cfg AuthConfig, authHeader string) (*domain.User, int, string) {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, http.StatusUnauthorized, "not authorized"
+	}
+
+	tokenString := parts[1]
+	claims, err := tokenGen.ValidateToken(tokenString)
+	if err != nil {
+		return nil, http.StatusUnauthorized, "invalid token: " + err.Error()
+	}
+
+	if claims.Dummy && !cfg.AllowDummy {
+		return nil, http.StatusUnauthorized, "dummy tokens are not accepted"
+	}
+
+	user := &domain.User{
+		Id:     claims.UserId,
+		Role:   claims.Role,
+		Dummy:  claims.Dummy,
+		PVZIds: claims.PVZIds,
+	}
+
+	// У dummy-токенов нет пользователя в БД.
+	if cfg.Users == nil || claims.Dummy {
+		return user, http.StatusOK, ""
+	}
+
+	stored, err := cfg.Users.GetUserById(ctx, claims.UserId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, http.StatusUnauthorized, "user not found"
+		}
+		return nil, http.StatusInternalServerError, "internal error"
+	}
+
+	if stored.Disabled {
+		return nil, http.StatusForbidden, "user is disabled"
+	}
+
+	user.Email = stored.Email
+	user.Role = stored.Role
+
+	return user, http.StatusOK, ""
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/jwt"
+	"github.com/aliskhannn/pvz-service/internal/usecase/mocks"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		header         string
+		claims         *jwt.Claims
+		validateErr    error
+		allowDummy     bool
+		expectedStatus int
+	}{
+		{
+			name:           "Valid token",
+			header:         "Bearer valid",
+			claims:         &jwt.Claims{UserId: userId, Role: constants.UserRoleEmployee},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing header",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid token",
+			header:         "Bearer invalid",
+			claims:         (*jwt.Claims)(nil),
+			validateErr:    errors.New("invalid token"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Dummy token in debug mode",
+			header:         "Bearer dummy",
+			claims:         &jwt.Claims{UserId: userId, Role: constants.UserRoleModerator, Dummy: true},
+			allowDummy:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Dummy token in release mode",
+			header:         "Bearer dummy",
+			claims:         &jwt.Claims{UserId: userId, Role: constants.UserRoleModerator, Dummy: true},
+			allowDummy:     false,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &mocks.MockJWTGenerator{}
+			if tt.claims != nil || tt.validateErr != nil {
+				tokens.On("ValidateToken", tt.header[len("Bearer "):]).Return(tt.claims, tt.validateErr).Once()
+			}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, ok := GetUserFromContext(r.Context())
+				assert.True(t, ok)
+				assert.Equal(t, userId, user.Id)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			AuthMiddleware(tokens, AuthConfig{AllowDummy: tt.allowDummy})(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			tokens.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthMiddleware_UserState(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		stored         *domain.User
+		storedErr      error
+		expectedStatus int
+		expectedRole   string
+	}{
+		{
+			name:           "Active user gets role from database",
+			stored:         &domain.User{Id: userId, Role: constants.UserRoleModerator},
+			expectedStatus: http.StatusOK,
+			expectedRole:   constants.UserRoleModerator,
+		},
+		{
+			name:           "Disabled user",
+			stored:         &domain.User{Id: userId, Role: constants.UserRoleEmployee, Disabled: true},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Deleted user",
+			storedErr:      pgx.ErrNoRows,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &mocks.MockJWTGenerator{}
+			tokens.On("ValidateToken", "valid").
+				Return(&jwt.Claims{UserId: userId, Role: constants.UserRoleEmployee}, nil).
+				Once()
+
+			users := &repository_mocks.MockUserRepository{}
+			users.On("GetUserById", mock.Anything, userId).Return(tt.stored, tt.storedErr).Once()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, _ := GetUserFromContext(r.Context())
+				assert.Equal(t, tt.expectedRole, user.Role)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer valid")
+			rec := httptest.NewRecorder()
+
+			AuthMiddleware(tokens, AuthConfig{Users: users})(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			users.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenFromQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		header       string
+		expectHeader string
+		expectQuery  string
+	}{
+		{
+			name:         "Token from query",
+			target:       "/events?city=Kazan&access_token=abc",
+			expectHeader: "Bearer abc",
+			expectQuery:  "city=Kazan",
+		},
+		{
+			name:         "Header wins",
+			target:       "/events?access_token=abc",
+			header:       "Bearer header",
+			expectHeader: "Bearer header",
+			expectQuery:  "access_token=abc",
+		},
+		{
+			name:   "No token",
+			target: "/events",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotHeader, gotQuery string
+			handler := TokenFromQuery("access_token")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotHeader = r.Header.Get("Authorization")
+				gotQuery = r.URL.RawQuery
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expectHeader, gotHeader)
+			assert.Equal(t, tt.expectQuery, gotQuery)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// TokenFromQuery переносит JWT из параметра param в заголовок Authorization, если заголовка нет.
+// Нужен для EventSource в браузере, который не умеет передавать заголовки. Параметр удаляется из
+// URL, чтобы токен не попал в логи дальше по цепочке.
+func TokenFromQuery(param string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if tokenString := query.Get(param); tokenString != "" && r.Header.Get("Authorization") == "" {
+				r = r.Clone(r.Context())
+				r.Header.Set("Authorization", "Bearer "+tokenString)
+				query.Del(param)
+				r.URL.RawQuery = query.Encode()
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/ratelimit"
+	"net"
+	"net/http"
+)
+
+// RateLimitByIP ограничивает частоту запросов с одного IP-адреса.
+// prefix отделяет счетчики разных эндпоинтов друг от друга.
+func RateLimitByIP(limiter ratelimit.Limiter, prefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, retryAfter, err := limiter.Allow(r.Context(), prefix+":"+clientIP(r))
+			if err != nil {
+				response.WriteJSONError(w, http.StatusInternalServerError, "internal error")
+				return
+			}
+
+			if !allowed {
+				response.WriteTooManyRequests(w, retryAfter)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
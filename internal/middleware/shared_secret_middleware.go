@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/aliskhannn/pvz-service/internal/delivery/http/response"
+	"net/http"
+)
+
+// SharedSecret пропускает запрос, только если заголовок header совпадает с secret.
+// Пустой secret отключает проверку.
+func SharedSecret(header, secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(header)), []byte(secret)) != 1 {
+				response.WriteJSONError(w, http.StatusForbidden, "access denied")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrPickupCodeTaken возвращается, если код получения уже выдан другому товару в этом ПВЗ.
+	ErrPickupCodeTaken = errors.New("pickup code already taken")
+	// ErrProductsUnavailable возвращается, если часть товаров не лежит на хранении в нужном ПВЗ.
+	ErrProductsUnavailable = errors.New("products are not available")
+	// ErrNoOpenReception возвращается, если в ПВЗ нет открытой приемки.
+	ErrNoOpenReception = errors.New("no open reception")
+	// ErrReceptionNotClosed возвращается, если приемка, которую исправляют, не закрыта.
+	ErrReceptionNotClosed = errors.New("reception is not closed")
+	// ErrNewerReceptionExists возвращается при переоткрытии, если в ПВЗ уже есть более новая или открытая приемка.
+	ErrNewerReceptionExists = errors.New("newer reception exists")
+	// ErrStorageCellTaken возвращается, если ячейка с таким адресом в ПВЗ уже есть.
+	ErrStorageCellTaken = errors.New("storage cell code already taken")
+	// ErrStorageCellNotEmpty возвращается при удалении ячейки, в которой лежат товары.
+	ErrStorageCellNotEmpty = errors.New("storage cell is not empty")
+)
@@ -7,25 +7,214 @@ import (
 	"time"
 )
 
+// TxManager выполняет fn в транзакции: репозитории, вызванные с переданным в fn ctx, работают в ней.
+// Вложенный вызов переиспользует уже открытую транзакцию.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// OutboxRepository хранит доменные события до их публикации.
+type OutboxRepository interface {
+	// Add сохраняет событие; вызывается в транзакции вместе с изменением, которое оно описывает.
+	// До конца транзакции блокирует запись событий того же ПВЗ, чтобы их порядок совпадал с порядком фиксации.
+	Add(ctx context.Context, event *domain.Event) error
+	// GetPending возвращает неопубликованные события в порядке записи. События ПВЗ, первое из которых
+	// ждет повтора позже now, не возвращаются.
+	GetPending(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, seq int64) error
+	MarkFailed(ctx context.Context, seq int64, reason string, nextAttemptAt time.Time) error
+}
+
+// WebhookRepository хранит подписки на вебхуки и журнал доставок.
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+	GetSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	// DeleteSubscription удаляет подписку вместе с ее доставками; pgx.ErrNoRows, если подписки нет.
+	DeleteSubscription(ctx context.Context, subscriptionId uuid.UUID) error
+	// EnqueueDeliveries создает доставки события для подходящих подписок. Повторная публикация
+	// того же события новых доставок не создает.
+	EnqueueDeliveries(ctx context.Context, event *domain.Event) error
+	// GetDueDeliveries возвращает ожидающие доставки, время попытки которых наступило, с URL и секретом подписки.
+	GetDueDeliveries(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error)
+	// SaveAttempt сохраняет результат попытки доставки: статус, число попыток и время следующей.
+	SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+	// GetDeliveries возвращает журнал доставок подписки; pgx.ErrNoRows, если подписки нет.
+	GetDeliveries(ctx context.Context, subscriptionId uuid.UUID, status string, offset, limit int) ([]*domain.WebhookDelivery, error)
+}
+
+// StatsRepository считает аналитику по приемкам и товарам агрегатами на стороне БД.
+type StatsRepository interface {
+	// GetIntake возвращает число приемок и товаров по периодам filter.Bucket и группам filter.GroupBy.
+	GetIntake(ctx context.Context, filter domain.IntakeFilter) ([]*domain.IntakeStat, error)
+	// GetProductivity считает по журналу действий с товарами работу каждого сотрудника в каждом ПВЗ.
+	// DeletionRate не заполняется.
+	GetProductivity(ctx context.Context, filter domain.ProductivityFilter) ([]*domain.EmployeeProductivity, error)
+}
+
+// ExportRepository построчно читает большие выгрузки, не загружая их в память целиком.
+type ExportRepository interface {
+	// StreamReceptionProducts вызывает fn для каждого товара из приемок filter в порядке открытия приемок.
+	// Строка переиспользуется между вызовами, fn не должна ее сохранять. Ошибка fn прерывает чтение
+	// и возвращается как есть.
+	StreamReceptionProducts(ctx context.Context, filter domain.ReceptionExportFilter, fn func(*domain.ReceptionExportRow) error) error
+}
+
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	RegisterFailedLogin(ctx context.Context, userId uuid.UUID, maxAttempts int, lockDuration time.Duration) error
+	ResetFailedLogins(ctx context.Context, userId uuid.UUID) error
+	CreateVerificationToken(ctx context.Context, userId uuid.UUID, tokenHash string, expiresAt time.Time) error
+	VerifyEmail(ctx context.Context, tokenHash string, now time.Time) error
+	GetUserById(ctx context.Context, userId uuid.UUID) (*domain.User, error)
+	ListUsers(ctx context.Context, search string, offset, limit int) ([]*domain.User, int, error)
+	UpdateUserRole(ctx context.Context, userId uuid.UUID, role string) error
+	SetUserDisabled(ctx context.Context, userId uuid.UUID, disabled bool) error
+	UpdatePassword(ctx context.Context, userId uuid.UUID, password string) error
+	UpdateLastLogin(ctx context.Context, userId uuid.UUID, at time.Time) error
+}
+
+// RoleRepository возвращает роли и их права: роль -> список прав.
+type RoleRepository interface {
+	GetRolePermissions(ctx context.Context) (map[string][]string, error)
+}
+
+// AssignmentRepository хранит закрепление сотрудников за ПВЗ.
+type AssignmentRepository interface {
+	AssignPVZ(ctx context.Context, userId, pvzId uuid.UUID) error
+	UnassignPVZ(ctx context.Context, userId, pvzId uuid.UUID) error
+	GetUserPVZIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+	IsAssigned(ctx context.Context, userId, pvzId uuid.UUID) (bool, error)
 }
 
 type PVZRepository interface {
+	// CreatePVZ создает ПВЗ и заполняет его id и дату регистрации.
 	CreatePVZ(ctx context.Context, pvz *domain.PVZ) error
+	// FindPVZsByAddress возвращает существующие ПВЗ с теми же городом и адресом (без учета регистра), что у pvzs.
+	FindPVZsByAddress(ctx context.Context, pvzs []*domain.PVZ) ([]*domain.PVZ, error)
+	// GetAllPVZs возвращает страницу ПВЗ вместе с их вместимостью и текущей заполненностью.
 	GetAllPVZs(ctx context.Context, offset, limit int) ([]*domain.PVZ, error)
+	// UpdatePVZCapacity задает вместимость ПВЗ; nil снимает ограничение. Если ПВЗ нет — pgx.ErrNoRows.
+	UpdatePVZCapacity(ctx context.Context, pvzId uuid.UUID, items *int, volume *float64) (*domain.PVZ, error)
+	GetPVZIdsByCity(ctx context.Context, city string) ([]uuid.UUID, error)
 	GetReceptionsByPVZId(ctx context.Context, pvzId uuid.UUID, startDate, endDate time.Time) ([]*domain.Reception, error)
 	GetAllProductsFromReception(ctx context.Context, receptionId uuid.UUID) ([]*domain.Product, error)
 }
 
 type ReceptionRepository interface {
+	// CreateReception создает приемку; к входящей приемке привязывается самый старый ожидающий манифест ПВЗ.
 	CreateReception(ctx context.Context, reception *domain.Reception) error
-	CloseLastReception(ctx context.Context, pvzId uuid.UUID) error
-	HasOpenReception(ctx context.Context, pvzId uuid.UUID) (bool, error)
+	// CloseLastReception закрывает открытую входящую приемку ПВЗ и возвращает ее id.
+	CloseLastReception(ctx context.Context, pvzId uuid.UUID) (uuid.UUID, error)
+	// CloseStaleReceptions закрывает открытые входящие приемки без поступлений и переоткрытия дольше idleFor,
+	// отмечая их как закрытые автоматически, и возвращает их (заполнены id и ПВЗ).
+	CloseStaleReceptions(ctx context.Context, idleFor time.Duration, reason string) ([]*domain.Reception, error)
+	HasOpenReception(ctx context.Context, pvzId uuid.UUID, direction string) (bool, error)
+	// AddProductToReturn добавляет товар на хранении в открытую возвратную партию ПВЗ. Возвращает
+	// ErrNoOpenReception без открытой партии и ErrProductsUnavailable, если товара нет на хранении в ПВЗ.
+	AddProductToReturn(ctx context.Context, pvzId, productId, userId uuid.UUID) error
+	// CloseLastReturn закрывает открытую возвратную партию; ее товары считаются возвращенными.
+	CloseLastReturn(ctx context.Context, pvzId uuid.UUID) (uuid.UUID, error)
+	GetReceptionById(ctx context.Context, receptionId uuid.UUID) (*domain.Reception, error)
+	// GetReceptionBarcodes возвращает штрихкоды товаров приемки, кроме удаленных исправлением;
+	// у товаров без штрихкода — пустая строка.
+	GetReceptionBarcodes(ctx context.Context, receptionId uuid.UUID) ([]string, error)
+	// GetOpenReceptions возвращает открытые входящие приемки с городом, числом товаров и возрастом
+	// по часам БД. SLA не заполняется.
+	GetOpenReceptions(ctx context.Context) ([]*domain.OpenReception, error)
+}
+
+// TransferRepository хранит перемещения товаров между ПВЗ.
+type TransferRepository interface {
+	// CreateTransfer переводит товары источника в in_transit и возвращает ErrProductsUnavailable,
+	// если хотя бы один товар не лежит на хранении в ПВЗ-источнике.
+	CreateTransfer(ctx context.Context, transfer *domain.Transfer, productIds []uuid.UUID, userId uuid.UUID) error
+	// LockDispatchedTransfer блокирует отправленное перемещение до конца транзакции и возвращает его
+	// вместе с позициями. Возвращает pgx.ErrNoRows, если перемещение не найдено или уже принято.
+	LockDispatchedTransfer(ctx context.Context, transferId uuid.UUID) (*domain.Transfer, error)
+	// GetPickupCodeConflicts возвращает товары перемещения, коды получения которых уже заняты в ПВЗ назначения.
+	GetPickupCodeConflicts(ctx context.Context, transferId uuid.UUID) ([]uuid.UUID, error)
+	// ReissuePickupCode меняет код получения товара перемещения. Возвращает ErrPickupCodeTaken, если код
+	// занят в ПВЗ назначения или другим товаром того же перемещения.
+	ReissuePickupCode(ctx context.Context, transferId, productId uuid.UUID, pickupCode string) error
+	// GetTransferVolume возвращает суммарный объем товаров перемещения; товары без объема не учитываются.
+	GetTransferVolume(ctx context.Context, transferId uuid.UUID) (float64, error)
+	// ReceiveTransfer переносит товары в открытую приемку ПВЗ назначения и заполняет у transfer статус,
+	// время и приемку. Возвращает ErrNoOpenReception, если в ПВЗ назначения нет открытой приемки,
+	// и ErrPickupCodeTaken, если код товара оказался занят.
+	ReceiveTransfer(ctx context.Context, transfer *domain.Transfer, userId uuid.UUID) error
+	GetTransferById(ctx context.Context, transferId uuid.UUID) (*domain.Transfer, error)
+	GetTransfersByPVZId(ctx context.Context, pvzId uuid.UUID, offset, limit int) ([]*domain.Transfer, error)
+}
+
+// ManifestRepository хранит ожидаемые поставки и отчеты о расхождениях по ним.
+type ManifestRepository interface {
+	// CreateManifest возвращает pgx.ErrNoRows, если ПВЗ не существует.
+	CreateManifest(ctx context.Context, manifest *domain.Manifest, createdBy uuid.UUID) error
+	GetManifestById(ctx context.Context, manifestId uuid.UUID) (*domain.Manifest, error)
+	GetManifestByReceptionId(ctx context.Context, receptionId uuid.UUID) (*domain.Manifest, error)
+	SaveDiscrepancyReport(ctx context.Context, report *domain.DiscrepancyReport) error
+	GetDiscrepancyReport(ctx context.Context, receptionId uuid.UUID) (*domain.DiscrepancyReport, error)
+}
+
+// ReceptionCorrectionRepository исправляет закрытые входящие приемки. Каждое изменение
+// сохраняется в истории приемки в той же транзакции; нулевой userId сохраняется как NULL.
+// Методы выполняются в транзакции из контекста, если она есть.
+type ReceptionCorrectionRepository interface {
+	// ReopenReception возвращает ErrReceptionNotClosed для открытой приемки и ErrNewerReceptionExists,
+	// если в ПВЗ есть более новая или открытая приемка.
+	ReopenReception(ctx context.Context, receptionId, userId uuid.UUID, reason string) error
+	// AddProduct добавляет в закрытую приемку товар сразу на хранение и пишет его в журнал действий.
+	// Возвращает ErrPickupCodeTaken, если код уже занят в ПВЗ.
+	AddProduct(ctx context.Context, receptionId uuid.UUID, productType, barcode, pickupCode string, userId uuid.UUID, reason string) (*domain.Product, error)
+	// RemoveProduct переводит товар на хранении в статус removed_by_correction с причиной; запись
+	// о товаре остается. Возвращает ErrProductsUnavailable, если товара нет в приемке или он уже не на хранении.
+	RemoveProduct(ctx context.Context, receptionId, productId, userId uuid.UUID, reason string) (*domain.Product, error)
+	GetAudit(ctx context.Context, receptionId uuid.UUID) ([]*domain.ReceptionAuditEntry, error)
 }
 
 type ProductRepository interface {
-	AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType string) error
-	DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID) error
+	// AddProductToReception возвращает ErrPickupCodeTaken, если код уже занят в этом ПВЗ.
+	// userId записывается автором товара и в журнал действий с товарами; нулевой сохраняется как NULL.
+	// volume — объем товара в литрах, nil — неизвестен.
+	AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType, barcode, pickupCode string, volume *float64, userId uuid.UUID) (*domain.Product, error)
+	// LockPVZCapacity блокирует ПВЗ до конца транзакции и возвращает его вместимость и заполненность,
+	// чтобы параллельные добавления товаров не превысили вместимость. Если ПВЗ нет — pgx.ErrNoRows.
+	LockPVZCapacity(ctx context.Context, pvzId uuid.UUID) (*domain.PVZ, error)
+	// DeleteLatProductFromReception удаляет последний принятый товар открытой приемки, записывает
+	// удаление от имени userId в журнал действий с товарами и возвращает товар.
+	DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID, userId uuid.UUID) (*domain.Product, error)
+	GetProductById(ctx context.Context, productId uuid.UUID) (*domain.Product, error)
+	// ChangeProductStatus переводит товар из статуса from в to и возвращает pgx.ErrNoRows,
+	// если товар уже в другом статусе. Нулевой userId сохраняется как NULL.
+	ChangeProductStatus(ctx context.Context, productId uuid.UUID, from, to string, userId uuid.UUID) error
+	GetInventory(ctx context.Context, pvzId uuid.UUID) ([]*domain.Product, error)
+	GetPickupInfo(ctx context.Context, productId uuid.UUID) (*domain.PickupInfo, error)
+	// FlagOverdueProducts отмечает товары на хранении, срок хранения которых по policy истек,
+	// и возвращает число отмеченных. Срок отсчитывается от перехода товара в статус stored.
+	FlagOverdueProducts(ctx context.Context, policy domain.StoragePolicy) (int64, error)
+	GetOverdueProducts(ctx context.Context, pvzId uuid.UUID) ([]*domain.OverdueProduct, error)
+	// CountOverdueProducts возвращает число просроченных товаров по всем ПВЗ, включая ПВЗ без них.
+	CountOverdueProducts(ctx context.Context) ([]*domain.OverdueCount, error)
+}
+
+// StorageCellRepository хранит ячейки хранения ПВЗ и раскладку товаров по ним. Занятость ячейки —
+// товары в ней в статусах received, stored и returning.
+type StorageCellRepository interface {
+	// CreateCells создает ячейки и заполняет их id; если адрес уже занят — ErrStorageCellTaken.
+	CreateCells(ctx context.Context, cells []*domain.StorageCell) error
+	// GetCells возвращает ячейки ПВЗ с их занятостью, упорядоченные по адресу.
+	GetCells(ctx context.Context, pvzId uuid.UUID) ([]*domain.StorageCell, error)
+	// DeleteCell удаляет пустую ячейку ПВЗ. Если ячейки нет — pgx.ErrNoRows, если в ней есть товары —
+	// ErrStorageCellNotEmpty.
+	DeleteCell(ctx context.Context, pvzId, cellId uuid.UUID) error
+	// GetCellByCode возвращает ячейку ПВЗ с занятостью по адресу или pgx.ErrNoRows.
+	GetCellByCode(ctx context.Context, pvzId uuid.UUID, code string) (*domain.StorageCell, error)
+	// SuggestCell возвращает незаполненную ячейку ПВЗ для товаров этого типа с наименьшим числом
+	// товаров или pgx.ErrNoRows, если такой нет.
+	SuggestCell(ctx context.Context, pvzId uuid.UUID, productType string) (*domain.StorageCell, error)
+	AssignProduct(ctx context.Context, productId, cellId uuid.UUID) error
+	// LocateProducts ищет товары, которые сейчас находятся в ПВЗ, по id или штрихкоду; пустые
+	// значения не участвуют в поиске.
+	LocateProducts(ctx context.Context, pvzId, productId uuid.UUID, barcode string) ([]*domain.ProductLocation, error)
 }
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type assignmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAssignmentRepository(db *pgxpool.Pool) repository.AssignmentRepository {
+	return &assignmentRepository{db: db}
+}
+
+func (r *assignmentRepository) AssignPVZ(ctx context.Context, userId, pvzId uuid.UUID) error {
+	// Повторное назначение идемпотентно; отсутствие ПВЗ возвращается как pgx.ErrNoRows.
+	query := `
+		INSERT INTO employee_pvz (user_id, pvz_id)
+		SELECT $1, id FROM pvz WHERE id = $2
+		ON CONFLICT (user_id, pvz_id) DO UPDATE SET assigned_at = employee_pvz.assigned_at
+		RETURNING pvz_id
+	`
+
+	var id uuid.UUID
+	err := r.db.QueryRow(ctx, query, userId, pvzId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("failed to assign pvz: %w", err)
+	}
+
+	return nil
+}
+
+func (r *assignmentRepository) UnassignPVZ(ctx context.Context, userId, pvzId uuid.UUID) error {
+	query := `DELETE FROM employee_pvz WHERE user_id = $1 AND pvz_id = $2`
+
+	cmdTag, err := r.db.Exec(ctx, query, userId, pvzId)
+	if err != nil {
+		return fmt.Errorf("failed to unassign pvz: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *assignmentRepository) GetUserPVZIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT pvz_id FROM employee_pvz WHERE user_id = $1 ORDER BY assigned_at`
+
+	rows, err := r.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user pvzs: %w", err)
+	}
+	defer rows.Close()
+
+	pvzIds := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("could not scan pvz id: %w", err)
+		}
+		pvzIds = append(pvzIds, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return pvzIds, nil
+}
+
+func (r *assignmentRepository) IsAssigned(ctx context.Context, userId, pvzId uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM employee_pvz WHERE user_id = $1 AND pvz_id = $2)`
+
+	var assigned bool
+	err := r.db.QueryRow(ctx, query, userId, pvzId).Scan(&assigned)
+	if err != nil {
+		return false, fmt.Errorf("failed to check pvz assignment: %w", err)
+	}
+
+	return assigned, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type exportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewExportRepository(db *pgxpool.Pool) repository.ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// StreamReceptionProducts читает результат по мере того, как строки приходят из соединения:
+// pgx не буферизует весь ответ, поэтому память не зависит от размера выгрузки.
+func (r *exportRepository) StreamReceptionProducts(ctx context.Context, filter domain.ReceptionExportFilter, fn func(*domain.ReceptionExportRow) error) error {
+	query := `
+		SELECT r.id, r.status, r.date_time, r.closed_at,
+		       pvz.id, pvz.city, COALESCE(pvz.address, ''),
+		       p.id, p.type, COALESCE(p.barcode, ''), p.status, COALESCE(p.date_time, r.date_time)
+		FROM receptions r
+		JOIN pvz ON pvz.id = r.pvz_id
+		JOIN products p ON p.reception_id = r.id
+		WHERE r.date_time >= $1 AND r.date_time < $2
+		  AND ($3 = '' OR pvz.city::text = $3)
+		ORDER BY r.date_time, r.id, p.date_time, p.id
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.From, filter.To, filter.City)
+	if err != nil {
+		return fmt.Errorf("failed to export reception products: %w", err)
+	}
+	defer rows.Close()
+
+	var row domain.ReceptionExportRow
+	for rows.Next() {
+		err := rows.Scan(
+			&row.ReceptionId, &row.ReceptionStatus, &row.ReceptionOpenedAt, &row.ReceptionClosedAt,
+			&row.PVZId, &row.City, &row.Address,
+			&row.ProductId, &row.ProductType, &row.Barcode, &row.ProductStatus, &row.ProductAddedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan export row: %w", err)
+		}
+
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export reception products: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type manifestRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewManifestRepository(db *pgxpool.Pool) repository.ManifestRepository {
+	return &manifestRepository{db: db}
+}
+
+func (r *manifestRepository) CreateManifest(ctx context.Context, manifest *domain.Manifest, createdBy uuid.UUID) error {
+	// Манифест и его позиции вставляются одним запросом; отсутствие ПВЗ возвращается как pgx.ErrNoRows.
+	query := `
+		WITH created AS (
+		    INSERT INTO manifests (pvz_id, external_id, created_by)
+		    SELECT id, NULLIF($2, ''), NULLIF($3::uuid, '00000000-0000-0000-0000-000000000000')
+		    FROM pvz WHERE id = $1
+		    RETURNING id, status, created_at
+		), items AS (
+		    INSERT INTO manifest_items (manifest_id, barcode, type)
+		    SELECT created.id, i.barcode, i.type::product_type
+		    FROM created, unnest($4::text[], $5::text[]) AS i (barcode, type)
+		)
+		SELECT id, status, created_at FROM created`
+
+	barcodes := make([]string, 0, len(manifest.Items))
+	types := make([]string, 0, len(manifest.Items))
+	for _, item := range manifest.Items {
+		barcodes = append(barcodes, item.Barcode)
+		types = append(types, item.Type)
+	}
+
+	err := r.db.QueryRow(ctx, query, manifest.PVZId, manifest.ExternalId, createdBy, barcodes, types).
+		Scan(&manifest.Id, &manifest.Status, &manifest.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("manifest could not be created: %w", err)
+	}
+
+	return nil
+}
+
+func (r *manifestRepository) GetManifestById(ctx context.Context, manifestId uuid.UUID) (*domain.Manifest, error) {
+	return r.getManifest(ctx, `m.id = $1`, manifestId)
+}
+
+func (r *manifestRepository) GetManifestByReceptionId(ctx context.Context, receptionId uuid.UUID) (*domain.Manifest, error) {
+	return r.getManifest(ctx, `m.reception_id = $1`, receptionId)
+}
+
+func (r *manifestRepository) getManifest(ctx context.Context, where string, id uuid.UUID) (*domain.Manifest, error) {
+	query := `
+		SELECT m.id, m.pvz_id, COALESCE(m.external_id, ''), m.reception_id, m.status, m.created_at
+		FROM manifests m
+		WHERE ` + where
+
+	var manifest domain.Manifest
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&manifest.Id, &manifest.PVZId, &manifest.ExternalId, &manifest.ReceptionId, &manifest.Status, &manifest.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error fetching manifest: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT barcode, type FROM manifest_items WHERE manifest_id = $1 ORDER BY barcode`, manifest.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching manifest items: %w", err)
+	}
+	defer rows.Close()
+
+	manifest.Items = []*domain.ManifestItem{}
+	for rows.Next() {
+		var item domain.ManifestItem
+		if err = rows.Scan(&item.Barcode, &item.Type); err != nil {
+			return nil, fmt.Errorf("manifest items could not be retrieved: %w", err)
+		}
+
+		manifest.Items = append(manifest.Items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+func (r *manifestRepository) SaveDiscrepancyReport(ctx context.Context, report *domain.DiscrepancyReport) error {
+	query := `
+		INSERT INTO discrepancy_reports
+		    (reception_id, manifest_id, expected, received, missing, unexpected, duplicates, without_barcode)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (reception_id) DO UPDATE
+		SET manifest_id = EXCLUDED.manifest_id,
+		    expected = EXCLUDED.expected,
+		    received = EXCLUDED.received,
+		    missing = EXCLUDED.missing,
+		    unexpected = EXCLUDED.unexpected,
+		    duplicates = EXCLUDED.duplicates,
+		    without_barcode = EXCLUDED.without_barcode
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		report.ReceptionId, report.ManifestId, report.Expected, report.Received,
+		report.Missing, report.Unexpected, report.Duplicates, report.WithoutBarcode,
+	).Scan(&report.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("discrepancy report could not be saved: %w", err)
+	}
+
+	return nil
+}
+
+func (r *manifestRepository) GetDiscrepancyReport(ctx context.Context, receptionId uuid.UUID) (*domain.DiscrepancyReport, error) {
+	query := `
+		SELECT reception_id, manifest_id, expected, received, missing, unexpected, duplicates, without_barcode, created_at
+		FROM discrepancy_reports
+		WHERE reception_id = $1
+	`
+
+	var report domain.DiscrepancyReport
+	err := r.db.QueryRow(ctx, query, receptionId).Scan(
+		&report.ReceptionId, &report.ManifestId, &report.Expected, &report.Received,
+		&report.Missing, &report.Unexpected, &report.Duplicates, &report.WithoutBarcode, &report.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error fetching discrepancy report: %w", err)
+	}
+
+	return &report, nil
+}
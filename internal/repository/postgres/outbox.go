@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type outboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *pgxpool.Pool) repository.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, event *domain.Event) error {
+	// Блокировка ПВЗ держится до конца транзакции: следующее событие этого ПВЗ получит id только
+	// после ее фиксации, поэтому порядок id совпадает с порядком фиксации. Advisory lock, а не
+	// блокировка строки pvz, чтобы не конфликтовать с внешними ключами товаров и приемок.
+	lock := `SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0))`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, lock, event.PVZId); err != nil {
+		return fmt.Errorf("failed to lock pvz events: %w", err)
+	}
+
+	query := `
+		INSERT INTO outbox (event_id, event_type, pvz_id, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		event.Id, event.Type, event.PVZId, event.AggregateId, event.Payload, event.OccurredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("event could not be saved: %w", err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) GetPending(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEvent, error) {
+	// Повтора ждет только первое неопубликованное событие ПВЗ: следующие за ним не отправлялись.
+	// ПВЗ, чье первое событие еще не пора повторять, в пачку не попадают и не занимают ее.
+	query := `
+		WITH heads AS (
+		    SELECT DISTINCT ON (pvz_id) pvz_id, next_attempt_at
+		    FROM outbox
+		    WHERE published_at IS NULL
+		    ORDER BY pvz_id, id
+		)
+		SELECT o.id, o.event_id, o.event_type, o.pvz_id, o.aggregate_id, o.payload, o.occurred_at,
+		       o.attempts, o.next_attempt_at
+		FROM outbox o
+		JOIN heads h ON h.pvz_id = o.pvz_id
+		WHERE o.published_at IS NULL AND h.next_attempt_at <= $1
+		ORDER BY o.id
+		LIMIT $2
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching pending events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.OutboxEvent{}
+	for rows.Next() {
+		var event domain.OutboxEvent
+		err = rows.Scan(
+			&event.Seq, &event.Id, &event.Type, &event.PVZId, &event.AggregateId, &event.Payload,
+			&event.OccurredAt, &event.Attempts, &event.NextAttemptAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("events could not be retrieved: %w", err)
+		}
+
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, seq int64) error {
+	query := `UPDATE outbox SET published_at = CURRENT_TIMESTAMP, last_error = NULL WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, seq); err != nil {
+		return fmt.Errorf("event could not be marked as published: %w", err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, seq int64, reason string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, seq, reason, nextAttemptAt); err != nil {
+		return fmt.Errorf("event failure could not be saved: %w", err)
+	}
+
+	return nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/aliskhannn/pvz-service/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,61 +21,326 @@ func NewProductRepository(db *pgxpool.Pool) repository.ProductRepository {
 	return &productRepository{db: db}
 }
 
-func (r *productRepository) AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType string) error {
+func (r *productRepository) AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType, barcode, pickupCode string, volume *float64, userId uuid.UUID) (*domain.Product, error) {
 	var receptionId uuid.UUID
 	query := `
 		SELECT id FROM receptions
-		WHERE pvz_id = $1 AND status = 'in_progress'
+		WHERE pvz_id = $1 AND status = 'in_progress' AND direction = 'inbound'
 		ORDER BY date_time DESC
 		LIMIT 1
     `
 
-	err := r.db.QueryRow(ctx, query, pvzId).Scan(&receptionId)
+	err := conn(ctx, r.db).QueryRow(ctx, query, pvzId).Scan(&receptionId)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("no active reception found for pvz %s", pvzId)
+			return nil, fmt.Errorf("no active reception found for pvz %s", pvzId)
 		}
-		return fmt.Errorf("error fetching reception: %w", err)
+		return nil, fmt.Errorf("error fetching reception: %w", err)
 	}
 
-	insert := `INSERT INTO products (type, reception_id) VALUES ($1, $2)`
+	// Запись в журнал действий идет тем же запросом, чтобы товар не мог появиться без нее.
+	insert := `
+		WITH inserted AS (
+			INSERT INTO products (type, reception_id, pvz_id, status, pickup_code, barcode, created_by, volume)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7::uuid, '00000000-0000-0000-0000-000000000000'), $8)
+			RETURNING id, date_time, pvz_id, created_by
+		), logged AS (
+			INSERT INTO product_activity (product_id, pvz_id, user_id, action, created_at)
+			SELECT id, pvz_id, created_by, 'added', date_time FROM inserted
+		)
+		SELECT id, date_time, created_by FROM inserted
+	`
+
+	product := &domain.Product{
+		Type:        productType,
+		PVZId:       pvzId,
+		ReceptionId: receptionId,
+		Barcode:     barcode,
+		Status:      constants.ProductStatusReceived,
+		PickupCode:  pickupCode,
+		Volume:      volume,
+	}
 
-	cmdTag, err := r.db.Exec(ctx, insert, productType, receptionId)
+	err = conn(ctx, r.db).QueryRow(ctx, insert, productType, receptionId, pvzId, product.Status, pickupCode, barcode, userId, volume).
+		Scan(&product.Id, &product.DateTime, &product.CreatedBy)
 	if err != nil {
-		return fmt.Errorf("error inserting product: %w", err)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "products_pvz_pickup_code_key" {
+			return nil, repository.ErrPickupCodeTaken
+		}
+		return nil, fmt.Errorf("error inserting product: %w", err)
 	}
 
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("no active reception found for pvz %s", pvzId)
+	return product, nil
+}
+
+func (r *productRepository) LockPVZCapacity(ctx context.Context, pvzId uuid.UUID) (*domain.PVZ, error) {
+	lock := `SELECT id, city, capacity_items, capacity_volume::float8 FROM pvz WHERE id = $1 FOR UPDATE`
+
+	pvz := domain.PVZ{Occupancy: &domain.PVZOccupancy{}}
+	err := conn(ctx, r.db).QueryRow(ctx, lock, pvzId).Scan(&pvz.Id, &pvz.City, &pvz.CapacityItems, &pvz.CapacityVolume)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error locking pvz: %w", err)
 	}
 
-	return nil
+	// Без вместимости заполненность для проверки не нужна.
+	if pvz.CapacityItems == nil && pvz.CapacityVolume == nil {
+		return &pvz, nil
+	}
+
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(volume), 0)::float8
+		FROM products
+		WHERE pvz_id = $1 AND status IN ($2, $3, $4)
+	`
+
+	err = conn(ctx, r.db).QueryRow(ctx, query, pvzId,
+		constants.ProductStatusReceived, constants.ProductStatusStored, constants.ProductStatusReturning,
+	).Scan(&pvz.Occupancy.Items, &pvz.Occupancy.Volume)
+	if err != nil {
+		return nil, fmt.Errorf("error counting pvz occupancy: %w", err)
+	}
+
+	return &pvz, nil
 }
 
-func (r *productRepository) DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID) error {
+func (r *productRepository) DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID, userId uuid.UUID) (*domain.Product, error) {
 	query := `
-		DELETE FROM products
-		WHERE id = (
-		      SELECT id FROM products
-		      WHERE reception_id = (
-		    	SELECT id FROM receptions
-				WHERE pvz_id = $1 AND status = 'in_progress'
-				ORDER BY date_time DESC
-			  	LIMIT 1
-		      )
-		      ORDER BY date_time DESC
-			  LIMIT 1
+		WITH deleted AS (
+			DELETE FROM products
+			WHERE id = (
+			      SELECT id FROM products
+			      WHERE status = 'received' AND reception_id = (
+			    	SELECT id FROM receptions
+					WHERE pvz_id = $1 AND status = 'in_progress' AND direction = 'inbound'
+					ORDER BY date_time DESC
+				  	LIMIT 1
+			      )
+			      -- Товары, поступившие перемещением, не сканировались в этой приемке и не удаляются.
+			      AND NOT EXISTS (SELECT 1 FROM transfer_items ti WHERE ti.product_id = products.id)
+			      ORDER BY date_time DESC
+				  LIMIT 1
+			)
+			RETURNING id, date_time, type, reception_id, COALESCE(barcode, '') AS barcode, status, created_by
+		), logged AS (
+			INSERT INTO product_activity (product_id, pvz_id, user_id, action)
+			SELECT id, $1, NULLIF($2::uuid, '00000000-0000-0000-0000-000000000000'), 'deleted' FROM deleted
 		)
+		SELECT id, date_time, type, reception_id, barcode, status, created_by FROM deleted
 	`
 
-	cmdTag, err := r.db.Exec(ctx, query, pvzId)
+	product := domain.Product{PVZId: pvzId}
+	err := conn(ctx, r.db).QueryRow(ctx, query, pvzId, userId).Scan(
+		&product.Id, &product.DateTime, &product.Type, &product.ReceptionId, &product.Barcode, &product.Status,
+		&product.CreatedBy,
+	)
 	if err != nil {
-		return fmt.Errorf("error deleting reception: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("no active reception found for pvz %s", pvzId)
+		}
+		return nil, fmt.Errorf("error deleting reception: %w", err)
+	}
+
+	return &product, nil
+}
+
+func (r *productRepository) GetProductById(ctx context.Context, productId uuid.UUID) (*domain.Product, error) {
+	query := `
+		SELECT p.id, p.date_time, p.type, r.pvz_id, p.reception_id, COALESCE(p.barcode, ''), p.status,
+		       COALESCE(p.pickup_code, ''), p.status_changed_at
+		FROM products p
+		JOIN receptions r ON r.id = p.reception_id
+		WHERE p.id = $1
+	`
+
+	var product domain.Product
+	err := conn(ctx, r.db).QueryRow(ctx, query, productId).Scan(
+		&product.Id, &product.DateTime, &product.Type, &product.PVZId, &product.ReceptionId,
+		&product.Barcode, &product.Status, &product.PickupCode, &product.StatusChangedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error fetching product: %w", err)
+	}
+
+	return &product, nil
+}
+
+func (r *productRepository) ChangeProductStatus(ctx context.Context, productId uuid.UUID, from, to string, userId uuid.UUID) error {
+	query := `
+		UPDATE products
+		SET status = $3, status_changed_at = CURRENT_TIMESTAMP, status_changed_by = NULLIF($4::uuid, '00000000-0000-0000-0000-000000000000')
+		WHERE id = $1 AND status = $2
+	`
+
+	cmdTag, err := conn(ctx, r.db).Exec(ctx, query, productId, from, to, userId)
+	if err != nil {
+		return fmt.Errorf("error changing product status: %w", err)
 	}
 
 	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("no active reception found for pvz %s", pvzId)
+		return pgx.ErrNoRows
 	}
 
 	return nil
 }
+
+func (r *productRepository) GetInventory(ctx context.Context, pvzId uuid.UUID) ([]*domain.Product, error) {
+	query := `
+		SELECT p.id, p.date_time, p.type, r.pvz_id, p.reception_id, COALESCE(p.barcode, ''), p.status, p.status_changed_at,
+		       c.id, COALESCE(c.code, '')
+		FROM products p
+		JOIN receptions r ON r.id = p.reception_id
+		LEFT JOIN storage_cells c ON c.id = p.cell_id
+		WHERE r.pvz_id = $1 AND p.status IN ($2, $3, $4)
+		ORDER BY p.date_time
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, pvzId,
+		constants.ProductStatusReceived, constants.ProductStatusStored, constants.ProductStatusReturning,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching inventory: %w", err)
+	}
+	defer rows.Close()
+
+	products := []*domain.Product{}
+	for rows.Next() {
+		var product domain.Product
+		err = rows.Scan(
+			&product.Id, &product.DateTime, &product.Type, &product.PVZId, &product.ReceptionId,
+			&product.Barcode, &product.Status, &product.StatusChangedAt, &product.CellId, &product.CellCode,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("products could not be retrieved: %w", err)
+		}
+
+		products = append(products, &product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return products, nil
+}
+
+func (r *productRepository) GetPickupInfo(ctx context.Context, productId uuid.UUID) (*domain.PickupInfo, error) {
+	query := `
+		SELECT p.id, p.type, p.status, p.status_changed_at, COALESCE(p.pickup_code, ''),
+		       v.id, v.city, COALESCE(v.address, ''), COALESCE(v.opening_hours, '')
+		FROM products p
+		JOIN pvz v ON v.id = p.pvz_id
+		WHERE p.id = $1
+	`
+
+	var info domain.PickupInfo
+	err := conn(ctx, r.db).QueryRow(ctx, query, productId).Scan(
+		&info.ProductId, &info.Type, &info.Status, &info.StatusChangedAt, &info.PickupCode,
+		&info.PVZId, &info.City, &info.Address, &info.OpeningHours,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error fetching pickup info: %w", err)
+	}
+
+	return &info, nil
+}
+
+func (r *productRepository) FlagOverdueProducts(ctx context.Context, policy domain.StoragePolicy) (int64, error) {
+	types := make([]string, 0, len(policy.Days))
+	days := make([]int32, 0, len(policy.Days))
+	for productType, d := range policy.Days {
+		types = append(types, productType)
+		days = append(days, int32(d))
+	}
+
+	query := `
+		UPDATE products p
+		SET overdue_at = CURRENT_TIMESTAMP
+		WHERE p.status = $1 AND p.overdue_at IS NULL
+		  AND COALESCE(p.status_changed_at, p.date_time) + make_interval(days => COALESCE(
+		      (SELECT s.days FROM unnest($2::text[], $3::int[]) AS s(type, days) WHERE s.type = p.type::text),
+		      $4::int
+		  )) <= CURRENT_TIMESTAMP
+	`
+
+	cmdTag, err := conn(ctx, r.db).Exec(ctx, query, constants.ProductStatusStored, types, days, policy.DefaultDays)
+	if err != nil {
+		return 0, fmt.Errorf("error flagging overdue products: %w", err)
+	}
+
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *productRepository) GetOverdueProducts(ctx context.Context, pvzId uuid.UUID) ([]*domain.OverdueProduct, error) {
+	query := `
+		SELECT id, type, reception_id, COALESCE(barcode, ''), COALESCE(status_changed_at, date_time), overdue_at
+		FROM products
+		WHERE pvz_id = $1 AND status = $2 AND overdue_at IS NOT NULL
+		ORDER BY overdue_at, date_time
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, pvzId, constants.ProductStatusStored)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching overdue products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []*domain.OverdueProduct{}
+	for rows.Next() {
+		var product domain.OverdueProduct
+		err = rows.Scan(
+			&product.Id, &product.Type, &product.ReceptionId, &product.Barcode, &product.StoredSince, &product.OverdueAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("overdue products could not be retrieved: %w", err)
+		}
+
+		products = append(products, &product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return products, nil
+}
+
+func (r *productRepository) CountOverdueProducts(ctx context.Context) ([]*domain.OverdueCount, error) {
+	query := `
+		SELECT v.id, v.city, COUNT(p.id)
+		FROM pvz v
+		LEFT JOIN products p ON p.pvz_id = v.id AND p.status = $1 AND p.overdue_at IS NOT NULL
+		GROUP BY v.id, v.city
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, constants.ProductStatusStored)
+	if err != nil {
+		return nil, fmt.Errorf("error counting overdue products: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*domain.OverdueCount{}
+	for rows.Next() {
+		var count domain.OverdueCount
+		if err = rows.Scan(&count.PVZId, &count.City, &count.Count); err != nil {
+			return nil, fmt.Errorf("overdue counts could not be retrieved: %w", err)
+		}
+
+		counts = append(counts, &count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return counts, nil
+}
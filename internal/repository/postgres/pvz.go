@@ -2,10 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/aliskhannn/pvz-service/internal/repository"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"time"
 )
@@ -19,8 +22,14 @@ func NewPVZRepository(db *pgxpool.Pool) repository.PVZRepository {
 }
 
 func (r *pvzRepository) CreatePVZ(ctx context.Context, pvz *domain.PVZ) error {
-	query := `INSERT INTO pvz (city) VALUES ($1)`
-	_, err := r.db.Exec(ctx, query, pvz.City)
+	query := `
+		INSERT INTO pvz (city, address, opening_hours, capacity_items, capacity_volume)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5)
+		RETURNING id, registration_date
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, pvz.City, pvz.Address, pvz.OpeningHours, pvz.CapacityItems, pvz.CapacityVolume).
+		Scan(&pvz.Id, &pvz.RegistrationDate)
 	if err != nil {
 		return fmt.Errorf("pvz could not be created: %w", err)
 	}
@@ -28,15 +37,61 @@ func (r *pvzRepository) CreatePVZ(ctx context.Context, pvz *domain.PVZ) error {
 	return nil
 }
 
+func (r *pvzRepository) FindPVZsByAddress(ctx context.Context, pvzs []*domain.PVZ) ([]*domain.PVZ, error) {
+	cities := make([]string, 0, len(pvzs))
+	addresses := make([]string, 0, len(pvzs))
+	for _, pvz := range pvzs {
+		cities = append(cities, pvz.City)
+		addresses = append(addresses, pvz.Address)
+	}
+
+	query := `
+		SELECT DISTINCT p.id, p.registration_date, p.city, p.address, COALESCE(p.opening_hours, '')
+		FROM pvz p
+		JOIN unnest($1::text[], $2::text[]) AS a (city, address)
+		  ON p.city::text = a.city AND lower(p.address) = lower(a.address)
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, cities, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("pvz could not be retrieved: %w", err)
+	}
+	defer rows.Close()
+
+	var existing []*domain.PVZ
+	for rows.Next() {
+		var pvz domain.PVZ
+		if err := rows.Scan(&pvz.Id, &pvz.RegistrationDate, &pvz.City, &pvz.Address, &pvz.OpeningHours); err != nil {
+			return nil, fmt.Errorf("failed to scan pvz: %w", err)
+		}
+
+		existing = append(existing, &pvz)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("pvz could not be retrieved: %w", err)
+	}
+
+	return existing, nil
+}
+
 func (r *pvzRepository) GetAllPVZs(ctx context.Context, offset, limit int) ([]*domain.PVZ, error) {
 	query := `
-		SELECT id, registration_date, city 
-		FROM pvz
-		ORDER BY registration_date DESC
+		SELECT p.id, p.registration_date, p.city, COALESCE(p.address, ''), COALESCE(p.opening_hours, ''),
+		       p.capacity_items, p.capacity_volume::float8, o.items, o.volume
+		FROM pvz p
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS items, COALESCE(SUM(volume), 0)::float8 AS volume
+			FROM products
+			WHERE pvz_id = p.id AND status IN ($3, $4, $5)
+		) o ON true
+		ORDER BY p.registration_date DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
+	rows, err := r.db.Query(ctx, query, limit, offset,
+		constants.ProductStatusReceived, constants.ProductStatusStored, constants.ProductStatusReturning,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("pvz could not be retrieved: %w", err)
 	}
@@ -44,8 +99,10 @@ func (r *pvzRepository) GetAllPVZs(ctx context.Context, offset, limit int) ([]*d
 
 	var pvzs []*domain.PVZ
 	for rows.Next() {
-		var pvz domain.PVZ
-		err = rows.Scan(&pvz.Id, &pvz.RegistrationDate, &pvz.City)
+		pvz := domain.PVZ{Occupancy: &domain.PVZOccupancy{}}
+		err = rows.Scan(&pvz.Id, &pvz.RegistrationDate, &pvz.City, &pvz.Address, &pvz.OpeningHours,
+			&pvz.CapacityItems, &pvz.CapacityVolume, &pvz.Occupancy.Items, &pvz.Occupancy.Volume,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("pvz could not be retrieved: %w", err)
 		}
@@ -60,9 +117,57 @@ func (r *pvzRepository) GetAllPVZs(ctx context.Context, offset, limit int) ([]*d
 	return pvzs, nil
 }
 
+func (r *pvzRepository) UpdatePVZCapacity(ctx context.Context, pvzId uuid.UUID, items *int, volume *float64) (*domain.PVZ, error) {
+	query := `
+		UPDATE pvz
+		SET capacity_items = $2, capacity_volume = $3
+		WHERE id = $1
+		RETURNING id, registration_date, city, COALESCE(address, ''), COALESCE(opening_hours, ''),
+		          capacity_items, capacity_volume::float8
+	`
+
+	var pvz domain.PVZ
+	err := conn(ctx, r.db).QueryRow(ctx, query, pvzId, items, volume).Scan(
+		&pvz.Id, &pvz.RegistrationDate, &pvz.City, &pvz.Address, &pvz.OpeningHours,
+		&pvz.CapacityItems, &pvz.CapacityVolume,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("pvz capacity could not be updated: %w", err)
+	}
+
+	return &pvz, nil
+}
+
+func (r *pvzRepository) GetPVZIdsByCity(ctx context.Context, city string) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `SELECT id FROM pvz WHERE city = $1`, city)
+	if err != nil {
+		return nil, fmt.Errorf("pvz could not be retrieved: %w", err)
+	}
+	defer rows.Close()
+
+	pvzIds := []uuid.UUID{}
+	for rows.Next() {
+		var pvzId uuid.UUID
+		if err = rows.Scan(&pvzId); err != nil {
+			return nil, fmt.Errorf("pvz could not be retrieved: %w", err)
+		}
+
+		pvzIds = append(pvzIds, pvzId)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return pvzIds, nil
+}
+
 func (r *pvzRepository) GetReceptionsByPVZId(ctx context.Context, pvzId uuid.UUID, startDate, endDate time.Time) ([]*domain.Reception, error) {
 	query := `
-		SELECT id, pvz_id, date_time, status
+		SELECT id, pvz_id, date_time, status, direction, closed_at, auto_closed, COALESCE(close_reason, '')
 		FROM receptions
 		WHERE pvz_id = $1 AND date_time BETWEEN $2 AND $3
 		ORDER BY date_time DESC
@@ -77,7 +182,9 @@ func (r *pvzRepository) GetReceptionsByPVZId(ctx context.Context, pvzId uuid.UUI
 	var receptions []*domain.Reception
 	for rows.Next() {
 		var reception domain.Reception
-		if err = rows.Scan(&reception.Id, &reception.PVZId, &reception.DateTime, &reception.Status); err != nil {
+		if err = rows.Scan(&reception.Id, &reception.PVZId, &reception.DateTime, &reception.Status, &reception.Direction,
+			&reception.ClosedAt, &reception.AutoClosed, &reception.CloseReason,
+		); err != nil {
 			return nil, fmt.Errorf("could not scan reception: %w", err)
 		}
 
@@ -93,13 +200,15 @@ func (r *pvzRepository) GetReceptionsByPVZId(ctx context.Context, pvzId uuid.UUI
 
 func (r *pvzRepository) GetAllProductsFromReception(ctx context.Context, receptionId uuid.UUID) ([]*domain.Product, error) {
 	query := `
-		SELECT id, type, reception_id, date_time
-		FROM products 
-		WHERE reception_id = $1
+		SELECT id, type, reception_id, date_time, COALESCE(barcode, ''), status, status_changed_at
+		FROM products
+		WHERE (reception_id = $1 OR id IN (SELECT product_id FROM return_items WHERE reception_id = $1))
+		  AND status <> $2
 		ORDER BY date_time DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, receptionId)
+	// Удаленные исправлением товары видны в истории приемки, а не в ее составе.
+	rows, err := r.db.Query(ctx, query, receptionId, constants.ProductStatusRemovedByCorrection)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching products: %w", err)
 	}
@@ -108,7 +217,7 @@ func (r *pvzRepository) GetAllProductsFromReception(ctx context.Context, recepti
 	var products []*domain.Product
 	for rows.Next() {
 		var product domain.Product
-		if err = rows.Scan(&product.Id, &product.Type, &product.ReceptionId, &product.DateTime); err != nil {
+		if err = rows.Scan(&product.Id, &product.Type, &product.ReceptionId, &product.DateTime, &product.Barcode, &product.Status, &product.StatusChangedAt); err != nil {
 			return nil, fmt.Errorf("products could not be retrieved: %w", err)
 		}
 
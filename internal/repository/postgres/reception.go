@@ -2,12 +2,15 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/aliskhannn/pvz-service/internal/repository"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
 )
 
 type receptionRepository struct {
@@ -19,9 +22,37 @@ func NewReceptionRepository(db *pgxpool.Pool) repository.ReceptionRepository {
 }
 
 func (r *receptionRepository) CreateReception(ctx context.Context, reception *domain.Reception) error {
-	query := `INSERT INTO receptions (date_time, pvz_id, status) VALUES ($1, $2, $3)`
+	if reception.Direction == "" {
+		reception.Direction = constants.ReceptionDirectionInbound
+	}
+
+	// Приемка и привязка манифеста создаются одним запросом. Возвратная партия манифест не получает.
+	query := `
+		WITH created AS (
+		    INSERT INTO receptions (date_time, pvz_id, status, direction)
+		    VALUES ($1, $2, $3, $6)
+		    RETURNING id
+		), linked AS (
+		    UPDATE manifests
+		    SET reception_id = (SELECT id FROM created), status = $5
+		    WHERE id = (
+		        SELECT id FROM manifests
+		        WHERE pvz_id = $2 AND status = $4 AND $6 = $7
+		        ORDER BY created_at
+		        LIMIT 1
+		        FOR UPDATE SKIP LOCKED
+		    )
+		    RETURNING id
+		)
+		SELECT created.id, linked.id
+		FROM created
+		LEFT JOIN linked ON true`
 
-	_, err := r.db.Exec(ctx, query, reception.DateTime, reception.PVZId, reception.Status)
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		reception.DateTime, reception.PVZId, reception.Status,
+		constants.ManifestStatusPending, constants.ManifestStatusLinked,
+		reception.Direction, constants.ReceptionDirectionInbound,
+	).Scan(&reception.Id, &reception.ManifestId)
 	if err != nil {
 		return fmt.Errorf("reception could not be created: %w", err)
 	}
@@ -29,30 +60,170 @@ func (r *receptionRepository) CreateReception(ctx context.Context, reception *do
 	return nil
 }
 
-func (r *receptionRepository) CloseLastReception(ctx context.Context, pvzId uuid.UUID) error {
+func (r *receptionRepository) CloseLastReception(ctx context.Context, pvzId uuid.UUID) (uuid.UUID, error) {
+	// Вместе с приемкой принятые товары переходят на хранение.
 	query := `
-		UPDATE receptions
-		SET status = $1 
-		WHERE id = (
-		    SELECT id FROM receptions
-		    WHERE pvz_id = $3 AND status = $2
-		    ORDER BY date_time DESC
-		    LIMIT 1
-		)`
-
-	cmdTag, err := r.db.Exec(ctx, query, constants.ReceptionStatusClose, constants.ReceptionStatusInProgress, pvzId)
+		WITH closed AS (
+		    UPDATE receptions
+		    SET status = $1, closed_at = CURRENT_TIMESTAMP
+		    WHERE id = (
+		        SELECT id FROM receptions
+		        WHERE pvz_id = $3 AND status = $2 AND direction = $6
+		        ORDER BY date_time DESC
+		        LIMIT 1
+		    )
+		    RETURNING id
+		), stored AS (
+		    UPDATE products
+		    SET status = $5, status_changed_at = CURRENT_TIMESTAMP
+		    WHERE reception_id IN (SELECT id FROM closed) AND status = $4
+		)
+		SELECT id FROM closed`
+
+	var receptionId uuid.UUID
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		constants.ReceptionStatusClose, constants.ReceptionStatusInProgress, pvzId,
+		constants.ProductStatusReceived, constants.ProductStatusStored, constants.ReceptionDirectionInbound,
+	).Scan(&receptionId)
 	if err != nil {
-		return fmt.Errorf("reception could not be closed: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, fmt.Errorf("no active reception found for pvz %s", pvzId)
+		}
+		return uuid.Nil, fmt.Errorf("reception could not be closed: %w", err)
 	}
 
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("no active reception found for pvz %s", pvzId)
+	return receptionId, nil
+}
+
+func (r *receptionRepository) CloseStaleReceptions(ctx context.Context, idleFor time.Duration, reason string) ([]*domain.Reception, error) {
+	// Простой считается от последнего поступления товара в приемку, а для пустой приемки — от ее открытия
+	// или переоткрытия модератором.
+	query := `
+		WITH closed AS (
+		    UPDATE receptions r
+		    SET status = $1, closed_at = CURRENT_TIMESTAMP, auto_closed = true, close_reason = $5
+		    WHERE r.status = $2 AND r.direction = $3
+		      AND GREATEST(r.date_time, r.reopened_at, (
+		          SELECT MAX(COALESCE(p.status_changed_at, p.date_time)) FROM products p WHERE p.reception_id = r.id
+		      )) < CURRENT_TIMESTAMP - make_interval(secs => $4)
+		    RETURNING r.id, r.pvz_id
+		), stored AS (
+		    UPDATE products
+		    SET status = $7, status_changed_at = CURRENT_TIMESTAMP
+		    WHERE reception_id IN (SELECT id FROM closed) AND status = $6
+		)
+		SELECT id, pvz_id FROM closed`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query,
+		constants.ReceptionStatusClose, constants.ReceptionStatusInProgress, constants.ReceptionDirectionInbound,
+		idleFor.Seconds(), reason, constants.ProductStatusReceived, constants.ProductStatusStored,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("stale receptions could not be closed: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	receptions := []*domain.Reception{}
+	for rows.Next() {
+		var reception domain.Reception
+		if err = rows.Scan(&reception.Id, &reception.PVZId); err != nil {
+			return nil, fmt.Errorf("closed receptions could not be retrieved: %w", err)
+		}
+
+		receptions = append(receptions, &reception)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return receptions, nil
 }
 
-func (r *receptionRepository) HasOpenReception(ctx context.Context, pvzId uuid.UUID) (bool, error) {
+func (r *receptionRepository) AddProductToReturn(ctx context.Context, pvzId, productId, userId uuid.UUID) error {
+	return pgx.BeginFunc(ctx, conn(ctx, r.db), func(tx pgx.Tx) error {
+		var receptionId uuid.UUID
+		reception := `
+			SELECT id FROM receptions
+			WHERE pvz_id = $1 AND status = $2 AND direction = $3
+			ORDER BY date_time DESC
+			LIMIT 1
+		`
+
+		err := tx.QueryRow(ctx, reception, pvzId, constants.ReceptionStatusInProgress, constants.ReceptionDirectionReturn).
+			Scan(&receptionId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrNoOpenReception
+			}
+			return fmt.Errorf("error fetching return reception: %w", err)
+		}
+
+		// Исходная приемка товара не меняется, партия хранится в return_items.
+		add := `
+			WITH returning_product AS (
+			    UPDATE products
+			    SET status = $4, status_changed_at = CURRENT_TIMESTAMP,
+			        status_changed_by = NULLIF($5::uuid, '00000000-0000-0000-0000-000000000000')
+			    WHERE id = $2 AND pvz_id = $3 AND status = $6
+			    RETURNING id
+			)
+			INSERT INTO return_items (reception_id, product_id)
+			SELECT $1, id FROM returning_product
+		`
+
+		tag, err := tx.Exec(ctx, add,
+			receptionId, productId, pvzId, constants.ProductStatusReturning, userId, constants.ProductStatusStored,
+		)
+		if err != nil {
+			return fmt.Errorf("product could not be added to return: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return repository.ErrProductsUnavailable
+		}
+
+		return nil
+	})
+}
+
+func (r *receptionRepository) CloseLastReturn(ctx context.Context, pvzId uuid.UUID) (uuid.UUID, error) {
+	// Вместе с партией ее товары считаются возвращенными отправителю.
+	query := `
+		WITH closed AS (
+		    UPDATE receptions
+		    SET status = $1, closed_at = CURRENT_TIMESTAMP
+		    WHERE id = (
+		        SELECT id FROM receptions
+		        WHERE pvz_id = $3 AND status = $2 AND direction = $6
+		        ORDER BY date_time DESC
+		        LIMIT 1
+		    )
+		    RETURNING id
+		), returned AS (
+		    UPDATE products
+		    SET status = $5, status_changed_at = CURRENT_TIMESTAMP
+		    WHERE status = $4 AND id IN (
+		        SELECT product_id FROM return_items WHERE reception_id IN (SELECT id FROM closed)
+		    )
+		)
+		SELECT id FROM closed`
+
+	var receptionId uuid.UUID
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		constants.ReceptionStatusClose, constants.ReceptionStatusInProgress, pvzId,
+		constants.ProductStatusReturning, constants.ProductStatusReturnedToSender, constants.ReceptionDirectionReturn,
+	).Scan(&receptionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, fmt.Errorf("no active return found for pvz %s", pvzId)
+		}
+		return uuid.Nil, fmt.Errorf("return could not be closed: %w", err)
+	}
+
+	return receptionId, nil
+}
+
+func (r *receptionRepository) HasOpenReception(ctx context.Context, pvzId uuid.UUID, direction string) (bool, error) {
 	if pvzId == uuid.Nil {
 		return false, fmt.Errorf("pvz id is required")
 	}
@@ -62,14 +233,105 @@ func (r *receptionRepository) HasOpenReception(ctx context.Context, pvzId uuid.U
 	query := `
 		SELECT EXISTS (
 			SELECT 1 FROM receptions
-         	WHERE pvz_id = $1 AND status = $2
+         	WHERE pvz_id = $1 AND status = $2 AND direction = $3
          )
 	`
 
-	err := r.db.QueryRow(ctx, query, pvzId, constants.ReceptionStatusInProgress).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, pvzId, constants.ReceptionStatusInProgress, direction).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check open reception: %w", err)
 	}
 
 	return exists, nil
 }
+
+func (r *receptionRepository) GetReceptionById(ctx context.Context, receptionId uuid.UUID) (*domain.Reception, error) {
+	query := `
+		SELECT r.id, r.date_time, r.pvz_id, r.status, r.direction, m.id,
+		       r.closed_at, r.auto_closed, COALESCE(r.close_reason, '')
+		FROM receptions r
+		LEFT JOIN manifests m ON m.reception_id = r.id
+		WHERE r.id = $1
+	`
+
+	var reception domain.Reception
+	err := conn(ctx, r.db).QueryRow(ctx, query, receptionId).Scan(
+		&reception.Id, &reception.DateTime, &reception.PVZId, &reception.Status, &reception.Direction, &reception.ManifestId,
+		&reception.ClosedAt, &reception.AutoClosed, &reception.CloseReason,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error fetching reception: %w", err)
+	}
+
+	return &reception, nil
+}
+
+func (r *receptionRepository) GetReceptionBarcodes(ctx context.Context, receptionId uuid.UUID) ([]string, error) {
+	query := `SELECT COALESCE(barcode, '') FROM products WHERE reception_id = $1 AND status <> $2`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, receptionId, constants.ProductStatusRemovedByCorrection)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching barcodes: %w", err)
+	}
+	defer rows.Close()
+
+	barcodes := []string{}
+	for rows.Next() {
+		var barcode string
+		if err = rows.Scan(&barcode); err != nil {
+			return nil, fmt.Errorf("barcodes could not be retrieved: %w", err)
+		}
+
+		barcodes = append(barcodes, barcode)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return barcodes, nil
+}
+
+func (r *receptionRepository) GetOpenReceptions(ctx context.Context) ([]*domain.OpenReception, error) {
+	query := `
+		SELECT r.id, r.pvz_id, pvz.city, r.date_time, COUNT(p.id),
+		       EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - r.date_time)::bigint
+		FROM receptions r
+		JOIN pvz ON pvz.id = r.pvz_id
+		LEFT JOIN products p ON p.reception_id = r.id AND p.status <> $3
+		WHERE r.status = $1 AND r.direction = $2
+		GROUP BY r.id, pvz.city
+		ORDER BY r.date_time
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query,
+		constants.ReceptionStatusInProgress, constants.ReceptionDirectionInbound, constants.ProductStatusRemovedByCorrection,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("open receptions could not be retrieved: %w", err)
+	}
+	defer rows.Close()
+
+	receptions := []*domain.OpenReception{}
+	for rows.Next() {
+		var reception domain.OpenReception
+		err := rows.Scan(
+			&reception.ReceptionId, &reception.PVZId, &reception.City, &reception.OpenedAt,
+			&reception.Products, &reception.OpenSeconds,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan open reception: %w", err)
+		}
+
+		receptions = append(receptions, &reception)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("open receptions could not be retrieved: %w", err)
+	}
+
+	return receptions, nil
+}
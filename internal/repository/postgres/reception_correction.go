@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type receptionCorrectionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReceptionCorrectionRepository(db *pgxpool.Pool) repository.ReceptionCorrectionRepository {
+	return &receptionCorrectionRepository{db: db}
+}
+
+func (r *receptionCorrectionRepository) ReopenReception(ctx context.Context, receptionId, userId uuid.UUID, reason string) error {
+	return pgx.BeginFunc(ctx, conn(ctx, r.db), func(tx pgx.Tx) error {
+		pvzId, dateTime, err := lockClosedReception(ctx, tx, receptionId)
+		if err != nil {
+			return err
+		}
+
+		var hasNewer bool
+		newer := `
+			SELECT EXISTS (
+			    SELECT 1 FROM receptions
+			    WHERE pvz_id = $1 AND direction = $2 AND id <> $3 AND (date_time > $4 OR status = $5)
+			)
+		`
+
+		err = tx.QueryRow(ctx, newer,
+			pvzId, constants.ReceptionDirectionInbound, receptionId, dateTime, constants.ReceptionStatusInProgress,
+		).Scan(&hasNewer)
+		if err != nil {
+			return fmt.Errorf("failed to check newer receptions: %w", err)
+		}
+		if hasNewer {
+			return repository.ErrNewerReceptionExists
+		}
+
+		// Отчет о расхождениях будет построен заново при повторном закрытии.
+		reopen := `
+			WITH reopened AS (
+			    UPDATE receptions
+			    SET status = $2, closed_at = NULL, auto_closed = false, close_reason = NULL, reopened_at = CURRENT_TIMESTAMP
+			    WHERE id = $1
+			)
+			DELETE FROM discrepancy_reports WHERE reception_id = $1
+		`
+
+		if _, err = tx.Exec(ctx, reopen, receptionId, constants.ReceptionStatusInProgress); err != nil {
+			return fmt.Errorf("reception could not be reopened: %w", err)
+		}
+
+		return insertReceptionAudit(ctx, tx, &domain.ReceptionAuditEntry{
+			ReceptionId: receptionId,
+			Action:      constants.ReceptionAuditReopen,
+			Reason:      reason,
+		}, userId)
+	})
+}
+
+func (r *receptionCorrectionRepository) AddProduct(
+	ctx context.Context,
+	receptionId uuid.UUID,
+	productType, barcode, pickupCode string,
+	userId uuid.UUID,
+	reason string,
+) (*domain.Product, error) {
+	var product *domain.Product
+
+	err := pgx.BeginFunc(ctx, conn(ctx, r.db), func(tx pgx.Tx) error {
+		pvzId, _, err := lockClosedReception(ctx, tx, receptionId)
+		if err != nil {
+			return err
+		}
+
+		insert := `
+			WITH inserted AS (
+			    INSERT INTO products (type, reception_id, pvz_id, status, pickup_code, barcode, status_changed_at, status_changed_by)
+			    VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), CURRENT_TIMESTAMP, NULLIF($7::uuid, '00000000-0000-0000-0000-000000000000'))
+			    RETURNING id, date_time, pvz_id, status_changed_at, status_changed_by
+			), logged AS (
+			    INSERT INTO product_activity (product_id, pvz_id, user_id, action)
+			    SELECT id, pvz_id, status_changed_by, 'correction_added' FROM inserted
+			)
+			SELECT id, date_time, status_changed_at FROM inserted
+		`
+
+		product = &domain.Product{
+			Type:        productType,
+			PVZId:       pvzId,
+			ReceptionId: receptionId,
+			Barcode:     barcode,
+			Status:      constants.ProductStatusStored,
+			PickupCode:  pickupCode,
+		}
+
+		err = tx.QueryRow(ctx, insert, productType, receptionId, pvzId, product.Status, pickupCode, barcode, userId).
+			Scan(&product.Id, &product.DateTime, &product.StatusChangedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.ConstraintName == "products_pvz_pickup_code_key" {
+				return repository.ErrPickupCodeTaken
+			}
+			return fmt.Errorf("error inserting product: %w", err)
+		}
+
+		return insertReceptionAudit(ctx, tx, &domain.ReceptionAuditEntry{
+			ReceptionId: receptionId,
+			Action:      constants.ReceptionAuditAddProduct,
+			ProductId:   &product.Id,
+			ProductType: productType,
+			Barcode:     barcode,
+			Reason:      reason,
+		}, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (r *receptionCorrectionRepository) RemoveProduct(ctx context.Context, receptionId, productId, userId uuid.UUID, reason string) (*domain.Product, error) {
+	product := &domain.Product{Id: productId, ReceptionId: receptionId}
+
+	err := pgx.BeginFunc(ctx, conn(ctx, r.db), func(tx pgx.Tx) error {
+		if _, _, err := lockClosedReception(ctx, tx, receptionId); err != nil {
+			return err
+		}
+
+		// Товар не удаляется физически: на него ссылаются перемещения, возвраты и выгрузки.
+		remove := `
+			WITH removed AS (
+			    UPDATE products
+			    SET status = $4, removal_reason = $5,
+			        status_changed_at = CURRENT_TIMESTAMP,
+			        status_changed_by = NULLIF($6::uuid, '00000000-0000-0000-0000-000000000000')
+			    WHERE id = $1 AND reception_id = $2 AND status = $3
+			    RETURNING id, date_time, type, COALESCE(barcode, '') AS barcode, pvz_id, status, status_changed_at, status_changed_by
+			), logged AS (
+			    INSERT INTO product_activity (product_id, pvz_id, user_id, action)
+			    SELECT id, pvz_id, status_changed_by, 'correction_removed' FROM removed
+			)
+			SELECT date_time, type, barcode, pvz_id, status, status_changed_at FROM removed
+		`
+
+		err := tx.QueryRow(ctx, remove,
+			productId, receptionId, constants.ProductStatusStored, constants.ProductStatusRemovedByCorrection, reason, userId,
+		).Scan(&product.DateTime, &product.Type, &product.Barcode, &product.PVZId, &product.Status, &product.StatusChangedAt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrProductsUnavailable
+			}
+			return fmt.Errorf("error removing product: %w", err)
+		}
+
+		return insertReceptionAudit(ctx, tx, &domain.ReceptionAuditEntry{
+			ReceptionId: receptionId,
+			Action:      constants.ReceptionAuditRemoveProduct,
+			ProductId:   &productId,
+			ProductType: product.Type,
+			Barcode:     product.Barcode,
+			Reason:      reason,
+		}, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (r *receptionCorrectionRepository) GetAudit(ctx context.Context, receptionId uuid.UUID) ([]*domain.ReceptionAuditEntry, error) {
+	query := `
+		SELECT id, reception_id, action, product_id, COALESCE(product_type::text, ''), COALESCE(barcode, ''),
+		       reason, user_id, created_at
+		FROM reception_audit
+		WHERE reception_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, receptionId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reception audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.ReceptionAuditEntry{}
+	for rows.Next() {
+		var entry domain.ReceptionAuditEntry
+		err = rows.Scan(
+			&entry.Id, &entry.ReceptionId, &entry.Action, &entry.ProductId, &entry.ProductType, &entry.Barcode,
+			&entry.Reason, &entry.UserId, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("reception audit could not be retrieved: %w", err)
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// lockClosedReception блокирует закрытую входящую приемку до конца транзакции и возвращает ее ПВЗ и дату.
+func lockClosedReception(ctx context.Context, tx pgx.Tx, receptionId uuid.UUID) (uuid.UUID, time.Time, error) {
+	var (
+		pvzId    uuid.UUID
+		dateTime time.Time
+		status   string
+	)
+
+	query := `SELECT pvz_id, date_time, status FROM receptions WHERE id = $1 AND direction = $2 FOR UPDATE`
+
+	err := tx.QueryRow(ctx, query, receptionId, constants.ReceptionDirectionInbound).Scan(&pvzId, &dateTime, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, time.Time{}, pgx.ErrNoRows
+		}
+		return uuid.Nil, time.Time{}, fmt.Errorf("error fetching reception: %w", err)
+	}
+
+	if status != constants.ReceptionStatusClose {
+		return uuid.Nil, time.Time{}, repository.ErrReceptionNotClosed
+	}
+
+	return pvzId, dateTime, nil
+}
+
+func insertReceptionAudit(ctx context.Context, tx pgx.Tx, entry *domain.ReceptionAuditEntry, userId uuid.UUID) error {
+	query := `
+		INSERT INTO reception_audit (reception_id, action, product_id, product_type, barcode, reason, user_id)
+		VALUES ($1, $2, $3, NULLIF($4, '')::product_type, NULLIF($5, ''), $6, NULLIF($7::uuid, '00000000-0000-0000-0000-000000000000'))
+	`
+
+	_, err := tx.Exec(ctx, query, entry.ReceptionId, entry.Action, entry.ProductId, entry.ProductType, entry.Barcode, entry.Reason, userId)
+	if err != nil {
+		return fmt.Errorf("reception audit could not be saved: %w", err)
+	}
+
+	return nil
+}
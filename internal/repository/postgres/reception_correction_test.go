@@ -0,0 +1,69 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPool подключается к базе с примененными миграциями из TEST_DATABASE_URL.
+// Без переменной тесты репозиториев пропускаются.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestReceptionCorrectionRepository_ReopenReception_NotAutoClosed(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	var pvzId uuid.UUID
+	err := pool.QueryRow(ctx, `INSERT INTO pvz (city) VALUES ($1) RETURNING id`, constants.PVZCityKazan).Scan(&pvzId)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DELETE FROM pvz WHERE id = $1`, pvzId)
+	})
+
+	// Приемка открыта и закрыта двое суток назад.
+	var receptionId uuid.UUID
+	err = pool.QueryRow(ctx, `
+		INSERT INTO receptions (pvz_id, status, direction, date_time, closed_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP - interval '48 hours', CURRENT_TIMESTAMP - interval '47 hours')
+		RETURNING id
+	`, pvzId, constants.ReceptionStatusClose, constants.ReceptionDirectionInbound).Scan(&receptionId)
+	require.NoError(t, err)
+
+	err = NewReceptionCorrectionRepository(pool).ReopenReception(ctx, receptionId, uuid.Nil, "closed by mistake")
+	require.NoError(t, err)
+
+	closed, err := NewReceptionRepository(pool).CloseStaleReceptions(ctx, 12*time.Hour, "no activity for 12h0m0s")
+	require.NoError(t, err)
+
+	for _, reception := range closed {
+		assert.NotEqual(t, receptionId, reception.Id)
+	}
+
+	var status string
+	err = pool.QueryRow(ctx, `SELECT status FROM receptions WHERE id = $1`, receptionId).Scan(&status)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReceptionStatusInProgress, status)
+}
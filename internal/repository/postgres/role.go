@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type roleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRoleRepository(db *pgxpool.Pool) repository.RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) GetRolePermissions(ctx context.Context) (map[string][]string, error) {
+	// LEFT JOIN, чтобы роли без прав тоже считались существующими.
+	query := `
+		SELECT r.name, rp.permission
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		ORDER BY r.name, rp.permission
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make(map[string][]string)
+	for rows.Next() {
+		var role string
+		var permission *string
+		if err = rows.Scan(&role, &permission); err != nil {
+			return nil, fmt.Errorf("could not scan role: %w", err)
+		}
+
+		if _, ok := roles[role]; !ok {
+			roles[role] = []string{}
+		}
+		if permission != nil {
+			roles[role] = append(roles[role], *permission)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// intakeGroupKeys — выражение ключа группировки и способ присоединения товаров. При группировке
+// по типу приемки без товаров не учитываются, в остальных случаях они входят в число приемок.
+var intakeGroupKeys = map[string]struct {
+	key  string
+	join string
+}{
+	constants.StatsGroupByCity: {key: "pvz.city::text", join: "LEFT JOIN"},
+	constants.StatsGroupByPVZ:  {key: "pvz.id::text", join: "LEFT JOIN"},
+	constants.StatsGroupByType: {key: "p.type::text", join: "JOIN"},
+}
+
+type statsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewStatsRepository(db *pgxpool.Pool) repository.StatsRepository {
+	return &statsRepository{db: db}
+}
+
+func (r *statsRepository) GetIntake(ctx context.Context, filter domain.IntakeFilter) ([]*domain.IntakeStat, error) {
+	group, ok := intakeGroupKeys[filter.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown intake grouping %q", filter.GroupBy)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT date_trunc($1, r.date_time) AS bucket, %s AS key,
+		       COUNT(DISTINCT r.id), COUNT(p.id)
+		FROM receptions r
+		JOIN pvz ON pvz.id = r.pvz_id
+		%s products p ON p.reception_id = r.id AND p.status <> $5
+		WHERE r.direction = $2 AND r.date_time >= $3 AND r.date_time < $4
+		GROUP BY bucket, key
+		ORDER BY bucket, key
+	`, group.key, group.join)
+
+	rows, err := r.db.Query(ctx, query, filter.Bucket, constants.ReceptionDirectionInbound, filter.From, filter.To,
+		constants.ProductStatusRemovedByCorrection,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intake stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*domain.IntakeStat
+	for rows.Next() {
+		var stat domain.IntakeStat
+		if err := rows.Scan(&stat.Bucket, &stat.Key, &stat.Receptions, &stat.Products); err != nil {
+			return nil, fmt.Errorf("failed to scan intake stat: %w", err)
+		}
+
+		stats = append(stats, &stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get intake stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (r *statsRepository) GetProductivity(ctx context.Context, filter domain.ProductivityFilter) ([]*domain.EmployeeProductivity, error) {
+	query := `
+		WITH activity AS (
+			SELECT user_id, pvz_id, action, created_at,
+			       created_at - LAG(created_at) OVER (
+			           PARTITION BY user_id, pvz_id, action, created_at::date
+			           ORDER BY created_at
+			       ) AS gap
+			FROM product_activity
+			WHERE user_id IS NOT NULL
+			  AND created_at >= $1 AND created_at < $2
+			  AND (pvz_id = $3 OR $3 = '00000000-0000-0000-0000-000000000000'::uuid)
+		)
+		SELECT a.user_id, COALESCE(u.email, ''), a.pvz_id,
+		       COUNT(DISTINCT a.created_at::date),
+		       COUNT(*) FILTER (WHERE a.action = 'added'),
+		       COUNT(*) FILTER (WHERE a.action = 'deleted'),
+		       EXTRACT(EPOCH FROM AVG(a.gap) FILTER (WHERE a.action = 'added'))::float8
+		FROM activity a
+		LEFT JOIN users u ON u.id = a.user_id
+		GROUP BY a.user_id, u.email, a.pvz_id
+		ORDER BY u.email, a.pvz_id
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.From, filter.To, filter.PVZId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get productivity: %w", err)
+	}
+	defer rows.Close()
+
+	var report []*domain.EmployeeProductivity
+	for rows.Next() {
+		var item domain.EmployeeProductivity
+		err := rows.Scan(
+			&item.UserId, &item.Email, &item.PVZId, &item.Shifts, &item.Scanned, &item.Deleted, &item.AvgScanIntervalSeconds,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan productivity: %w", err)
+		}
+
+		report = append(report, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get productivity: %w", err)
+	}
+
+	return report, nil
+}
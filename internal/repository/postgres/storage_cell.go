@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// storageCellColumns выбирает ячейку вместе с числом товаров в ней; $2, $3, $4 — статусы товаров в ПВЗ.
+const storageCellColumns = `
+	c.id, c.pvz_id, c.code, c.rack, c.shelf, c.cell, c.capacity, c.product_types, c.created_at,
+	(SELECT COUNT(*) FROM products p WHERE p.cell_id = c.id AND p.status IN ($2, $3, $4)) AS occupied
+`
+
+type storageCellRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewStorageCellRepository(db *pgxpool.Pool) repository.StorageCellRepository {
+	return &storageCellRepository{db: db}
+}
+
+func (r *storageCellRepository) CreateCells(ctx context.Context, cells []*domain.StorageCell) error {
+	query := `
+		INSERT INTO storage_cells (pvz_id, code, rack, shelf, cell, capacity, product_types)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	for _, cell := range cells {
+		err := conn(ctx, r.db).QueryRow(ctx, query,
+			cell.PVZId, cell.Code, cell.Rack, cell.Shelf, cell.Cell, cell.Capacity, cell.ProductTypes,
+		).Scan(&cell.Id, &cell.CreatedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.ConstraintName == "storage_cells_pvz_code_key" {
+				return repository.ErrStorageCellTaken
+			}
+			return fmt.Errorf("storage cell could not be created: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *storageCellRepository) GetCells(ctx context.Context, pvzId uuid.UUID) ([]*domain.StorageCell, error) {
+	query := `SELECT ` + storageCellColumns + ` FROM storage_cells c WHERE c.pvz_id = $1 ORDER BY c.code`
+
+	return r.queryCells(ctx, query, pvzId)
+}
+
+func (r *storageCellRepository) DeleteCell(ctx context.Context, pvzId, cellId uuid.UUID) error {
+	query := `
+		WITH target AS (
+			SELECT id, EXISTS (
+				SELECT 1 FROM products WHERE cell_id = storage_cells.id AND status IN ($3, $4, $5)
+			) AS occupied
+			FROM storage_cells
+			WHERE id = $1 AND pvz_id = $2
+		), deleted AS (
+			DELETE FROM storage_cells WHERE id IN (SELECT id FROM target WHERE NOT occupied)
+			RETURNING id
+		)
+		SELECT occupied FROM target
+	`
+
+	var occupied bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, cellId, pvzId,
+		constants.ProductStatusReceived, constants.ProductStatusStored, constants.ProductStatusReturning,
+	).Scan(&occupied)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("storage cell could not be deleted: %w", err)
+	}
+
+	if occupied {
+		return repository.ErrStorageCellNotEmpty
+	}
+
+	return nil
+}
+
+func (r *storageCellRepository) GetCellByCode(ctx context.Context, pvzId uuid.UUID, code string) (*domain.StorageCell, error) {
+	query := `SELECT ` + storageCellColumns + ` FROM storage_cells c WHERE c.pvz_id = $1 AND c.code = $5`
+
+	cells, err := r.queryCells(ctx, query, pvzId, code)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return cells[0], nil
+}
+
+func (r *storageCellRepository) SuggestCell(ctx context.Context, pvzId uuid.UUID, productType string) (*domain.StorageCell, error) {
+	query := `
+		SELECT * FROM (
+			SELECT ` + storageCellColumns + `
+			FROM storage_cells c
+			WHERE c.pvz_id = $1 AND (cardinality(c.product_types) = 0 OR $5 = ANY (c.product_types))
+		) cells
+		WHERE capacity IS NULL OR occupied < capacity
+		ORDER BY occupied, code
+		LIMIT 1
+	`
+
+	cells, err := r.queryCells(ctx, query, pvzId, productType)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return cells[0], nil
+}
+
+func (r *storageCellRepository) AssignProduct(ctx context.Context, productId, cellId uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `UPDATE products SET cell_id = $2 WHERE id = $1`, productId, cellId)
+	if err != nil {
+		return fmt.Errorf("product could not be assigned to storage cell: %w", err)
+	}
+
+	return nil
+}
+
+func (r *storageCellRepository) LocateProducts(ctx context.Context, pvzId, productId uuid.UUID, barcode string) ([]*domain.ProductLocation, error) {
+	query := `
+		SELECT p.id, p.type, COALESCE(p.barcode, ''), p.status, p.pvz_id, c.id, COALESCE(c.code, '')
+		FROM products p
+		LEFT JOIN storage_cells c ON c.id = p.cell_id
+		WHERE p.pvz_id = $1 AND p.status IN ($2, $3, $4)
+		  AND (p.id = $5 OR (NULLIF($6, '') IS NOT NULL AND p.barcode = $6))
+		ORDER BY p.date_time
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, pvzId,
+		constants.ProductStatusReceived, constants.ProductStatusStored, constants.ProductStatusReturning,
+		productId, barcode,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error locating products: %w", err)
+	}
+	defer rows.Close()
+
+	locations := []*domain.ProductLocation{}
+	for rows.Next() {
+		var location domain.ProductLocation
+		err = rows.Scan(
+			&location.ProductId, &location.Type, &location.Barcode, &location.Status, &location.PVZId,
+			&location.CellId, &location.CellCode,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("product location could not be retrieved: %w", err)
+		}
+
+		locations = append(locations, &location)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return locations, nil
+}
+
+// queryCells выполняет запрос со storageCellColumns; первым аргументом идет id ПВЗ, extra — с $5.
+func (r *storageCellRepository) queryCells(ctx context.Context, query string, pvzId uuid.UUID, extra ...any) ([]*domain.StorageCell, error) {
+	args := []any{pvzId, constants.ProductStatusReceived, constants.ProductStatusStored, constants.ProductStatusReturning}
+	args = append(args, extra...)
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching storage cells: %w", err)
+	}
+	defer rows.Close()
+
+	cells := []*domain.StorageCell{}
+	for rows.Next() {
+		var cell domain.StorageCell
+		err = rows.Scan(
+			&cell.Id, &cell.PVZId, &cell.Code, &cell.Rack, &cell.Shelf, &cell.Cell, &cell.Capacity,
+			&cell.ProductTypes, &cell.CreatedAt, &cell.Occupied,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("storage cells could not be retrieved: %w", err)
+		}
+
+		cells = append(cells, &cell)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,338 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type transferRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTransferRepository(db *pgxpool.Pool) repository.TransferRepository {
+	return &transferRepository{db: db}
+}
+
+func (r *transferRepository) CreateTransfer(ctx context.Context, transfer *domain.Transfer, productIds []uuid.UUID, userId uuid.UUID) error {
+	return pgx.BeginFunc(ctx, conn(ctx, r.db), func(tx pgx.Tx) error {
+		insert := `
+			INSERT INTO transfers (source_pvz_id, destination_pvz_id, status, dispatched_by)
+			SELECT $1, id, $3, NULLIF($4::uuid, '00000000-0000-0000-0000-000000000000')
+			FROM pvz WHERE id = $2
+			RETURNING id, dispatched_at
+		`
+
+		err := tx.QueryRow(ctx, insert, transfer.SourcePVZId, transfer.DestinationPVZId, constants.TransferStatusDispatched, userId).
+			Scan(&transfer.Id, &transfer.DispatchedAt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgx.ErrNoRows
+			}
+			return fmt.Errorf("transfer could not be created: %w", err)
+		}
+		transfer.Status = constants.TransferStatusDispatched
+
+		// Товары уходят из остатков источника; исходная приемка сохраняется в позиции перемещения.
+		items := `
+			WITH moved AS (
+			    UPDATE products
+			    SET status = $3, status_changed_at = CURRENT_TIMESTAMP,
+			        status_changed_by = NULLIF($5::uuid, '00000000-0000-0000-0000-000000000000')
+			    WHERE id = ANY($2) AND pvz_id = $4 AND status = $6
+			    RETURNING id, reception_id
+			)
+			INSERT INTO transfer_items (transfer_id, product_id, source_reception_id)
+			SELECT $1, id, reception_id FROM moved
+			RETURNING product_id, source_reception_id
+		`
+
+		rows, err := tx.Query(ctx, items,
+			transfer.Id, productIds, constants.ProductStatusInTransit, transfer.SourcePVZId, userId, constants.ProductStatusStored,
+		)
+		if err != nil {
+			return fmt.Errorf("transfer items could not be created: %w", err)
+		}
+
+		transfer.Items, err = scanTransferItems(rows)
+		if err != nil {
+			return err
+		}
+
+		if len(transfer.Items) != len(productIds) {
+			return repository.ErrProductsUnavailable
+		}
+
+		return nil
+	})
+}
+
+func (r *transferRepository) LockDispatchedTransfer(ctx context.Context, transferId uuid.UUID) (*domain.Transfer, error) {
+	query := `
+		SELECT id, source_pvz_id, destination_pvz_id, status, dispatched_at
+		FROM transfers
+		WHERE id = $1 AND status = $2
+		FOR UPDATE
+	`
+
+	var transfer domain.Transfer
+	err := conn(ctx, r.db).QueryRow(ctx, query, transferId, constants.TransferStatusDispatched).Scan(
+		&transfer.Id, &transfer.SourcePVZId, &transfer.DestinationPVZId, &transfer.Status, &transfer.DispatchedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error fetching transfer: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT product_id, source_reception_id FROM transfer_items WHERE transfer_id = $1`, transferId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching transfer items: %w", err)
+	}
+
+	transfer.Items, err = scanTransferItems(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func (r *transferRepository) GetPickupCodeConflicts(ctx context.Context, transferId uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT p.id
+		FROM transfer_items ti
+		JOIN transfers t ON t.id = ti.transfer_id
+		JOIN products p ON p.id = ti.product_id
+		WHERE ti.transfer_id = $1 AND p.status = $2 AND EXISTS (
+		    SELECT 1 FROM products o
+		    WHERE o.pvz_id = t.destination_pvz_id AND o.pickup_code = p.pickup_code AND o.status IN ($3, $4)
+		)
+		ORDER BY p.id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query,
+		transferId, constants.ProductStatusInTransit, constants.ProductStatusReceived, constants.ProductStatusStored,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("pickup code conflicts could not be retrieved: %w", err)
+	}
+	defer rows.Close()
+
+	productIds := []uuid.UUID{}
+	for rows.Next() {
+		var productId uuid.UUID
+		if err = rows.Scan(&productId); err != nil {
+			return nil, fmt.Errorf("pickup code conflicts could not be retrieved: %w", err)
+		}
+
+		productIds = append(productIds, productId)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return productIds, nil
+}
+
+func (r *transferRepository) ReissuePickupCode(ctx context.Context, transferId, productId uuid.UUID, pickupCode string) error {
+	// Товар в пути не входит в уникальный индекс кодов, поэтому занятость проверяется здесь:
+	// среди товаров ПВЗ назначения и среди остальных товаров перемещения.
+	query := `
+		UPDATE products p
+		SET pickup_code = $3
+		FROM transfers t
+		WHERE t.id = $1 AND p.id = $2 AND p.status = $4 AND NOT EXISTS (
+		    SELECT 1 FROM products o
+		    WHERE o.pickup_code = $3 AND o.id <> p.id AND (
+		        (o.pvz_id = t.destination_pvz_id AND o.status IN ($5, $6))
+		        OR o.id IN (SELECT product_id FROM transfer_items WHERE transfer_id = $1)
+		    )
+		)
+	`
+
+	cmdTag, err := conn(ctx, r.db).Exec(ctx, query, transferId, productId, pickupCode,
+		constants.ProductStatusInTransit, constants.ProductStatusReceived, constants.ProductStatusStored,
+	)
+	if err != nil {
+		return fmt.Errorf("pickup code could not be reissued: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return repository.ErrPickupCodeTaken
+	}
+
+	return nil
+}
+
+func (r *transferRepository) ReceiveTransfer(ctx context.Context, transfer *domain.Transfer, userId uuid.UUID) error {
+	var receptionId uuid.UUID
+	reception := `
+		SELECT id FROM receptions
+		WHERE pvz_id = $1 AND status = $2 AND direction = $3
+		ORDER BY date_time DESC
+		LIMIT 1
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, reception,
+		transfer.DestinationPVZId, constants.ReceptionStatusInProgress, constants.ReceptionDirectionInbound,
+	).Scan(&receptionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrNoOpenReception
+		}
+		return fmt.Errorf("error fetching reception: %w", err)
+	}
+
+	// Ячейка прежнего ПВЗ сбрасывается.
+	move := `
+		UPDATE products
+		SET pvz_id = $2, reception_id = $3, status = $4, status_changed_at = CURRENT_TIMESTAMP, cell_id = NULL,
+		    status_changed_by = NULLIF($5::uuid, '00000000-0000-0000-0000-000000000000'), overdue_at = NULL
+		WHERE id IN (SELECT product_id FROM transfer_items WHERE transfer_id = $1) AND status = $6
+	`
+
+	_, err = conn(ctx, r.db).Exec(ctx, move,
+		transfer.Id, transfer.DestinationPVZId, receptionId, constants.ProductStatusReceived, userId,
+		constants.ProductStatusInTransit,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "products_pvz_pickup_code_key" {
+			return repository.ErrPickupCodeTaken
+		}
+		return fmt.Errorf("transfer products could not be moved: %w", err)
+	}
+
+	update := `
+		UPDATE transfers
+		SET status = $2, received_at = CURRENT_TIMESTAMP, reception_id = $3,
+		    received_by = NULLIF($4::uuid, '00000000-0000-0000-0000-000000000000')
+		WHERE id = $1
+		RETURNING received_at
+	`
+
+	err = conn(ctx, r.db).QueryRow(ctx, update, transfer.Id, constants.TransferStatusReceived, receptionId, userId).
+		Scan(&transfer.ReceivedAt)
+	if err != nil {
+		return fmt.Errorf("transfer could not be received: %w", err)
+	}
+
+	transfer.Status = constants.TransferStatusReceived
+	transfer.ReceptionId = &receptionId
+
+	return nil
+}
+
+func (r *transferRepository) GetTransferVolume(ctx context.Context, transferId uuid.UUID) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(p.volume), 0)::float8
+		FROM transfer_items ti
+		JOIN products p ON p.id = ti.product_id
+		WHERE ti.transfer_id = $1
+	`
+
+	var volume float64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, transferId).Scan(&volume); err != nil {
+		return 0, fmt.Errorf("error fetching transfer volume: %w", err)
+	}
+
+	return volume, nil
+}
+
+func (r *transferRepository) GetTransferById(ctx context.Context, transferId uuid.UUID) (*domain.Transfer, error) {
+	query := `
+		SELECT id, source_pvz_id, destination_pvz_id, status, dispatched_at, received_at, reception_id
+		FROM transfers
+		WHERE id = $1
+	`
+
+	var transfer domain.Transfer
+	err := conn(ctx, r.db).QueryRow(ctx, query, transferId).Scan(
+		&transfer.Id, &transfer.SourcePVZId, &transfer.DestinationPVZId, &transfer.Status,
+		&transfer.DispatchedAt, &transfer.ReceivedAt, &transfer.ReceptionId,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error fetching transfer: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT product_id, source_reception_id FROM transfer_items WHERE transfer_id = $1`, transferId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching transfer items: %w", err)
+	}
+
+	transfer.Items, err = scanTransferItems(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func (r *transferRepository) GetTransfersByPVZId(ctx context.Context, pvzId uuid.UUID, offset, limit int) ([]*domain.Transfer, error) {
+	query := `
+		SELECT id, source_pvz_id, destination_pvz_id, status, dispatched_at, received_at, reception_id
+		FROM transfers
+		WHERE source_pvz_id = $1 OR destination_pvz_id = $1
+		ORDER BY dispatched_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, pvzId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("transfers could not be retrieved: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := []*domain.Transfer{}
+	for rows.Next() {
+		var transfer domain.Transfer
+		err = rows.Scan(
+			&transfer.Id, &transfer.SourcePVZId, &transfer.DestinationPVZId, &transfer.Status,
+			&transfer.DispatchedAt, &transfer.ReceivedAt, &transfer.ReceptionId,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("transfers could not be retrieved: %w", err)
+		}
+
+		transfers = append(transfers, &transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return transfers, nil
+}
+
+func scanTransferItems(rows pgx.Rows) ([]*domain.TransferItem, error) {
+	defer rows.Close()
+
+	items := []*domain.TransferItem{}
+	for rows.Next() {
+		var item domain.TransferItem
+		if err := rows.Scan(&item.ProductId, &item.SourceReceptionId); err != nil {
+			return nil, fmt.Errorf("transfer items could not be retrieved: %w", err)
+		}
+
+		items = append(items, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return items, nil
+}
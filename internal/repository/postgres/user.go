@@ -7,10 +7,16 @@ import (
 	"github.com/aliskhannn/pvz-service/internal/auth"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"strings"
+	"time"
 )
 
+// likeEscaper экранирует служебные символы LIKE, чтобы поиск шел по подстроке как есть.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type userRepository struct {
 	db *pgxpool.Pool
 }
@@ -26,8 +32,8 @@ func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) erro
 	}
 	user.Password = hashedPassword
 
-	query := `INSERT INTO users (email, password, role) VALUES ($1, $2, $3)`
-	_, err = r.db.Exec(ctx, query, user.Email, user.Password, user.Role)
+	query := `INSERT INTO users (email, password, role, email_verified) VALUES ($1, $2, $3, $4) RETURNING id`
+	err = r.db.QueryRow(ctx, query, user.Email, user.Password, user.Role, user.EmailVerified).Scan(&user.Id)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
@@ -35,11 +41,23 @@ func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) erro
 	return nil
 }
 
+const userColumns = `
+	id, email, password, role, email_verified, disabled, last_login_at, created_at,
+	failed_login_attempts, locked_until
+`
+
+func scanUser(row pgx.Row, user *domain.User) error {
+	return row.Scan(
+		&user.Id, &user.Email, &user.Password, &user.Role, &user.EmailVerified, &user.Disabled, &user.LastLoginAt,
+		&user.CreatedAt, &user.FailedLoginAttempts, &user.LockedUntil,
+	)
+}
+
 func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
 
-	query := `SELECT id, email, password, role FROM users WHERE email = $1`
-	err := r.db.QueryRow(ctx, query, email).Scan(&user.Id, &user.Email, &user.Password, &user.Role)
+	query := `SELECT ` + userColumns + ` FROM users WHERE lower(email) = lower($1)`
+	err := scanUser(r.db.QueryRow(ctx, query, email), &user)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, pgx.ErrNoRows
@@ -49,3 +67,163 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*dom
 
 	return &user, nil
 }
+
+func (r *userRepository) RegisterFailedLogin(ctx context.Context, userId uuid.UUID, maxAttempts int, lockDuration time.Duration) error {
+	// При достижении лимита блокируем аккаунт и начинаем отсчет попыток заново.
+	query := `
+		UPDATE users
+		SET failed_login_attempts = CASE
+		        WHEN failed_login_attempts + 1 >= $2 THEN 0
+		        ELSE failed_login_attempts + 1
+		    END,
+		    locked_until = CASE
+		        WHEN failed_login_attempts + 1 >= $2 THEN CURRENT_TIMESTAMP + $3::interval
+		        ELSE locked_until
+		    END
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, userId, maxAttempts, lockDuration)
+	if err != nil {
+		return fmt.Errorf("failed to register failed login: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) ResetFailedLogins(ctx context.Context, userId uuid.UUID) error {
+	query := `UPDATE users SET failed_login_attempts = 0, locked_until = NULL WHERE id = $1`
+
+	_, err := r.db.Exec(ctx, query, userId)
+	if err != nil {
+		return fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) CreateVerificationToken(ctx context.Context, userId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO email_verification_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`
+
+	_, err := r.db.Exec(ctx, query, tokenHash, userId, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) VerifyEmail(ctx context.Context, tokenHash string, now time.Time) error {
+	query := `
+		WITH consumed AS (
+		    DELETE FROM email_verification_tokens
+		    WHERE token_hash = $1 AND expires_at > $2
+		    RETURNING user_id
+		)
+		UPDATE users
+		SET email_verified = TRUE
+		WHERE id IN (SELECT user_id FROM consumed)
+	`
+
+	cmdTag, err := r.db.Exec(ctx, query, tokenHash, now)
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *userRepository) GetUserById(ctx context.Context, userId uuid.UUID) (*domain.User, error) {
+	var user domain.User
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	err := scanUser(r.db.QueryRow(ctx, query, userId), &user)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *userRepository) ListUsers(ctx context.Context, search string, offset, limit int) ([]*domain.User, int, error) {
+	query := `
+		SELECT ` + userColumns + `, count(*) OVER ()
+		FROM users
+		WHERE $1 = '' OR email ILIKE '%' || $1 || '%' ESCAPE '\'
+		ORDER BY created_at DESC, email
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, likeEscaper.Replace(search), limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	var total int
+	for rows.Next() {
+		var user domain.User
+		err = rows.Scan(
+			&user.Id, &user.Email, &user.Password, &user.Role, &user.EmailVerified, &user.Disabled, &user.LastLoginAt,
+			&user.CreatedAt, &user.FailedLoginAttempts, &user.LockedUntil, &total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not scan user: %w", err)
+		}
+
+		user.Password = ""
+		users = append(users, &user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return users, total, nil
+}
+
+func (r *userRepository) UpdateUserRole(ctx context.Context, userId uuid.UUID, role string) error {
+	return r.execOnUser(ctx, `UPDATE users SET role = $2 WHERE id = $1`, userId, role)
+}
+
+func (r *userRepository) SetUserDisabled(ctx context.Context, userId uuid.UUID, disabled bool) error {
+	return r.execOnUser(ctx, `UPDATE users SET disabled = $2 WHERE id = $1`, userId, disabled)
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userId uuid.UUID, password string) error {
+	hashedPassword, err := auth.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// Сброс пароля также снимает блокировку после неудачных входов.
+	query := `UPDATE users SET password = $2, failed_login_attempts = 0, locked_until = NULL WHERE id = $1`
+
+	return r.execOnUser(ctx, query, userId, hashedPassword)
+}
+
+func (r *userRepository) UpdateLastLogin(ctx context.Context, userId uuid.UUID, at time.Time) error {
+	return r.execOnUser(ctx, `UPDATE users SET last_login_at = $2 WHERE id = $1`, userId, at)
+}
+
+// execOnUser выполняет UPDATE одного пользователя и возвращает pgx.ErrNoRows, если его нет.
+func (r *userRepository) execOnUser(ctx context.Context, query string, userId uuid.UUID, value interface{}) error {
+	cmdTag, err := r.db.Exec(ctx, query, userId, value)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
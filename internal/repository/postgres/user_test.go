@@ -0,0 +1,42 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_ListUsers_EscapesWildcards(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	suffix := uuid.NewString()[:8]
+	emails := []string{"under_score." + suffix + "@example.com", "underXscore." + suffix + "@example.com"}
+	for _, email := range emails {
+		_, err := pool.Exec(ctx, `INSERT INTO users (email, password, role) VALUES ($1, 'hash', 'employee')`, email)
+		require.NoError(t, err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DELETE FROM users WHERE email = ANY($1)`, emails)
+	})
+
+	repo := NewUserRepository(pool)
+
+	// "_" ищется как символ, а не как любой символ.
+	users, total, err := repo.ListUsers(ctx, "_score."+suffix, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, emails[0], users[0].Email)
+	}
+
+	users, total, err = repo.ListUsers(ctx, "%."+suffix, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, users)
+}
@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type webhookRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *pgxpool.Pool) repository.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, pvz_ids, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		subscription.URL, subscription.Secret, subscription.EventTypes, subscription.PVZIds, subscription.CreatedBy,
+	).Scan(&subscription.Id, &subscription.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("webhook subscription could not be created: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) GetSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, event_types, pvz_ids, created_by, created_at
+		FROM webhook_subscriptions
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*domain.WebhookSubscription{}
+	for rows.Next() {
+		var subscription domain.WebhookSubscription
+		err = rows.Scan(
+			&subscription.Id, &subscription.URL, &subscription.EventTypes, &subscription.PVZIds,
+			&subscription.CreatedBy, &subscription.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("webhook subscriptions could not be retrieved: %w", err)
+		}
+
+		subscriptions = append(subscriptions, &subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, subscriptionId uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, subscriptionId)
+	if err != nil {
+		return fmt.Errorf("webhook subscription could not be deleted: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) EnqueueDeliveries(ctx context.Context, event *domain.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT id, $1, $2, $4
+		FROM webhook_subscriptions
+		WHERE (cardinality(event_types) = 0 OR $2 = ANY (event_types))
+		  AND (cardinality(pvz_ids) = 0 OR $3 = ANY (pvz_ids))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	if _, err = r.db.Exec(ctx, query, event.Id, event.Type, event.PVZId, payload); err != nil {
+		return fmt.Errorf("webhook deliveries could not be created: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) GetDueDeliveries(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+		       d.next_attempt_at, COALESCE(d.last_error, ''), d.response_status, d.created_at, d.delivered_at,
+		       s.url, s.secret
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = $1 AND d.next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY d.next_attempt_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, constants.WebhookDeliveryStatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		err = rows.Scan(
+			&delivery.Id, &delivery.SubscriptionId, &delivery.EventId, &delivery.EventType, &delivery.Payload,
+			&delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastError,
+			&delivery.ResponseStatus, &delivery.CreatedAt, &delivery.DeliveredAt, &delivery.URL, &delivery.Secret,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("webhook deliveries could not be retrieved: %w", err)
+		}
+
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = NULLIF($5, ''),
+		    response_status = $6, delivered_at = $7
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		delivery.Id, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastError,
+		delivery.ResponseStatus, delivery.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("webhook delivery could not be updated: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) GetDeliveries(ctx context.Context, subscriptionId uuid.UUID, status string, offset, limit int) ([]*domain.WebhookDelivery, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM webhook_subscriptions WHERE id = $1)`, subscriptionId).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook subscription: %w", err)
+	}
+	if !exists {
+		return nil, pgx.ErrNoRows
+	}
+
+	query := `
+		SELECT id, subscription_id, event_id, event_type, status, attempts, next_attempt_at,
+		       COALESCE(last_error, ''), response_status, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status::text = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, subscriptionId, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		err = rows.Scan(
+			&delivery.Id, &delivery.SubscriptionId, &delivery.EventId, &delivery.EventType, &delivery.Status,
+			&delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastError, &delivery.ResponseStatus,
+			&delivery.CreatedAt, &delivery.DeliveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("webhook deliveries could not be retrieved: %w", err)
+		}
+
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return deliveries, nil
+}
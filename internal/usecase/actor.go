@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// actorId возвращает id пользователя для полей "кем изменено". Dummy-пользователя нет в БД,
+// поэтому для него возвращается uuid.Nil, который репозитории сохраняют как NULL.
+func actorId(user *domain.User) uuid.UUID {
+	if user.Dummy {
+		return uuid.Nil
+	}
+
+	return user.Id
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/auth"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"math/big"
+)
+
+type AdminUseCase interface {
+	ListUsers(ctx context.Context, actor *domain.User, search string, page, limit int) (*domain.UserList, error)
+	GetUser(ctx context.Context, actor *domain.User, userId uuid.UUID) (*domain.User, error)
+	ChangeUserRole(ctx context.Context, actor *domain.User, userId uuid.UUID, role string) error
+	SetUserDisabled(ctx context.Context, actor *domain.User, userId uuid.UUID, disabled bool) error
+	// ResetUserPassword задает пользователю новый пароль. Если password пустой,
+	// генерируется временный пароль и возвращается вызывающему.
+	ResetUserPassword(ctx context.Context, actor *domain.User, userId uuid.UUID, password string) (string, error)
+	GetUserPVZs(ctx context.Context, actor *domain.User, userId uuid.UUID) ([]uuid.UUID, error)
+	AssignPVZ(ctx context.Context, actor *domain.User, userId, pvzId uuid.UUID) error
+	UnassignPVZ(ctx context.Context, actor *domain.User, userId, pvzId uuid.UUID) error
+}
+
+type adminUseCase struct {
+	repo           repository.UserRepository
+	assignments    repository.AssignmentRepository
+	authorizer     authz.Authorizer
+	passwordPolicy auth.PasswordPolicy
+}
+
+func NewAdminUseCase(repo repository.UserRepository, assignments repository.AssignmentRepository, authorizer authz.Authorizer, passwordPolicy auth.PasswordPolicy) AdminUseCase {
+	return &adminUseCase{
+		repo:           repo,
+		assignments:    assignments,
+		authorizer:     authorizer,
+		passwordPolicy: passwordPolicy,
+	}
+}
+
+func (uc *adminUseCase) ListUsers(ctx context.Context, actor *domain.User, search string, page, limit int) (*domain.UserList, error) {
+	if err := uc.authorizer.Authorize(actor, authz.PermUserManage); err != nil {
+		return nil, err
+	}
+
+	if page < 1 || limit < 1 {
+		return nil, appErr.ErrBadRequest
+	}
+
+	users, total, err := uc.repo.ListUsers(ctx, search, (page-1)*limit, limit)
+	if err != nil {
+		return nil, appErr.ErrGettingUsers
+	}
+
+	if users == nil {
+		users = []*domain.User{}
+	}
+
+	return &domain.UserList{
+		Users: users,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+func (uc *adminUseCase) GetUser(ctx context.Context, actor *domain.User, userId uuid.UUID) (*domain.User, error) {
+	if err := uc.authorizer.Authorize(actor, authz.PermUserManage); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.repo.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, mapUserRepoError(err, appErr.ErrGettingUser)
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+func (uc *adminUseCase) ChangeUserRole(ctx context.Context, actor *domain.User, userId uuid.UUID, role string) error {
+	if err := uc.authorizer.Authorize(actor, authz.PermUserManage); err != nil {
+		return err
+	}
+
+	if !uc.authorizer.RoleExists(role) {
+		return appErr.ErrInvalidRole
+	}
+
+	if actor.Id == userId {
+		return appErr.ErrCannotModifySelf
+	}
+
+	err := uc.repo.UpdateUserRole(ctx, userId, role)
+	if err != nil {
+		return mapUserRepoError(err, appErr.ErrUpdatingUser)
+	}
+
+	return nil
+}
+
+func (uc *adminUseCase) SetUserDisabled(ctx context.Context, actor *domain.User, userId uuid.UUID, disabled bool) error {
+	if err := uc.authorizer.Authorize(actor, authz.PermUserManage); err != nil {
+		return err
+	}
+
+	if actor.Id == userId {
+		return appErr.ErrCannotModifySelf
+	}
+
+	err := uc.repo.SetUserDisabled(ctx, userId, disabled)
+	if err != nil {
+		return mapUserRepoError(err, appErr.ErrUpdatingUser)
+	}
+
+	return nil
+}
+
+func (uc *adminUseCase) ResetUserPassword(ctx context.Context, actor *domain.User, userId uuid.UUID, password string) (string, error) {
+	if err := uc.authorizer.Authorize(actor, authz.PermUserManage); err != nil {
+		return "", err
+	}
+
+	if password == "" {
+		generated, err := generatePassword(uc.passwordPolicy)
+		if err != nil {
+			return "", appErr.ErrInternal
+		}
+		password = generated
+	}
+
+	if err := uc.passwordPolicy.Validate(password); err != nil {
+		return "", err
+	}
+
+	err := uc.repo.UpdatePassword(ctx, userId, password)
+	if err != nil {
+		return "", mapUserRepoError(err, appErr.ErrUpdatingUser)
+	}
+
+	return password, nil
+}
+
+func (uc *adminUseCase) GetUserPVZs(ctx context.Context, actor *domain.User, userId uuid.UUID) ([]uuid.UUID, error) {
+	if err := uc.authorizer.Authorize(actor, authz.PermPVZAssign); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.repo.GetUserById(ctx, userId); err != nil {
+		return nil, mapUserRepoError(err, appErr.ErrGettingUser)
+	}
+
+	pvzIds, err := uc.assignments.GetUserPVZIds(ctx, userId)
+	if err != nil {
+		return nil, appErr.ErrGettingAssignments
+	}
+
+	return pvzIds, nil
+}
+
+func (uc *adminUseCase) AssignPVZ(ctx context.Context, actor *domain.User, userId, pvzId uuid.UUID) error {
+	if err := uc.authorizer.Authorize(actor, authz.PermPVZAssign); err != nil {
+		return err
+	}
+
+	user, err := uc.repo.GetUserById(ctx, userId)
+	if err != nil {
+		return mapUserRepoError(err, appErr.ErrGettingUser)
+	}
+
+	// Закрепляются только роли, работающие с приемками на ПВЗ.
+	if !uc.authorizer.Can(user.Role, authz.PermReceptionOpen) {
+		return appErr.ErrAssignNonEmployee
+	}
+
+	err = uc.assignments.AssignPVZ(ctx, userId, pvzId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErr.ErrPVZNotFound
+		}
+		return appErr.ErrUpdatingAssignments
+	}
+
+	return nil
+}
+
+func (uc *adminUseCase) UnassignPVZ(ctx context.Context, actor *domain.User, userId, pvzId uuid.UUID) error {
+	if err := uc.authorizer.Authorize(actor, authz.PermPVZAssign); err != nil {
+		return err
+	}
+
+	err := uc.assignments.UnassignPVZ(ctx, userId, pvzId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErr.ErrAssignmentNotFound
+		}
+		return appErr.ErrUpdatingAssignments
+	}
+
+	return nil
+}
+
+func mapUserRepoError(err error, fallback error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErr.ErrUserNotFound
+	}
+
+	return fallback
+}
+
+const (
+	passwordUpper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordLower   = "abcdefghijkmnopqrstuvwxyz"
+	passwordDigits  = "23456789"
+	passwordSpecial = "!@#$%^&*-_=+?"
+)
+
+// generatePassword создает случайный пароль, в котором есть символы каждого класса,
+// поэтому он проходит любую комбинацию требований политики.
+func generatePassword(policy auth.PasswordPolicy) (string, error) {
+	length := 16
+	if policy.MinLength > length {
+		length = policy.MinLength
+	}
+
+	classes := []string{passwordUpper, passwordLower, passwordDigits, passwordSpecial}
+	all := passwordUpper + passwordLower + passwordDigits + passwordSpecial
+
+	password := make([]byte, 0, length)
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	for len(password) < length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(alphabet string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+	if err != nil {
+		return 0, err
+	}
+
+	return alphabet[n.Int64()], nil
+}
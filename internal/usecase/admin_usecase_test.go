@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/auth"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdminUseCase_ListUsers(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		page      int
+		limit     int
+		mockRepo  bool
+		users     []*domain.User
+		total     int
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:     "Valid request",
+			user:     moderator,
+			page:     2,
+			limit:    10,
+			mockRepo: true,
+			users:    []*domain.User{{Id: uuid.New(), Email: "a@example.com"}},
+			total:    11,
+		},
+		{
+			name:     "Empty result",
+			user:     moderator,
+			page:     1,
+			limit:    10,
+			mockRepo: true,
+		},
+		{
+			name:      "Nil user",
+			page:      1,
+			limit:     10,
+			expectErr: appErr.ErrUserRequired,
+		},
+		{
+			name:      "Employee",
+			user:      &domain.User{Role: constants.UserRoleEmployee},
+			page:      1,
+			limit:     10,
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Invalid page",
+			user:      moderator,
+			page:      0,
+			limit:     10,
+			expectErr: appErr.ErrBadRequest,
+		},
+		{
+			name:      "Repository error",
+			user:      moderator,
+			page:      1,
+			limit:     10,
+			mockRepo:  true,
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrGettingUsers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockUserRepository{}
+			adminUC := NewAdminUseCase(repo, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), auth.PasswordPolicy{})
+
+			if tt.mockRepo {
+				repo.On("ListUsers", mock.Anything, "", (tt.page-1)*tt.limit, tt.limit).
+					Return(tt.users, tt.total, tt.repoErr).
+					Once()
+			}
+
+			list, err := adminUC.ListUsers(context.Background(), tt.user, "", tt.page, tt.limit)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, list)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, list.Users)
+				assert.Len(t, list.Users, len(tt.users))
+				assert.Equal(t, tt.total, list.Total)
+				assert.Equal(t, tt.page, list.Page)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminUseCase_ChangeUserRole(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	targetId := uuid.New()
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		userId    uuid.UUID
+		role      string
+		mockRepo  bool
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:     "Valid role change",
+			user:     moderator,
+			userId:   targetId,
+			role:     constants.UserRoleModerator,
+			mockRepo: true,
+		},
+		{
+			name:      "Employee",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			userId:    targetId,
+			role:      constants.UserRoleModerator,
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Invalid role",
+			user:      moderator,
+			userId:    targetId,
+			role:      "admin",
+			expectErr: appErr.ErrInvalidRole,
+		},
+		{
+			name:      "Own role",
+			user:      moderator,
+			userId:    moderator.Id,
+			role:      constants.UserRoleEmployee,
+			expectErr: appErr.ErrCannotModifySelf,
+		},
+		{
+			name:      "User not found",
+			user:      moderator,
+			userId:    targetId,
+			role:      constants.UserRoleEmployee,
+			mockRepo:  true,
+			repoErr:   pgx.ErrNoRows,
+			expectErr: appErr.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockUserRepository{}
+			adminUC := NewAdminUseCase(repo, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), auth.PasswordPolicy{})
+
+			if tt.mockRepo {
+				repo.On("UpdateUserRole", mock.Anything, tt.userId, tt.role).Return(tt.repoErr).Once()
+			}
+
+			err := adminUC.ChangeUserRole(context.Background(), tt.user, tt.userId, tt.role)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminUseCase_SetUserDisabled(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	targetId := uuid.New()
+
+	tests := []struct {
+		name      string
+		userId    uuid.UUID
+		disabled  bool
+		mockRepo  bool
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:     "Disable user",
+			userId:   targetId,
+			disabled: true,
+			mockRepo: true,
+		},
+		{
+			name:     "Enable user",
+			userId:   targetId,
+			disabled: false,
+			mockRepo: true,
+		},
+		{
+			name:      "Disable self",
+			userId:    moderator.Id,
+			disabled:  true,
+			expectErr: appErr.ErrCannotModifySelf,
+		},
+		{
+			name:      "Repository error",
+			userId:    targetId,
+			disabled:  true,
+			mockRepo:  true,
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrUpdatingUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockUserRepository{}
+			adminUC := NewAdminUseCase(repo, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), auth.PasswordPolicy{})
+
+			if tt.mockRepo {
+				repo.On("SetUserDisabled", mock.Anything, tt.userId, tt.disabled).Return(tt.repoErr).Once()
+			}
+
+			err := adminUC.SetUserDisabled(context.Background(), moderator, tt.userId, tt.disabled)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminUseCase_ResetUserPassword(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	targetId := uuid.New()
+	policy := auth.PasswordPolicy{
+		MinLength:      12,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSpecial: true,
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		mockRepo  bool
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:     "Explicit password",
+			password: "Str0ng-Passw0rd",
+			mockRepo: true,
+		},
+		{
+			name:     "Generated password",
+			mockRepo: true,
+		},
+		{
+			name:      "Weak password",
+			password:  "weak",
+			expectErr: appErr.ErrWeakPassword,
+		},
+		{
+			name:      "User not found",
+			password:  "Str0ng-Passw0rd",
+			mockRepo:  true,
+			repoErr:   pgx.ErrNoRows,
+			expectErr: appErr.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockUserRepository{}
+			adminUC := NewAdminUseCase(repo, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), policy)
+
+			if tt.mockRepo {
+				repo.On("UpdatePassword", mock.Anything, targetId, mock.Anything).Return(tt.repoErr).Once()
+			}
+
+			password, err := adminUC.ResetUserPassword(context.Background(), moderator, targetId, tt.password)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.NoError(t, policy.Validate(password))
+				if tt.password != "" {
+					assert.Equal(t, tt.password, password)
+				}
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminUseCase_AssignPVZ(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	pvzId := uuid.New()
+
+	tests := []struct {
+		name       string
+		target     *domain.User
+		targetErr  error
+		mockAssign bool
+		assignErr  error
+		expectErr  error
+	}{
+		{
+			name:       "Valid assignment",
+			target:     employee,
+			mockAssign: true,
+		},
+		{
+			name:      "User not found",
+			targetErr: pgx.ErrNoRows,
+			expectErr: appErr.ErrUserNotFound,
+		},
+		{
+			name:      "Moderator cannot be assigned",
+			target:    &domain.User{Id: employee.Id, Role: constants.UserRoleModerator},
+			expectErr: appErr.ErrAssignNonEmployee,
+		},
+		{
+			name:       "PVZ not found",
+			target:     employee,
+			mockAssign: true,
+			assignErr:  pgx.ErrNoRows,
+			expectErr:  appErr.ErrPVZNotFound,
+		},
+		{
+			name:       "Repository error",
+			target:     employee,
+			mockAssign: true,
+			assignErr:  errors.New("db error"),
+			expectErr:  appErr.ErrUpdatingAssignments,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockUserRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			adminUC := NewAdminUseCase(repo, assignments, authz.New(authz.DefaultRoles()), auth.PasswordPolicy{})
+
+			repo.On("GetUserById", mock.Anything, employee.Id).Return(tt.target, tt.targetErr).Once()
+			if tt.mockAssign {
+				assignments.On("AssignPVZ", mock.Anything, employee.Id, pvzId).Return(tt.assignErr).Once()
+			}
+
+			err := adminUC.AssignPVZ(context.Background(), moderator, employee.Id, pvzId)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+			assignments.AssertExpectations(t)
+		})
+	}
+}
@@ -2,44 +2,80 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/aliskhannn/pvz-service/internal/auth"
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/aliskhannn/pvz-service/internal/domain/token"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/mail"
 	"github.com/aliskhannn/pvz-service/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"strings"
+	"time"
 )
 
 type AuthUseCase interface {
-	DummyLogin(ctx context.Context, role string) (string, error)
+	// DummyLogin выдает тестовый токен. pvzIds задает ПВЗ, с которыми может работать dummy-сотрудник.
+	DummyLogin(ctx context.Context, role string, pvzIds []uuid.UUID) (string, error)
 	Login(ctx context.Context, email string, password string) (string, error)
-	Register(ctx context.Context, user *domain.User) error
+	Register(ctx context.Context, user *domain.User, actor *domain.User) error
+	VerifyEmail(ctx context.Context, token string) error
+	// ResendVerification повторно отправляет письмо с подтверждением, если адрес еще не подтвержден.
+	ResendVerification(ctx context.Context, email string) error
+	EnsureBootstrapAdmin(ctx context.Context, email, password string) error
+}
+
+// LockoutPolicy задает временную блокировку аккаунта после серии неудачных входов.
+// MaxAttempts <= 0 отключает блокировку.
+type LockoutPolicy struct {
+	MaxAttempts int
+	Duration    time.Duration
+}
+
+// AuthOptions — настраиваемые правила входа и регистрации.
+type AuthOptions struct {
+	Lockout                  LockoutPolicy
+	PasswordPolicy           auth.PasswordPolicy
+	RequireEmailVerification bool
+	VerificationTTL          time.Duration
+	// VerificationURL — адрес, к которому в письме добавляется ?token=...
+	VerificationURL string
 }
 
 type authUseCase struct {
-	repo   repository.UserRepository
-	tokens token.Generator
-	hasher auth.PasswordHasher
+	repo       repository.UserRepository
+	tokens     token.Generator
+	hasher     auth.PasswordHasher
+	mailer     mail.Sender
+	authorizer authz.Authorizer
+	opts       AuthOptions
 }
 
-func NewAuthUseCase(repo repository.UserRepository, tokens token.Generator, hasher auth.PasswordHasher) AuthUseCase {
+func NewAuthUseCase(repo repository.UserRepository, tokens token.Generator, hasher auth.PasswordHasher, mailer mail.Sender, authorizer authz.Authorizer, opts AuthOptions) AuthUseCase {
 	return &authUseCase{
-		repo:   repo,
-		tokens: tokens,
-		hasher: hasher,
+		repo:       repo,
+		tokens:     tokens,
+		hasher:     hasher,
+		mailer:     mailer,
+		authorizer: authorizer,
+		opts:       opts,
 	}
 }
 
-func (uc *authUseCase) DummyLogin(ctx context.Context, role string) (string, error) {
-	if role != constants.UserRoleEmployee && role != constants.UserRoleModerator {
+func (uc *authUseCase) DummyLogin(ctx context.Context, role string, pvzIds []uuid.UUID) (string, error) {
+	if !uc.authorizer.RoleExists(role) {
 		return "", appErr.ErrInvalidRole
 	}
 
 	userId := uuid.New()
-	token, err := uc.tokens.CreateToken(userId, role)
+	token, err := uc.tokens.CreateDummyToken(userId, role, pvzIds)
 	if err != nil {
 		return "", appErr.ErrCreatingToken
 	}
@@ -48,6 +84,7 @@ func (uc *authUseCase) DummyLogin(ctx context.Context, role string) (string, err
 }
 
 func (uc *authUseCase) Login(ctx context.Context, email string, password string) (string, error) {
+	email = normalizeEmail(email)
 	if email == "" || password == "" {
 		return "", appErr.ErrMissingAuthFields
 	}
@@ -57,32 +94,79 @@ func (uc *authUseCase) Login(ctx context.Context, email string, password string)
 		return "", appErr.ErrGettingUser
 	}
 
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return "", &appErr.RetryAfterError{
+			Err:        appErr.ErrAccountLocked,
+			RetryAfter: time.Until(*user.LockedUntil),
+		}
+	}
+
 	err = uc.hasher.CheckPassword(password, user.Password)
 	if err != nil {
+		if uc.opts.Lockout.MaxAttempts > 0 {
+			err = uc.repo.RegisterFailedLogin(ctx, user.Id, uc.opts.Lockout.MaxAttempts, uc.opts.Lockout.Duration)
+			if err != nil {
+				return "", appErr.ErrInternal
+			}
+		}
+
 		return "", appErr.ErrInvalidAuthFields
 	}
 
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		err = uc.repo.ResetFailedLogins(ctx, user.Id)
+		if err != nil {
+			return "", appErr.ErrInternal
+		}
+	}
+
+	if user.Disabled {
+		return "", appErr.ErrUserDisabled
+	}
+
+	if uc.opts.RequireEmailVerification && !user.EmailVerified {
+		return "", appErr.ErrEmailNotVerified
+	}
+
 	token, err := uc.tokens.CreateToken(user.Id, user.Role)
 	if err != nil {
 		return "", appErr.ErrCreatingToken
 	}
 
+	err = uc.repo.UpdateLastLogin(ctx, user.Id, time.Now())
+	if err != nil {
+		return "", appErr.ErrInternal
+	}
+
 	return token, nil
 }
 
-func (uc *authUseCase) Register(ctx context.Context, user *domain.User) error {
+// Register создает пользователя. Сотрудник может зарегистрироваться сам,
+// остальные роли назначает только пользователь с правом user:manage (actor).
+func (uc *authUseCase) Register(ctx context.Context, user *domain.User, actor *domain.User) error {
 	if user == nil {
 		return appErr.ErrUserRequired
 	}
 
+	user.Email = normalizeEmail(user.Email)
 	if user.Email == "" || user.Password == "" || user.Role == "" {
 		return appErr.ErrMissingAuthFields
 	}
 
-	if user.Role != constants.UserRoleEmployee && user.Role != constants.UserRoleModerator {
+	if !uc.authorizer.RoleExists(user.Role) {
 		return appErr.ErrInvalidRole
 	}
 
+	if user.Role != constants.UserRoleEmployee {
+		if err := uc.authorizer.Authorize(actor, authz.PermUserManage); err != nil {
+			return appErr.ErrPermissionDenied
+		}
+	}
+
+	if err := uc.opts.PasswordPolicy.Validate(user.Password); err != nil {
+		return err
+	}
+
 	existingUser, err := uc.repo.GetUserByEmail(ctx, user.Email)
 	if err == nil && existingUser != nil {
 		return appErr.ErrUserEmailExists
@@ -92,10 +176,130 @@ func (uc *authUseCase) Register(ctx context.Context, user *domain.User) error {
 		return appErr.ErrCheckingExistingUser
 	}
 
+	user.EmailVerified = !uc.opts.RequireEmailVerification
+
 	err = uc.repo.CreateUser(ctx, user)
 	if err != nil {
 		return appErr.ErrCreatingUser
 	}
 
+	if !user.EmailVerified {
+		return uc.sendVerification(ctx, user)
+	}
+
+	return nil
+}
+
+func (uc *authUseCase) VerifyEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return appErr.ErrInvalidVerification
+	}
+
+	err := uc.repo.VerifyEmail(ctx, hashToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErr.ErrInvalidVerification
+		}
+		return appErr.ErrInternal
+	}
+
+	return nil
+}
+
+// ResendVerification отправляет новую ссылку подтверждения. Для неизвестного, уже подтвержденного
+// или заблокированного адреса письмо не отправляется, но ошибка не возвращается, чтобы по ответу
+// нельзя было узнать, зарегистрирован ли email.
+func (uc *authUseCase) ResendVerification(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	if email == "" {
+		return appErr.ErrMissingAuthFields
+	}
+
+	if !uc.opts.RequireEmailVerification {
+		return nil
+	}
+
+	user, err := uc.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return appErr.ErrGettingUser
+	}
+
+	if user.EmailVerified || user.Disabled {
+		return nil
+	}
+
+	return uc.sendVerification(ctx, user)
+}
+
+// EnsureBootstrapAdmin создает первого модератора, если его еще нет.
+// Пустой email или пароль отключает создание.
+func (uc *authUseCase) EnsureBootstrapAdmin(ctx context.Context, email, password string) error {
+	email = normalizeEmail(email)
+	if email == "" || password == "" {
+		return nil
+	}
+
+	_, err := uc.repo.GetUserByEmail(ctx, email)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return appErr.ErrCheckingExistingUser
+	}
+
+	if err = uc.opts.PasswordPolicy.Validate(password); err != nil {
+		return err
+	}
+
+	admin := &domain.User{
+		Email:         email,
+		Password:      password,
+		Role:          constants.UserRoleModerator,
+		EmailVerified: true,
+	}
+
+	err = uc.repo.CreateUser(ctx, admin)
+	if err != nil {
+		return appErr.ErrCreatingUser
+	}
+
 	return nil
 }
+
+func (uc *authUseCase) sendVerification(ctx context.Context, user *domain.User) error {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return appErr.ErrCreatingToken
+	}
+	verificationToken := hex.EncodeToString(raw)
+
+	err := uc.repo.CreateVerificationToken(ctx, user.Id, hashToken(verificationToken), time.Now().Add(uc.opts.VerificationTTL))
+	if err != nil {
+		return appErr.ErrCreatingToken
+	}
+
+	err = uc.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Подтверждение email",
+		Body:    fmt.Sprintf("Для подтверждения адреса перейдите по ссылке: %s?token=%s", uc.opts.VerificationURL, verificationToken),
+	})
+	if err != nil {
+		return appErr.ErrSendingEmail
+	}
+
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// hashToken — в БД храним только хеш токена, чтобы утечка таблицы не давала подтвердить чужой email.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
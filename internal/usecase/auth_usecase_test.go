@@ -3,11 +3,16 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aliskhannn/pvz-service/internal/auth"
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/mail"
 	"github.com/aliskhannn/pvz-service/internal/usecase/mocks"
 	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
 	"github.com/google/uuid"
@@ -20,7 +25,7 @@ func TestAuthUseCase_DummyLogin(t *testing.T) {
 	userRepo := &repository_mocks.MockUserRepository{}
 	tokens := &mocks.MockJWTGenerator{}
 	hasher := &mocks.MockPasswordHasher{}
-	authUC := NewAuthUseCase(userRepo, tokens, hasher)
+	authUC := NewAuthUseCase(userRepo, tokens, hasher, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{})
 
 	tests := []struct {
 		name      string
@@ -62,12 +67,12 @@ func TestAuthUseCase_DummyLogin(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.token != "" || tt.tokenErr != nil {
-				tokens.On("CreateToken", mock.Anything, tt.role).
+				tokens.On("CreateDummyToken", mock.Anything, tt.role, mock.Anything).
 					Return(tt.token, tt.tokenErr).
 					Once()
 			}
 
-			result, err := authUC.DummyLogin(context.Background(), tt.role)
+			result, err := authUC.DummyLogin(context.Background(), tt.role, nil)
 
 			if tt.expectErr != nil {
 				assert.ErrorIs(t, err, tt.expectErr)
@@ -86,7 +91,7 @@ func TestAuthUseCase_Login(t *testing.T) {
 	userRepo := &repository_mocks.MockUserRepository{}
 	tokens := &mocks.MockJWTGenerator{}
 	hasher := &mocks.MockPasswordHasher{}
-	authUC := NewAuthUseCase(userRepo, tokens, hasher)
+	authUC := NewAuthUseCase(userRepo, tokens, hasher, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{})
 
 	userID := uuid.New()
 	user := &domain.User{
@@ -162,7 +167,7 @@ func TestAuthUseCase_Login(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.email != "" {
+			if tt.email != "" && tt.password != "" {
 				userRepo.On("GetUserByEmail", mock.Anything, tt.email).
 					Return(tt.user, tt.userErr).
 					Once()
@@ -180,6 +185,12 @@ func TestAuthUseCase_Login(t *testing.T) {
 					Once()
 			}
 
+			if tt.expectErr == nil {
+				userRepo.On("UpdateLastLogin", mock.Anything, tt.user.Id, mock.AnythingOfType("time.Time")).
+					Return(nil).
+					Once()
+			}
+
 			result, err := authUC.Login(context.Background(), tt.email, tt.password)
 
 			if tt.expectErr != nil {
@@ -197,11 +208,94 @@ func TestAuthUseCase_Login(t *testing.T) {
 	}
 }
 
+func TestAuthUseCase_LoginLockout(t *testing.T) {
+	lockout := LockoutPolicy{MaxAttempts: 3, Duration: 15 * time.Minute}
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	expiredLock := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		user        *domain.User
+		hashErr     error
+		expectFail  bool
+		expectReset bool
+		expectErr   error
+	}{
+		{
+			name:      "Locked account",
+			user:      &domain.User{Id: uuid.New(), Password: "hashed", Role: constants.UserRoleEmployee, LockedUntil: &lockedUntil},
+			expectErr: appErr.ErrAccountLocked,
+		},
+		{
+			name:       "Wrong password registers failure",
+			user:       &domain.User{Id: uuid.New(), Password: "hashed", Role: constants.UserRoleEmployee},
+			hashErr:    errors.New("invalid password"),
+			expectFail: true,
+			expectErr:  appErr.ErrInvalidAuthFields,
+		},
+		{
+			name:        "Successful login resets failures",
+			user:        &domain.User{Id: uuid.New(), Password: "hashed", Role: constants.UserRoleEmployee, FailedLoginAttempts: 2},
+			expectReset: true,
+		},
+		{
+			name:        "Expired lock allows login",
+			user:        &domain.User{Id: uuid.New(), Password: "hashed", Role: constants.UserRoleEmployee, LockedUntil: &expiredLock},
+			expectReset: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &repository_mocks.MockUserRepository{}
+			tokens := &mocks.MockJWTGenerator{}
+			hasher := &mocks.MockPasswordHasher{}
+			authUC := NewAuthUseCase(userRepo, tokens, hasher, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{Lockout: lockout})
+
+			userRepo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(tt.user, nil).Once()
+
+			if tt.expectErr != appErr.ErrAccountLocked {
+				hasher.On("CheckPassword", "password", tt.user.Password).Return(tt.hashErr).Once()
+			}
+			if tt.expectFail {
+				userRepo.On("RegisterFailedLogin", mock.Anything, tt.user.Id, lockout.MaxAttempts, lockout.Duration).Return(nil).Once()
+			}
+			if tt.expectReset {
+				userRepo.On("ResetFailedLogins", mock.Anything, tt.user.Id).Return(nil).Once()
+			}
+			if tt.expectErr == nil {
+				tokens.On("CreateToken", tt.user.Id, tt.user.Role).Return("valid-token", nil).Once()
+				userRepo.On("UpdateLastLogin", mock.Anything, tt.user.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+			}
+
+			result, err := authUC.Login(context.Background(), "test@example.com", "password")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Empty(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "valid-token", result)
+			}
+
+			if tt.expectErr == appErr.ErrAccountLocked {
+				var retryErr *appErr.RetryAfterError
+				assert.ErrorAs(t, err, &retryErr)
+				assert.InDelta(t, 10*time.Minute, retryErr.RetryAfter, float64(time.Second))
+			}
+
+			userRepo.AssertExpectations(t)
+			hasher.AssertExpectations(t)
+			tokens.AssertExpectations(t)
+		})
+	}
+}
+
 func TestAuthUseCase_Register(t *testing.T) {
 	userRepo := &repository_mocks.MockUserRepository{}
 	tokens := &mocks.MockJWTGenerator{}
 	hasher := &mocks.MockPasswordHasher{}
-	authUC := NewAuthUseCase(userRepo, tokens, hasher)
+	authUC := NewAuthUseCase(userRepo, tokens, hasher, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{})
 
 	userID := uuid.New()
 	validUser := &domain.User{
@@ -290,7 +384,177 @@ func TestAuthUseCase_Register(t *testing.T) {
 					Once()
 			}
 
-			err := authUC.Register(context.Background(), tt.user)
+			err := authUC.Register(context.Background(), tt.user, nil)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthUseCase_RegisterPolicy(t *testing.T) {
+	opts := AuthOptions{
+		PasswordPolicy:           auth.PasswordPolicy{MinLength: 8, RequireDigit: true},
+		RequireEmailVerification: true,
+		VerificationTTL:          time.Hour,
+		VerificationURL:          "http://localhost/verify-email",
+	}
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+
+	tests := []struct {
+		name          string
+		user          *domain.User
+		actor         *domain.User
+		expectCreate  bool
+		expectedEmail string
+		expectErr     error
+	}{
+		{
+			name:          "Employee self-registration sends verification",
+			user:          &domain.User{Email: "  Test@Example.COM ", Password: "password1", Role: constants.UserRoleEmployee},
+			expectCreate:  true,
+			expectedEmail: "test@example.com",
+		},
+		{
+			name:      "Moderator self-registration is forbidden",
+			user:      &domain.User{Email: "mod@example.com", Password: "password1", Role: constants.UserRoleModerator},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Employee cannot create moderator",
+			user:      &domain.User{Email: "mod@example.com", Password: "password1", Role: constants.UserRoleModerator},
+			actor:     &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:          "Moderator creates moderator",
+			user:          &domain.User{Email: "mod@example.com", Password: "password1", Role: constants.UserRoleModerator},
+			actor:         moderator,
+			expectCreate:  true,
+			expectedEmail: "mod@example.com",
+		},
+		{
+			name:          "Moderator creates auditor",
+			user:          &domain.User{Email: "audit@example.com", Password: "password1", Role: constants.UserRoleAuditor},
+			actor:         moderator,
+			expectCreate:  true,
+			expectedEmail: "audit@example.com",
+		},
+		{
+			name:      "Regional manager cannot create auditor",
+			user:      &domain.User{Email: "audit@example.com", Password: "password1", Role: constants.UserRoleAuditor},
+			actor:     &domain.User{Id: uuid.New(), Role: constants.UserRoleRegionalManager},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Unknown role",
+			user:      &domain.User{Email: "test@example.com", Password: "password1", Role: "admin"},
+			actor:     moderator,
+			expectErr: appErr.ErrInvalidRole,
+		},
+		{
+			name:      "Weak password",
+			user:      &domain.User{Email: "test@example.com", Password: "password", Role: constants.UserRoleEmployee},
+			expectErr: appErr.ErrWeakPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &repository_mocks.MockUserRepository{}
+			mailer := &mocks.MockMailSender{}
+			authUC := NewAuthUseCase(userRepo, &mocks.MockJWTGenerator{}, &mocks.MockPasswordHasher{}, mailer, authz.New(authz.DefaultRoles()), opts)
+
+			if tt.expectCreate {
+				userId := uuid.New()
+				userRepo.On("GetUserByEmail", mock.Anything, tt.expectedEmail).Return(nil, pgx.ErrNoRows).Once()
+				userRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+					return u.Email == tt.expectedEmail && !u.EmailVerified
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*domain.User).Id = userId
+				}).Return(nil).Once()
+				userRepo.On("CreateVerificationToken", mock.Anything, userId, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+					Return(nil).Once()
+				mailer.On("Send", mock.Anything, mock.MatchedBy(func(msg mail.Message) bool {
+					return msg.To == tt.expectedEmail && strings.Contains(msg.Body, opts.VerificationURL+"?token=")
+				})).Return(nil).Once()
+			}
+
+			err := authUC.Register(context.Background(), tt.user, tt.actor)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			userRepo.AssertExpectations(t)
+			mailer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthUseCase_LoginRequiresVerifiedEmail(t *testing.T) {
+	userRepo := &repository_mocks.MockUserRepository{}
+	hasher := &mocks.MockPasswordHasher{}
+	authUC := NewAuthUseCase(userRepo, &mocks.MockJWTGenerator{}, hasher, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{RequireEmailVerification: true})
+
+	user := &domain.User{Id: uuid.New(), Email: "test@example.com", Password: "hashed", Role: constants.UserRoleEmployee}
+	userRepo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(user, nil).Once()
+	hasher.On("CheckPassword", "password", "hashed").Return(nil).Once()
+
+	result, err := authUC.Login(context.Background(), "Test@Example.com", "password")
+
+	assert.ErrorIs(t, err, appErr.ErrEmailNotVerified)
+	assert.Empty(t, result)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAuthUseCase_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:  "Valid token",
+			token: "token",
+		},
+		{
+			name:      "Empty token",
+			token:     "",
+			expectErr: appErr.ErrInvalidVerification,
+		},
+		{
+			name:      "Unknown or expired token",
+			token:     "token",
+			repoErr:   pgx.ErrNoRows,
+			expectErr: appErr.ErrInvalidVerification,
+		},
+		{
+			name:      "Repository error",
+			token:     "token",
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &repository_mocks.MockUserRepository{}
+			authUC := NewAuthUseCase(userRepo, &mocks.MockJWTGenerator{}, &mocks.MockPasswordHasher{}, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{})
+
+			if tt.token != "" {
+				userRepo.On("VerifyEmail", mock.Anything, hashToken(tt.token), mock.AnythingOfType("time.Time")).Return(tt.repoErr).Once()
+			}
+
+			err := authUC.VerifyEmail(context.Background(), tt.token)
 
 			if tt.expectErr != nil {
 				assert.ErrorIs(t, err, tt.expectErr)
@@ -302,3 +566,133 @@ func TestAuthUseCase_Register(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthUseCase_ResendVerification(t *testing.T) {
+	opts := AuthOptions{
+		RequireEmailVerification: true,
+		VerificationTTL:          time.Hour,
+		VerificationURL:          "http://localhost/verify-email",
+	}
+	pending := &domain.User{Id: uuid.New(), Email: "test@example.com", Role: constants.UserRoleEmployee}
+
+	tests := []struct {
+		name       string
+		email      string
+		opts       AuthOptions
+		user       *domain.User
+		repoErr    error
+		mockRepo   bool
+		expectSend bool
+		expectErr  error
+	}{
+		{
+			name:       "Unverified user gets new link",
+			email:      " Test@Example.com",
+			opts:       opts,
+			user:       pending,
+			mockRepo:   true,
+			expectSend: true,
+		},
+		{
+			name:     "Unknown email is not revealed",
+			email:    "test@example.com",
+			opts:     opts,
+			repoErr:  pgx.ErrNoRows,
+			mockRepo: true,
+		},
+		{
+			name:     "Already verified",
+			email:    "test@example.com",
+			opts:     opts,
+			user:     &domain.User{Id: pending.Id, Email: pending.Email, EmailVerified: true},
+			mockRepo: true,
+		},
+		{
+			name:     "Disabled user",
+			email:    "test@example.com",
+			opts:     opts,
+			user:     &domain.User{Id: pending.Id, Email: pending.Email, Disabled: true},
+			mockRepo: true,
+		},
+		{
+			name:  "Verification is off",
+			email: "test@example.com",
+		},
+		{
+			name:      "Empty email",
+			opts:      opts,
+			expectErr: appErr.ErrMissingAuthFields,
+		},
+		{
+			name:      "Repository error",
+			email:     "test@example.com",
+			opts:      opts,
+			repoErr:   errors.New("db error"),
+			mockRepo:  true,
+			expectErr: appErr.ErrGettingUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &repository_mocks.MockUserRepository{}
+			mailer := &mocks.MockMailSender{}
+			authUC := NewAuthUseCase(userRepo, &mocks.MockJWTGenerator{}, &mocks.MockPasswordHasher{}, mailer, authz.New(authz.DefaultRoles()), tt.opts)
+
+			if tt.mockRepo {
+				userRepo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(tt.user, tt.repoErr).Once()
+			}
+			if tt.expectSend {
+				userRepo.On("CreateVerificationToken", mock.Anything, pending.Id, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+					Return(nil).Once()
+				mailer.On("Send", mock.Anything, mock.MatchedBy(func(msg mail.Message) bool {
+					return msg.To == pending.Email && strings.Contains(msg.Body, opts.VerificationURL+"?token=")
+				})).Return(nil).Once()
+			}
+
+			err := authUC.ResendVerification(context.Background(), tt.email)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			userRepo.AssertExpectations(t)
+			mailer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthUseCase_EnsureBootstrapAdmin(t *testing.T) {
+	t.Run("Creates verified moderator", func(t *testing.T) {
+		userRepo := &repository_mocks.MockUserRepository{}
+		authUC := NewAuthUseCase(userRepo, &mocks.MockJWTGenerator{}, &mocks.MockPasswordHasher{}, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{})
+
+		userRepo.On("GetUserByEmail", mock.Anything, "admin@example.com").Return(nil, pgx.ErrNoRows).Once()
+		userRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == "admin@example.com" && u.Role == constants.UserRoleModerator && u.EmailVerified
+		})).Return(nil).Once()
+
+		assert.NoError(t, authUC.EnsureBootstrapAdmin(context.Background(), "Admin@Example.com", "secret"))
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("Existing admin is left untouched", func(t *testing.T) {
+		userRepo := &repository_mocks.MockUserRepository{}
+		authUC := NewAuthUseCase(userRepo, &mocks.MockJWTGenerator{}, &mocks.MockPasswordHasher{}, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{})
+
+		userRepo.On("GetUserByEmail", mock.Anything, "admin@example.com").Return(&domain.User{}, nil).Once()
+
+		assert.NoError(t, authUC.EnsureBootstrapAdmin(context.Background(), "admin@example.com", "secret"))
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("Disabled without credentials", func(t *testing.T) {
+		userRepo := &repository_mocks.MockUserRepository{}
+		authUC := NewAuthUseCase(userRepo, &mocks.MockJWTGenerator{}, &mocks.MockPasswordHasher{}, &mocks.MockMailSender{}, authz.New(authz.DefaultRoles()), AuthOptions{})
+
+		assert.NoError(t, authUC.EnsureBootstrapAdmin(context.Background(), "", ""))
+		userRepo.AssertExpectations(t)
+	})
+}
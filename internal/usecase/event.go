@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+// EventBroker раздает события живой ленте внутри процесса; реализуется broker.Broker.
+type EventBroker interface {
+	Publish(events ...*domain.Event)
+	Subscribe(filter func(event *domain.Event) bool) *broker.Subscription
+}
+
+// addEvent записывает событие в outbox и возвращает его. Вызывается внутри TxManager.WithinTx с ctx
+// транзакции, чтобы событие сохранилось только вместе с изменением состояния. В живую ленту
+// событие отправляется после фиксации транзакции.
+func addEvent(ctx context.Context, outbox repository.OutboxRepository, eventType string, pvzId, aggregateId uuid.UUID, payload any) (*domain.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &domain.Event{
+		Id:          uuid.New(),
+		Type:        eventType,
+		PVZId:       pvzId,
+		AggregateId: aggregateId,
+		OccurredAt:  time.Now(),
+		Payload:     data,
+	}
+
+	if err = outbox.Add(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
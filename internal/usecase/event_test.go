@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newOutboxMock принимает любые события.
+func newOutboxMock() *repository_mocks.MockOutboxRepository {
+	outbox := &repository_mocks.MockOutboxRepository{}
+	outbox.On("Add", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return outbox
+}
+
+// newStorageCellMock возвращает репозиторий ячеек ПВЗ, в котором ячейки не заведены.
+func newStorageCellMock() *repository_mocks.MockStorageCellRepository {
+	cells := &repository_mocks.MockStorageCellRepository{}
+	cells.On("SuggestCell", mock.Anything, mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows).Maybe()
+	return cells
+}
+
+func TestReceptionUseCase_CreateReception_Event(t *testing.T) {
+	pvzId := uuid.New()
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+
+	repo := &repository_mocks.MockReceptionRepository{}
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	outbox := &repository_mocks.MockOutboxRepository{}
+	events := broker.New(1)
+	sub := events.Subscribe(nil)
+	receptionUC := NewReceptionUseCase(repo, &repository_mocks.MockManifestRepository{}, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, outbox, events)
+
+	assignments.On("IsAssigned", mock.Anything, employee.Id, pvzId).Return(true, nil)
+	repo.On("HasOpenReception", mock.Anything, pvzId, constants.ReceptionDirectionInbound).Return(false, nil)
+	repo.On("CreateReception", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Reception).Id = uuid.New()
+	}).Return(nil)
+	outbox.On("Add", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == constants.EventReceptionOpened && event.PVZId == pvzId
+	})).Return(errors.New("db error")).Once()
+
+	// Ошибка записи события откатывает создание приемки, в живую ленту ничего не уходит.
+	_, err := receptionUC.CreateReception(context.Background(), pvzId, employee)
+
+	assert.ErrorIs(t, err, appErr.ErrCreatingReception)
+	assert.Len(t, sub.Events(), 0)
+	outbox.AssertExpectations(t)
+}
+
+func TestProductUseCase_AddProductToReception_Event(t *testing.T) {
+	pvzId := uuid.New()
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	product := &domain.Product{Id: uuid.New(), PVZId: pvzId, Type: constants.ProductTypeShoes, PickupCode: "123456"}
+
+	productRepo := &repository_mocks.MockProductRepository{}
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	outbox := &repository_mocks.MockOutboxRepository{}
+	events := broker.New(1)
+	sub := events.Subscribe(nil)
+	productUC := NewProductUseCase(productRepo, newStorageCellMock(), assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, outbox, events, constants.CapacityPolicyReject)
+
+	assignments.On("IsAssigned", mock.Anything, employee.Id, pvzId).Return(true, nil)
+	productRepo.On("LockPVZCapacity", mock.Anything, pvzId).Return(&domain.PVZ{Id: pvzId}, nil)
+	productRepo.On("AddProductToReception", mock.Anything, pvzId, constants.ProductTypeShoes, "", mock.Anything, (*float64)(nil), employee.Id).Return(product, nil)
+
+	var event *domain.Event
+	outbox.On("Add", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(*domain.Event)
+	}).Return(nil).Once()
+
+	_, err := productUC.AddProductToReception(context.Background(), pvzId, constants.ProductTypeShoes, "", "", nil, employee)
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.EventProductAdded, event.Type)
+	assert.Equal(t, product.Id, event.AggregateId)
+
+	var payload map[string]any
+	assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, product.Id.String(), payload["product_id"])
+	assert.NotContains(t, payload, "pickup_code")
+
+	// После фиксации то же событие уходит в живую ленту.
+	assert.Equal(t, event, <-sub.Events())
+}
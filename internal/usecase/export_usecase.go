@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"time"
+)
+
+// ExportUseCase готовит выгрузки для бухгалтерии.
+type ExportUseCase interface {
+	// ExportReceptions передает в fn товары из приемок, открытых в даты [from, to] включительно.
+	// Пустые даты — последние 30 дней, пустой city — все города. Ошибки валидации и доступа
+	// возвращаются до первого вызова fn; ошибка fn возвращается как есть.
+	ExportReceptions(ctx context.Context, from, to time.Time, city string, user *domain.User, fn func(*domain.ReceptionExportRow) error) error
+}
+
+type exportUseCase struct {
+	repo       repository.ExportRepository
+	authorizer authz.Authorizer
+	now        func() time.Time
+}
+
+func NewExportUseCase(repo repository.ExportRepository, authorizer authz.Authorizer) ExportUseCase {
+	return &exportUseCase{
+		repo:       repo,
+		authorizer: authorizer,
+		now:        time.Now,
+	}
+}
+
+func (uc *exportUseCase) ExportReceptions(ctx context.Context, from, to time.Time, city string, user *domain.User, fn func(*domain.ReceptionExportRow) error) error {
+	if err := uc.authorizer.Authorize(user, authz.PermReportRead); err != nil {
+		return err
+	}
+
+	if city != "" && !isPVZCity(city) {
+		return appErr.ErrInvalidCity
+	}
+
+	from, to = reportPeriod(from, to, uc.now())
+	if !from.Before(to) {
+		return appErr.ErrInvalidExportPeriod
+	}
+
+	// Ошибки fn (например, клиент закрыл соединение) отличаем от ошибок чтения из БД.
+	var fnErr error
+	err := uc.repo.StreamReceptionProducts(ctx, domain.ReceptionExportFilter{From: from, To: to, City: city}, func(row *domain.ReceptionExportRow) error {
+		fnErr = fn(row)
+		return fnErr
+	})
+	if err != nil {
+		if fnErr != nil && errors.Is(err, fnErr) {
+			return fnErr
+		}
+		return appErr.ErrExportingReceptions
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportUseCase_ExportReceptions(t *testing.T) {
+	now := time.Date(2025, 5, 5, 15, 30, 0, 0, time.UTC)
+	auditor := &domain.User{Id: uuid.New(), Role: constants.UserRoleAuditor}
+	rows := []*domain.ReceptionExportRow{
+		{ReceptionId: uuid.New(), ProductId: uuid.New()},
+		{ReceptionId: uuid.New(), ProductId: uuid.New()},
+	}
+	writeErr := errors.New("client gone")
+
+	tests := []struct {
+		name         string
+		from         time.Time
+		to           time.Time
+		city         string
+		user         *domain.User
+		fnErr        error
+		repoErr      error
+		expectFilter domain.ReceptionExportFilter
+		expectRows   int
+		expectErr    error
+	}{
+		{
+			name: "Month for one city",
+			from: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+			city: constants.PVZCityKazan,
+			user: auditor,
+			expectFilter: domain.ReceptionExportFilter{
+				From: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+				City: constants.PVZCityKazan,
+			},
+			expectRows: 2,
+		},
+		{
+			name: "Default period",
+			user: auditor,
+			expectFilter: domain.ReceptionExportFilter{
+				From: time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2025, 5, 6, 0, 0, 0, 0, time.UTC),
+			},
+			expectRows: 2,
+		},
+		{
+			name:      "Employee is not allowed",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Invalid city",
+			city:      "Сочи",
+			user:      auditor,
+			expectErr: appErr.ErrInvalidCity,
+		},
+		{
+			name:      "From after to",
+			from:      time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			to:        time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			user:      auditor,
+			expectErr: appErr.ErrInvalidExportPeriod,
+		},
+		{
+			name:       "Writer error is returned as is",
+			user:       auditor,
+			fnErr:      writeErr,
+			expectRows: 1,
+			expectErr:  writeErr,
+		},
+		{
+			name:       "Repository error",
+			user:       auditor,
+			repoErr:    errors.New("db error"),
+			expectRows: 2,
+			expectErr:  appErr.ErrExportingReceptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockExportRepository{Rows: rows}
+			repo.On("StreamReceptionProducts", mock.Anything, mock.Anything).Return(tt.repoErr)
+
+			uc := NewExportUseCase(repo, authz.New(authz.DefaultRoles())).(*exportUseCase)
+			uc.now = func() time.Time { return now }
+
+			var got int
+			err := uc.ExportReceptions(context.Background(), tt.from, tt.to, tt.city, tt.user, func(row *domain.ReceptionExportRow) error {
+				got++
+				return tt.fnErr
+			})
+
+			assert.Equal(t, tt.expectRows, got)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			repo.AssertCalled(t, "StreamReceptionProducts", mock.Anything, tt.expectFilter)
+		})
+	}
+}
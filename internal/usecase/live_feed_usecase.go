@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// LiveFeedUseCase подписывает на события приемок и товаров в реальном времени.
+// Подписку нужно закрыть, когда клиент отключился.
+type LiveFeedUseCase interface {
+	SubscribePVZ(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*broker.Subscription, error)
+	// SubscribeCity подписывает на события всех ПВЗ города, существующих на момент подписки.
+	SubscribeCity(ctx context.Context, city string, user *domain.User) (*broker.Subscription, error)
+}
+
+type liveFeedUseCase struct {
+	broker      EventBroker
+	pvzs        repository.PVZRepository
+	assignments repository.AssignmentRepository
+	authorizer  authz.Authorizer
+}
+
+func NewLiveFeedUseCase(
+	broker EventBroker,
+	pvzs repository.PVZRepository,
+	assignments repository.AssignmentRepository,
+	authorizer authz.Authorizer,
+) LiveFeedUseCase {
+	return &liveFeedUseCase{
+		broker:      broker,
+		pvzs:        pvzs,
+		assignments: assignments,
+		authorizer:  authorizer,
+	}
+}
+
+func (uc *liveFeedUseCase) SubscribePVZ(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*broker.Subscription, error) {
+	if pvzId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	if err := checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, pvzId); err != nil {
+		return nil, err
+	}
+
+	return uc.broker.Subscribe(func(event *domain.Event) bool {
+		return event.PVZId == pvzId
+	}), nil
+}
+
+func (uc *liveFeedUseCase) SubscribeCity(ctx context.Context, city string, user *domain.User) (*broker.Subscription, error) {
+	// Лента по городу — для супервизоров, сотруднику ПВЗ она не положена.
+	if err := uc.authorizer.Authorize(user, authz.PermReportRead); err != nil {
+		return nil, err
+	}
+
+	if !isPVZCity(city) {
+		return nil, appErr.ErrInvalidCity
+	}
+
+	pvzIds, err := uc.pvzs.GetPVZIdsByCity(ctx, city)
+	if err != nil {
+		return nil, appErr.ErrGettingPVZs
+	}
+
+	inCity := make(map[uuid.UUID]struct{}, len(pvzIds))
+	for _, pvzId := range pvzIds {
+		inCity[pvzId] = struct{}{}
+	}
+
+	return uc.broker.Subscribe(func(event *domain.Event) bool {
+		_, ok := inCity[event.PVZId]
+		return ok
+	}), nil
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLiveFeedUseCase_SubscribePVZ(t *testing.T) {
+	pvzId := uuid.New()
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		assigned  bool
+		expectErr error
+	}{
+		{name: "Assigned employee", user: employee, assigned: true},
+		{name: "Not assigned employee", user: employee, expectErr: appErr.ErrPVZAccessDenied},
+		{name: "Auditor sees any pvz", user: &domain.User{Id: uuid.New(), Role: constants.UserRoleAuditor}},
+		{name: "Nil user", expectErr: appErr.ErrUserRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := broker.New(4)
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			assignments.On("IsAssigned", mock.Anything, employee.Id, pvzId).Return(tt.assigned, nil)
+			liveFeedUC := NewLiveFeedUseCase(events, &repository_mocks.MockPVZRepository{}, assignments, authz.New(authz.DefaultRoles()))
+
+			sub, err := liveFeedUC.SubscribePVZ(context.Background(), pvzId, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Equal(t, 0, events.Subscribers())
+				return
+			}
+
+			assert.NoError(t, err)
+			defer sub.Close()
+
+			own := &domain.Event{Id: uuid.New(), PVZId: pvzId}
+			events.Publish(&domain.Event{Id: uuid.New(), PVZId: uuid.New()}, own)
+			assert.Equal(t, own, <-sub.Events())
+			assert.Len(t, sub.Events(), 0)
+		})
+	}
+}
+
+func TestLiveFeedUseCase_SubscribeCity(t *testing.T) {
+	moscowPVZ := uuid.New()
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		city      string
+		expectErr error
+	}{
+		{name: "Moderator", user: moderator, city: constants.PVZCityMoscow},
+		{name: "Employee is not allowed", user: &domain.User{Role: constants.UserRoleEmployee}, city: constants.PVZCityMoscow, expectErr: appErr.ErrPermissionDenied},
+		{name: "Unknown city", user: moderator, city: "Тверь", expectErr: appErr.ErrInvalidCity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := broker.New(4)
+			pvzs := &repository_mocks.MockPVZRepository{}
+			pvzs.On("GetPVZIdsByCity", mock.Anything, constants.PVZCityMoscow).Return([]uuid.UUID{moscowPVZ}, nil)
+			liveFeedUC := NewLiveFeedUseCase(events, pvzs, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()))
+
+			sub, err := liveFeedUC.SubscribeCity(context.Background(), tt.city, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			defer sub.Close()
+
+			own := &domain.Event{Id: uuid.New(), PVZId: moscowPVZ}
+			events.Publish(&domain.Event{Id: uuid.New(), PVZId: uuid.New()}, own)
+			assert.Equal(t, own, <-sub.Events())
+			assert.Len(t, sub.Events(), 0)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"sort"
+)
+
+type ManifestUseCase interface {
+	// CreateManifest регистрирует ожидаемую поставку; она привяжется к следующей открытой приемке ПВЗ.
+	CreateManifest(ctx context.Context, manifest *domain.Manifest, user *domain.User) error
+	GetManifest(ctx context.Context, manifestId uuid.UUID, user *domain.User) (*domain.Manifest, error)
+	GetDiscrepancyReport(ctx context.Context, receptionId uuid.UUID, user *domain.User) (*domain.DiscrepancyReport, error)
+}
+
+type manifestUseCase struct {
+	repo        repository.ManifestRepository
+	receptions  repository.ReceptionRepository
+	assignments repository.AssignmentRepository
+	authorizer  authz.Authorizer
+}
+
+func NewManifestUseCase(
+	repo repository.ManifestRepository,
+	receptions repository.ReceptionRepository,
+	assignments repository.AssignmentRepository,
+	authorizer authz.Authorizer,
+) ManifestUseCase {
+	return &manifestUseCase{
+		repo:        repo,
+		receptions:  receptions,
+		assignments: assignments,
+		authorizer:  authorizer,
+	}
+}
+
+func (uc *manifestUseCase) CreateManifest(ctx context.Context, manifest *domain.Manifest, user *domain.User) error {
+	if err := uc.authorizer.Authorize(user, authz.PermManifestCreate); err != nil {
+		return err
+	}
+
+	if manifest == nil {
+		return appErr.ErrManifestRequired
+	}
+
+	if manifest.PVZId == uuid.Nil {
+		return appErr.ErrPVZIdRequired
+	}
+
+	if len(manifest.Items) == 0 {
+		return appErr.ErrManifestItemsRequired
+	}
+
+	seen := make(map[string]struct{}, len(manifest.Items))
+	for _, item := range manifest.Items {
+		if item.Type != constants.ProductTypeElectronics && item.Type != constants.ProductsTypeCloth && item.Type != constants.ProductTypeShoes {
+			return appErr.ErrInvalidProductType
+		}
+
+		if _, ok := seen[item.Barcode]; ok {
+			return appErr.ErrDuplicateManifestBarcode
+		}
+		seen[item.Barcode] = struct{}{}
+	}
+
+	err := uc.repo.CreateManifest(ctx, manifest, actorId(user))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErr.ErrPVZNotFound
+		}
+		return appErr.ErrCreatingManifest
+	}
+
+	return nil
+}
+
+func (uc *manifestUseCase) GetManifest(ctx context.Context, manifestId uuid.UUID, user *domain.User) (*domain.Manifest, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermManifestRead); err != nil {
+		return nil, err
+	}
+
+	manifest, err := uc.repo.GetManifestById(ctx, manifestId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrManifestNotFound
+		}
+		return nil, appErr.ErrGettingManifest
+	}
+
+	if err = checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, manifest.PVZId); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func (uc *manifestUseCase) GetDiscrepancyReport(ctx context.Context, receptionId uuid.UUID, user *domain.User) (*domain.DiscrepancyReport, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermManifestRead); err != nil {
+		return nil, err
+	}
+
+	reception, err := uc.receptions.GetReceptionById(ctx, receptionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrReceptionNotFound
+		}
+		return nil, appErr.ErrGettingReceptions
+	}
+
+	if err = checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, reception.PVZId); err != nil {
+		return nil, err
+	}
+
+	if reception.Status != constants.ReceptionStatusClose {
+		return nil, appErr.ErrReceptionNotClosed
+	}
+
+	report, err := uc.repo.GetDiscrepancyReport(ctx, receptionId)
+	if err == nil {
+		return report, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErr.ErrGettingDiscrepancyReport
+	}
+
+	// Отчет мог не сохраниться при закрытии приемки — строим его заново.
+	return generateDiscrepancyReport(ctx, uc.repo, uc.receptions, receptionId)
+}
+
+// generateDiscrepancyReport сверяет товары приемки с привязанным к ней манифестом и сохраняет отчет.
+func generateDiscrepancyReport(
+	ctx context.Context,
+	manifests repository.ManifestRepository,
+	receptions repository.ReceptionRepository,
+	receptionId uuid.UUID,
+) (*domain.DiscrepancyReport, error) {
+	manifest, err := manifests.GetManifestByReceptionId(ctx, receptionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrDiscrepancyReportNotFound
+		}
+		return nil, appErr.ErrGettingManifest
+	}
+
+	barcodes, err := receptions.GetReceptionBarcodes(ctx, receptionId)
+	if err != nil {
+		return nil, appErr.ErrGettingProducts
+	}
+
+	report := buildDiscrepancyReport(manifest, barcodes)
+	report.ReceptionId = receptionId
+
+	if err = manifests.SaveDiscrepancyReport(ctx, report); err != nil {
+		return nil, appErr.ErrGettingDiscrepancyReport
+	}
+
+	return report, nil
+}
+
+// buildDiscrepancyReport находит недостающие, лишние и повторно принятые штрихкоды.
+// Пустая строка в barcodes означает товар, принятый без штрихкода.
+func buildDiscrepancyReport(manifest *domain.Manifest, barcodes []string) *domain.DiscrepancyReport {
+	report := &domain.DiscrepancyReport{
+		ManifestId: manifest.Id,
+		Expected:   len(manifest.Items),
+		Received:   len(barcodes),
+		Missing:    []string{},
+		Unexpected: []string{},
+		Duplicates: []string{},
+	}
+
+	received := make(map[string]int, len(barcodes))
+	for _, barcode := range barcodes {
+		if barcode == "" {
+			report.WithoutBarcode++
+			continue
+		}
+		received[barcode]++
+	}
+
+	expected := make(map[string]struct{}, len(manifest.Items))
+	for _, item := range manifest.Items {
+		expected[item.Barcode] = struct{}{}
+		if received[item.Barcode] == 0 {
+			report.Missing = append(report.Missing, item.Barcode)
+		}
+	}
+
+	for barcode, count := range received {
+		if _, ok := expected[barcode]; !ok {
+			report.Unexpected = append(report.Unexpected, barcode)
+		}
+		if count > 1 {
+			report.Duplicates = append(report.Duplicates, barcode)
+		}
+	}
+
+	sort.Strings(report.Missing)
+	sort.Strings(report.Unexpected)
+	sort.Strings(report.Duplicates)
+
+	return report
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBuildDiscrepancyReport(t *testing.T) {
+	manifest := &domain.Manifest{
+		Id: uuid.New(),
+		Items: []*domain.ManifestItem{
+			{Barcode: "A", Type: constants.ProductTypeShoes},
+			{Barcode: "B", Type: constants.ProductTypeShoes},
+			{Barcode: "C", Type: constants.ProductsTypeCloth},
+		},
+	}
+
+	report := buildDiscrepancyReport(manifest, []string{"A", "A", "C", "X", "X", ""})
+
+	assert.Equal(t, manifest.Id, report.ManifestId)
+	assert.Equal(t, 3, report.Expected)
+	assert.Equal(t, 6, report.Received)
+	assert.Equal(t, []string{"B"}, report.Missing)
+	assert.Equal(t, []string{"X"}, report.Unexpected)
+	assert.Equal(t, []string{"A", "X"}, report.Duplicates)
+	assert.Equal(t, 1, report.WithoutBarcode)
+}
+
+func TestManifestUseCase_CreateManifest(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	pvzId := uuid.New()
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		items     []*domain.ManifestItem
+		mockRepo  bool
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:     "Valid manifest",
+			user:     moderator,
+			items:    []*domain.ManifestItem{{Barcode: "A", Type: constants.ProductTypeShoes}},
+			mockRepo: true,
+		},
+		{
+			name:      "Employee",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			items:     []*domain.ManifestItem{{Barcode: "A", Type: constants.ProductTypeShoes}},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "No items",
+			user:      moderator,
+			expectErr: appErr.ErrManifestItemsRequired,
+		},
+		{
+			name: "Duplicate barcode",
+			user: moderator,
+			items: []*domain.ManifestItem{
+				{Barcode: "A", Type: constants.ProductTypeShoes},
+				{Barcode: "A", Type: constants.ProductsTypeCloth},
+			},
+			expectErr: appErr.ErrDuplicateManifestBarcode,
+		},
+		{
+			name:      "Invalid product type",
+			user:      moderator,
+			items:     []*domain.ManifestItem{{Barcode: "A", Type: "мебель"}},
+			expectErr: appErr.ErrInvalidProductType,
+		},
+		{
+			name:      "PVZ not found",
+			user:      moderator,
+			items:     []*domain.ManifestItem{{Barcode: "A", Type: constants.ProductTypeShoes}},
+			mockRepo:  true,
+			repoErr:   pgx.ErrNoRows,
+			expectErr: appErr.ErrPVZNotFound,
+		},
+		{
+			name:      "Repository error",
+			user:      moderator,
+			items:     []*domain.ManifestItem{{Barcode: "A", Type: constants.ProductTypeShoes}},
+			mockRepo:  true,
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrCreatingManifest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockManifestRepository{}
+			manifestUC := NewManifestUseCase(repo, &repository_mocks.MockReceptionRepository{}, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()))
+
+			manifest := &domain.Manifest{PVZId: pvzId, Items: tt.items}
+			if tt.mockRepo {
+				repo.On("CreateManifest", mock.Anything, manifest, moderator.Id).Return(tt.repoErr).Once()
+			}
+
+			err := manifestUC.CreateManifest(context.Background(), manifest, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestManifestUseCase_GetDiscrepancyReport(t *testing.T) {
+	auditor := &domain.User{Id: uuid.New(), Role: constants.UserRoleAuditor}
+	receptionId := uuid.New()
+	closed := &domain.Reception{Id: receptionId, PVZId: uuid.New(), Status: constants.ReceptionStatusClose}
+	manifest := &domain.Manifest{Id: uuid.New(), Items: []*domain.ManifestItem{{Barcode: "A"}}}
+
+	t.Run("Stored report", func(t *testing.T) {
+		repo := &repository_mocks.MockManifestRepository{}
+		receptions := &repository_mocks.MockReceptionRepository{}
+		manifestUC := NewManifestUseCase(repo, receptions, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()))
+
+		stored := &domain.DiscrepancyReport{ReceptionId: receptionId}
+		receptions.On("GetReceptionById", mock.Anything, receptionId).Return(closed, nil).Once()
+		repo.On("GetDiscrepancyReport", mock.Anything, receptionId).Return(stored, nil).Once()
+
+		report, err := manifestUC.GetDiscrepancyReport(context.Background(), receptionId, auditor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, stored, report)
+		repo.AssertExpectations(t)
+		receptions.AssertExpectations(t)
+	})
+
+	t.Run("Missing report is regenerated", func(t *testing.T) {
+		repo := &repository_mocks.MockManifestRepository{}
+		receptions := &repository_mocks.MockReceptionRepository{}
+		manifestUC := NewManifestUseCase(repo, receptions, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()))
+
+		receptions.On("GetReceptionById", mock.Anything, receptionId).Return(closed, nil).Once()
+		repo.On("GetDiscrepancyReport", mock.Anything, receptionId).Return(nil, pgx.ErrNoRows).Once()
+		repo.On("GetManifestByReceptionId", mock.Anything, receptionId).Return(manifest, nil).Once()
+		receptions.On("GetReceptionBarcodes", mock.Anything, receptionId).Return([]string{"B"}, nil).Once()
+		repo.On("SaveDiscrepancyReport", mock.Anything, mock.Anything).Return(nil).Once()
+
+		report, err := manifestUC.GetDiscrepancyReport(context.Background(), receptionId, auditor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, receptionId, report.ReceptionId)
+		assert.Equal(t, []string{"A"}, report.Missing)
+		assert.Equal(t, []string{"B"}, report.Unexpected)
+		repo.AssertExpectations(t)
+		receptions.AssertExpectations(t)
+	})
+
+	t.Run("Open reception", func(t *testing.T) {
+		receptions := &repository_mocks.MockReceptionRepository{}
+		manifestUC := NewManifestUseCase(&repository_mocks.MockManifestRepository{}, receptions, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()))
+
+		open := &domain.Reception{Id: receptionId, PVZId: closed.PVZId, Status: constants.ReceptionStatusInProgress}
+		receptions.On("GetReceptionById", mock.Anything, receptionId).Return(open, nil).Once()
+
+		_, err := manifestUC.GetDiscrepancyReport(context.Background(), receptionId, auditor)
+
+		assert.ErrorIs(t, err, appErr.ErrReceptionNotClosed)
+	})
+
+	t.Run("Reception without manifest", func(t *testing.T) {
+		repo := &repository_mocks.MockManifestRepository{}
+		receptions := &repository_mocks.MockReceptionRepository{}
+		manifestUC := NewManifestUseCase(repo, receptions, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()))
+
+		receptions.On("GetReceptionById", mock.Anything, receptionId).Return(closed, nil).Once()
+		repo.On("GetDiscrepancyReport", mock.Anything, receptionId).Return(nil, pgx.ErrNoRows).Once()
+		repo.On("GetManifestByReceptionId", mock.Anything, receptionId).Return(nil, pgx.ErrNoRows).Once()
+
+		_, err := manifestUC.GetDiscrepancyReport(context.Background(), receptionId, auditor)
+
+		assert.ErrorIs(t, err, appErr.ErrDiscrepancyReportNotFound)
+	})
+}
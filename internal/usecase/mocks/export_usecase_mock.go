@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExportUseCase передает в fn строки Rows, затем возвращает ошибку из On.
+type MockExportUseCase struct {
+	mock.Mock
+	Rows []*domain.ReceptionExportRow
+}
+
+func (m *MockExportUseCase) ExportReceptions(ctx context.Context, from, to time.Time, city string, user *domain.User, fn func(*domain.ReceptionExportRow) error) error {
+	args := m.Called(ctx, from, to, city, user)
+	for _, row := range m.Rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return args.Error(0)
+}
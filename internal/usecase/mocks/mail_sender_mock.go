@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/mail"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMailSender struct {
+	mock.Mock
+}
+
+func (m *MockMailSender) Send(ctx context.Context, msg mail.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
@@ -12,12 +12,35 @@ type MockProductUseCase struct {
 	mock.Mock
 }
 
-func (m *MockProductUseCase) AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType string, user *domain.User) error {
-	args := m.Called(ctx, pvzId, productType, user)
-	return args.Error(0)
+func (m *MockProductUseCase) AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType, barcode, cellCode string, volume *float64, user *domain.User) (*domain.Product, error) {
+	args := m.Called(ctx, pvzId, productType, barcode, cellCode, volume, user)
+	product, _ := args.Get(0).(*domain.Product)
+	return product, args.Error(1)
 }
 
 func (m *MockProductUseCase) DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) error {
 	args := m.Called(ctx, pvzId, user)
 	return args.Error(0)
 }
+
+func (m *MockProductUseCase) IssueProduct(ctx context.Context, productId uuid.UUID, pickupCode string, user *domain.User) error {
+	args := m.Called(ctx, productId, pickupCode, user)
+	return args.Error(0)
+}
+
+func (m *MockProductUseCase) ReturnProductToSender(ctx context.Context, productId uuid.UUID, user *domain.User) error {
+	args := m.Called(ctx, productId, user)
+	return args.Error(0)
+}
+
+func (m *MockProductUseCase) GetInventory(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*domain.Inventory, error) {
+	args := m.Called(ctx, pvzId, user)
+	inventory, _ := args.Get(0).(*domain.Inventory)
+	return inventory, args.Error(1)
+}
+
+func (m *MockProductUseCase) GetPickupInfo(ctx context.Context, productId uuid.UUID, pickupCode string) (*domain.PickupInfo, error) {
+	args := m.Called(ctx, productId, pickupCode)
+	info, _ := args.Get(0).(*domain.PickupInfo)
+	return info, args.Error(1)
+}
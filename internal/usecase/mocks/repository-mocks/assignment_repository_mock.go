@@ -0,0 +1,32 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAssignmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentRepository) AssignPVZ(ctx context.Context, userId, pvzId uuid.UUID) error {
+	args := m.Called(ctx, userId, pvzId)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) UnassignPVZ(ctx context.Context, userId, pvzId uuid.UUID) error {
+	args := m.Called(ctx, userId, pvzId)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) GetUserPVZIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, userId)
+	pvzIds, _ := args.Get(0).([]uuid.UUID)
+	return pvzIds, args.Error(1)
+}
+
+func (m *MockAssignmentRepository) IsAssigned(ctx context.Context, userId, pvzId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userId, pvzId)
+	return args.Bool(0), args.Error(1)
+}
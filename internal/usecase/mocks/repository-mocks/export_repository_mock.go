@@ -0,0 +1,23 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExportRepository передает в fn строки Rows, затем возвращает ошибку из On.
+type MockExportRepository struct {
+	mock.Mock
+	Rows []*domain.ReceptionExportRow
+}
+
+func (m *MockExportRepository) StreamReceptionProducts(ctx context.Context, filter domain.ReceptionExportFilter, fn func(*domain.ReceptionExportRow) error) error {
+	args := m.Called(ctx, filter)
+	for _, row := range m.Rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return args.Error(0)
+}
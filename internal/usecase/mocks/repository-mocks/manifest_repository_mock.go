@@ -0,0 +1,40 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockManifestRepository struct {
+	mock.Mock
+}
+
+func (m *MockManifestRepository) CreateManifest(ctx context.Context, manifest *domain.Manifest, createdBy uuid.UUID) error {
+	args := m.Called(ctx, manifest, createdBy)
+	return args.Error(0)
+}
+
+func (m *MockManifestRepository) GetManifestById(ctx context.Context, manifestId uuid.UUID) (*domain.Manifest, error) {
+	args := m.Called(ctx, manifestId)
+	manifest, _ := args.Get(0).(*domain.Manifest)
+	return manifest, args.Error(1)
+}
+
+func (m *MockManifestRepository) GetManifestByReceptionId(ctx context.Context, receptionId uuid.UUID) (*domain.Manifest, error) {
+	args := m.Called(ctx, receptionId)
+	manifest, _ := args.Get(0).(*domain.Manifest)
+	return manifest, args.Error(1)
+}
+
+func (m *MockManifestRepository) SaveDiscrepancyReport(ctx context.Context, report *domain.DiscrepancyReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockManifestRepository) GetDiscrepancyReport(ctx context.Context, receptionId uuid.UUID) (*domain.DiscrepancyReport, error) {
+	args := m.Called(ctx, receptionId)
+	report, _ := args.Get(0).(*domain.DiscrepancyReport)
+	return report, args.Error(1)
+}
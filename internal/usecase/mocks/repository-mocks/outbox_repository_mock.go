@@ -0,0 +1,33 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Add(ctx context.Context, event *domain.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) GetPending(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEvent, error) {
+	args := m.Called(ctx, now, limit)
+	events, _ := args.Get(0).([]*domain.OutboxEvent)
+	return events, args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkPublished(ctx context.Context, seq int64) error {
+	args := m.Called(ctx, seq)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, seq int64, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, seq, reason, nextAttemptAt)
+	return args.Error(0)
+}
@@ -2,6 +2,7 @@ package repository_mocks
 
 import (
 	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -10,12 +11,61 @@ type MockProductRepository struct {
 	mock.Mock
 }
 
-func (m *MockProductRepository) AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType string) error {
-	args := m.Called(ctx, pvzId, productType)
-	return args.Error(0)
+func (m *MockProductRepository) AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType, barcode, pickupCode string, volume *float64, userId uuid.UUID) (*domain.Product, error) {
+	args := m.Called(ctx, pvzId, productType, barcode, pickupCode, volume, userId)
+	product, _ := args.Get(0).(*domain.Product)
+	return product, args.Error(1)
 }
 
-func (m *MockProductRepository) DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID) error {
+func (m *MockProductRepository) LockPVZCapacity(ctx context.Context, pvzId uuid.UUID) (*domain.PVZ, error) {
 	args := m.Called(ctx, pvzId)
+	pvz, _ := args.Get(0).(*domain.PVZ)
+	return pvz, args.Error(1)
+}
+
+func (m *MockProductRepository) DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID, userId uuid.UUID) (*domain.Product, error) {
+	args := m.Called(ctx, pvzId, userId)
+	product, _ := args.Get(0).(*domain.Product)
+	return product, args.Error(1)
+}
+
+func (m *MockProductRepository) GetProductById(ctx context.Context, productId uuid.UUID) (*domain.Product, error) {
+	args := m.Called(ctx, productId)
+	product, _ := args.Get(0).(*domain.Product)
+	return product, args.Error(1)
+}
+
+func (m *MockProductRepository) ChangeProductStatus(ctx context.Context, productId uuid.UUID, from, to string, userId uuid.UUID) error {
+	args := m.Called(ctx, productId, from, to, userId)
 	return args.Error(0)
 }
+
+func (m *MockProductRepository) GetInventory(ctx context.Context, pvzId uuid.UUID) ([]*domain.Product, error) {
+	args := m.Called(ctx, pvzId)
+	products, _ := args.Get(0).([]*domain.Product)
+	return products, args.Error(1)
+}
+
+func (m *MockProductRepository) GetPickupInfo(ctx context.Context, productId uuid.UUID) (*domain.PickupInfo, error) {
+	args := m.Called(ctx, productId)
+	info, _ := args.Get(0).(*domain.PickupInfo)
+	return info, args.Error(1)
+}
+
+func (m *MockProductRepository) FlagOverdueProducts(ctx context.Context, policy domain.StoragePolicy) (int64, error) {
+	args := m.Called(ctx, policy)
+	flagged, _ := args.Get(0).(int64)
+	return flagged, args.Error(1)
+}
+
+func (m *MockProductRepository) GetOverdueProducts(ctx context.Context, pvzId uuid.UUID) ([]*domain.OverdueProduct, error) {
+	args := m.Called(ctx, pvzId)
+	products, _ := args.Get(0).([]*domain.OverdueProduct)
+	return products, args.Error(1)
+}
+
+func (m *MockProductRepository) CountOverdueProducts(ctx context.Context) ([]*domain.OverdueCount, error) {
+	args := m.Called(ctx)
+	counts, _ := args.Get(0).([]*domain.OverdueCount)
+	return counts, args.Error(1)
+}
@@ -17,11 +17,29 @@ func (m *MockPVZRepository) CreatePVZ(ctx context.Context, pvz *domain.PVZ) erro
 	return args.Error(0)
 }
 
+func (m *MockPVZRepository) FindPVZsByAddress(ctx context.Context, pvzs []*domain.PVZ) ([]*domain.PVZ, error) {
+	args := m.Called(ctx, pvzs)
+	existing, _ := args.Get(0).([]*domain.PVZ)
+	return existing, args.Error(1)
+}
+
 func (m *MockPVZRepository) GetAllPVZs(ctx context.Context, offset, limit int) ([]*domain.PVZ, error) {
 	args := m.Called(ctx, offset, limit)
 	return args.Get(0).([]*domain.PVZ), args.Error(1)
 }
 
+func (m *MockPVZRepository) UpdatePVZCapacity(ctx context.Context, pvzId uuid.UUID, items *int, volume *float64) (*domain.PVZ, error) {
+	args := m.Called(ctx, pvzId, items, volume)
+	pvz, _ := args.Get(0).(*domain.PVZ)
+	return pvz, args.Error(1)
+}
+
+func (m *MockPVZRepository) GetPVZIdsByCity(ctx context.Context, city string) ([]uuid.UUID, error) {
+	args := m.Called(ctx, city)
+	pvzIds, _ := args.Get(0).([]uuid.UUID)
+	return pvzIds, args.Error(1)
+}
+
 func (m *MockPVZRepository) GetReceptionsByPVZId(ctx context.Context, pvzId uuid.UUID, startDate, endDate time.Time) ([]*domain.Reception, error) {
 	args := m.Called(ctx, pvzId, startDate, endDate)
 	return args.Get(0).([]*domain.Reception), args.Error(1)
@@ -0,0 +1,35 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReceptionCorrectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockReceptionCorrectionRepository) ReopenReception(ctx context.Context, receptionId, userId uuid.UUID, reason string) error {
+	args := m.Called(ctx, receptionId, userId, reason)
+	return args.Error(0)
+}
+
+func (m *MockReceptionCorrectionRepository) AddProduct(ctx context.Context, receptionId uuid.UUID, productType, barcode, pickupCode string, userId uuid.UUID, reason string) (*domain.Product, error) {
+	args := m.Called(ctx, receptionId, productType, barcode, pickupCode, userId, reason)
+	product, _ := args.Get(0).(*domain.Product)
+	return product, args.Error(1)
+}
+
+func (m *MockReceptionCorrectionRepository) RemoveProduct(ctx context.Context, receptionId, productId, userId uuid.UUID, reason string) (*domain.Product, error) {
+	args := m.Called(ctx, receptionId, productId, userId, reason)
+	product, _ := args.Get(0).(*domain.Product)
+	return product, args.Error(1)
+}
+
+func (m *MockReceptionCorrectionRepository) GetAudit(ctx context.Context, receptionId uuid.UUID) ([]*domain.ReceptionAuditEntry, error) {
+	args := m.Called(ctx, receptionId)
+	entries, _ := args.Get(0).([]*domain.ReceptionAuditEntry)
+	return entries, args.Error(1)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type MockReceptionRepository struct {
@@ -16,12 +17,48 @@ func (m *MockReceptionRepository) CreateReception(ctx context.Context, reception
 	return args.Error(0)
 }
 
-func (m *MockReceptionRepository) CloseLastReception(ctx context.Context, pvzId uuid.UUID) error {
+func (m *MockReceptionRepository) CloseLastReception(ctx context.Context, pvzId uuid.UUID) (uuid.UUID, error) {
 	args := m.Called(ctx, pvzId)
+	receptionId, _ := args.Get(0).(uuid.UUID)
+	return receptionId, args.Error(1)
+}
+
+func (m *MockReceptionRepository) HasOpenReception(ctx context.Context, pvzId uuid.UUID, direction string) (bool, error) {
+	args := m.Called(ctx, pvzId, direction)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReceptionRepository) AddProductToReturn(ctx context.Context, pvzId, productId, userId uuid.UUID) error {
+	args := m.Called(ctx, pvzId, productId, userId)
 	return args.Error(0)
 }
 
-func (m *MockReceptionRepository) HasOpenReception(ctx context.Context, pvzId uuid.UUID) (bool, error) {
+func (m *MockReceptionRepository) CloseLastReturn(ctx context.Context, pvzId uuid.UUID) (uuid.UUID, error) {
 	args := m.Called(ctx, pvzId)
-	return args.Bool(0), args.Error(1)
+	receptionId, _ := args.Get(0).(uuid.UUID)
+	return receptionId, args.Error(1)
+}
+
+func (m *MockReceptionRepository) GetReceptionById(ctx context.Context, receptionId uuid.UUID) (*domain.Reception, error) {
+	args := m.Called(ctx, receptionId)
+	reception, _ := args.Get(0).(*domain.Reception)
+	return reception, args.Error(1)
+}
+
+func (m *MockReceptionRepository) GetReceptionBarcodes(ctx context.Context, receptionId uuid.UUID) ([]string, error) {
+	args := m.Called(ctx, receptionId)
+	barcodes, _ := args.Get(0).([]string)
+	return barcodes, args.Error(1)
+}
+
+func (m *MockReceptionRepository) CloseStaleReceptions(ctx context.Context, idleFor time.Duration, reason string) ([]*domain.Reception, error) {
+	args := m.Called(ctx, idleFor, reason)
+	receptions, _ := args.Get(0).([]*domain.Reception)
+	return receptions, args.Error(1)
+}
+
+func (m *MockReceptionRepository) GetOpenReceptions(ctx context.Context) ([]*domain.OpenReception, error) {
+	args := m.Called(ctx)
+	receptions, _ := args.Get(0).([]*domain.OpenReception)
+	return receptions, args.Error(1)
 }
@@ -0,0 +1,23 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepository) GetIntake(ctx context.Context, filter domain.IntakeFilter) ([]*domain.IntakeStat, error) {
+	args := m.Called(ctx, filter)
+	stats, _ := args.Get(0).([]*domain.IntakeStat)
+	return stats, args.Error(1)
+}
+
+func (m *MockStatsRepository) GetProductivity(ctx context.Context, filter domain.ProductivityFilter) ([]*domain.EmployeeProductivity, error) {
+	args := m.Called(ctx, filter)
+	report, _ := args.Get(0).([]*domain.EmployeeProductivity)
+	return report, args.Error(1)
+}
@@ -0,0 +1,51 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStorageCellRepository struct {
+	mock.Mock
+}
+
+func (m *MockStorageCellRepository) CreateCells(ctx context.Context, cells []*domain.StorageCell) error {
+	args := m.Called(ctx, cells)
+	return args.Error(0)
+}
+
+func (m *MockStorageCellRepository) GetCells(ctx context.Context, pvzId uuid.UUID) ([]*domain.StorageCell, error) {
+	args := m.Called(ctx, pvzId)
+	cells, _ := args.Get(0).([]*domain.StorageCell)
+	return cells, args.Error(1)
+}
+
+func (m *MockStorageCellRepository) DeleteCell(ctx context.Context, pvzId, cellId uuid.UUID) error {
+	args := m.Called(ctx, pvzId, cellId)
+	return args.Error(0)
+}
+
+func (m *MockStorageCellRepository) GetCellByCode(ctx context.Context, pvzId uuid.UUID, code string) (*domain.StorageCell, error) {
+	args := m.Called(ctx, pvzId, code)
+	cell, _ := args.Get(0).(*domain.StorageCell)
+	return cell, args.Error(1)
+}
+
+func (m *MockStorageCellRepository) SuggestCell(ctx context.Context, pvzId uuid.UUID, productType string) (*domain.StorageCell, error) {
+	args := m.Called(ctx, pvzId, productType)
+	cell, _ := args.Get(0).(*domain.StorageCell)
+	return cell, args.Error(1)
+}
+
+func (m *MockStorageCellRepository) AssignProduct(ctx context.Context, productId, cellId uuid.UUID) error {
+	args := m.Called(ctx, productId, cellId)
+	return args.Error(0)
+}
+
+func (m *MockStorageCellRepository) LocateProducts(ctx context.Context, pvzId, productId uuid.UUID, barcode string) ([]*domain.ProductLocation, error) {
+	args := m.Called(ctx, pvzId, productId, barcode)
+	locations, _ := args.Get(0).([]*domain.ProductLocation)
+	return locations, args.Error(1)
+}
@@ -0,0 +1,56 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransferRepository) CreateTransfer(ctx context.Context, transfer *domain.Transfer, productIds []uuid.UUID, userId uuid.UUID) error {
+	args := m.Called(ctx, transfer, productIds, userId)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) LockDispatchedTransfer(ctx context.Context, transferId uuid.UUID) (*domain.Transfer, error) {
+	args := m.Called(ctx, transferId)
+	transfer, _ := args.Get(0).(*domain.Transfer)
+	return transfer, args.Error(1)
+}
+
+func (m *MockTransferRepository) GetPickupCodeConflicts(ctx context.Context, transferId uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, transferId)
+	productIds, _ := args.Get(0).([]uuid.UUID)
+	return productIds, args.Error(1)
+}
+
+func (m *MockTransferRepository) ReissuePickupCode(ctx context.Context, transferId, productId uuid.UUID, pickupCode string) error {
+	args := m.Called(ctx, transferId, productId, pickupCode)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) GetTransferVolume(ctx context.Context, transferId uuid.UUID) (float64, error) {
+	args := m.Called(ctx, transferId)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockTransferRepository) ReceiveTransfer(ctx context.Context, transfer *domain.Transfer, userId uuid.UUID) error {
+	args := m.Called(ctx, transfer, userId)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) GetTransferById(ctx context.Context, transferId uuid.UUID) (*domain.Transfer, error) {
+	args := m.Called(ctx, transferId)
+	transfer, _ := args.Get(0).(*domain.Transfer)
+	return transfer, args.Error(1)
+}
+
+func (m *MockTransferRepository) GetTransfersByPVZId(ctx context.Context, pvzId uuid.UUID, offset, limit int) ([]*domain.Transfer, error) {
+	args := m.Called(ctx, pvzId, offset, limit)
+	transfers, _ := args.Get(0).([]*domain.Transfer)
+	return transfers, args.Error(1)
+}
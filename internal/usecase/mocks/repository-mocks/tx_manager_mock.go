@@ -0,0 +1,12 @@
+package repository_mocks
+
+import (
+	"context"
+)
+
+// MockTxManager выполняет fn без транзакции.
+type MockTxManager struct{}
+
+func (m *MockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
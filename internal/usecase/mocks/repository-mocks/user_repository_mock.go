@@ -3,7 +3,9 @@ package repository_mocks
 import (
 	"context"
 	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type MockUserRepository struct {
@@ -17,5 +19,58 @@ func (m *MockUserRepository) CreateUser(ctx context.Context, user *domain.User)
 
 func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	args := m.Called(ctx, email)
-	return args.Get(0).(*domain.User), args.Error(1)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) RegisterFailedLogin(ctx context.Context, userId uuid.UUID, maxAttempts int, lockDuration time.Duration) error {
+	args := m.Called(ctx, userId, maxAttempts, lockDuration)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CreateVerificationToken(ctx context.Context, userId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, userId, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) VerifyEmail(ctx context.Context, tokenHash string, now time.Time) error {
+	args := m.Called(ctx, tokenHash, now)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ResetFailedLogins(ctx context.Context, userId uuid.UUID) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetUserById(ctx context.Context, userId uuid.UUID) (*domain.User, error) {
+	args := m.Called(ctx, userId)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) ListUsers(ctx context.Context, search string, offset, limit int) ([]*domain.User, int, error) {
+	args := m.Called(ctx, search, offset, limit)
+	users, _ := args.Get(0).([]*domain.User)
+	return users, args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) UpdateUserRole(ctx context.Context, userId uuid.UUID, role string) error {
+	args := m.Called(ctx, userId, role)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetUserDisabled(ctx context.Context, userId uuid.UUID, disabled bool) error {
+	args := m.Called(ctx, userId, disabled)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, userId uuid.UUID, password string) error {
+	args := m.Called(ctx, userId, password)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, userId uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, userId, at)
+	return args.Error(0)
 }
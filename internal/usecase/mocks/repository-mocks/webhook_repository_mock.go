@@ -0,0 +1,50 @@
+package repository_mocks
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	subscriptions, _ := args.Get(0).([]*domain.WebhookSubscription)
+	return subscriptions, args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, subscriptionId uuid.UUID) error {
+	args := m.Called(ctx, subscriptionId)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnqueueDeliveries(ctx context.Context, event *domain.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDueDeliveries(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, limit)
+	deliveries, _ := args.Get(0).([]*domain.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func (m *MockWebhookRepository) SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeliveries(ctx context.Context, subscriptionId uuid.UUID, status string, offset, limit int) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionId, status, offset, limit)
+	deliveries, _ := args.Get(0).([]*domain.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
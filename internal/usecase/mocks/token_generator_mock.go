@@ -15,6 +15,11 @@ func (m *MockJWTGenerator) CreateToken(userId uuid.UUID, role string) (string, e
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockJWTGenerator) CreateDummyToken(userId uuid.UUID, role string, pvzIds []uuid.UUID) (string, error) {
+	args := m.Called(userId, role, pvzIds)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockJWTGenerator) ValidateToken(tokenString string) (*jwt.Claims, error) {
 	args := m.Called(tokenString)
 	return args.Get(0).(*jwt.Claims), args.Error(1)
@@ -2,68 +2,381 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
 	"github.com/aliskhannn/pvz-service/internal/repository"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"math/big"
 )
 
 type ProductUseCase interface {
-	AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType string, user *domain.User) error
+	// AddProductToReception добавляет товар в открытую приемку и выдает ему код получения.
+	// Если товар не помещается в ПВЗ, он отклоняется или принимается с пометкой в зависимости от политики.
+	// Товар кладется в отсканированную ячейку cellCode, а без нее — в самую свободную подходящую ячейку ПВЗ.
+	AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType, barcode, cellCode string, volume *float64, user *domain.User) (*domain.Product, error)
 	DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) error
+	// IssueProduct выдает товар клиенту по коду получения.
+	IssueProduct(ctx context.Context, productId uuid.UUID, pickupCode string, user *domain.User) error
+	ReturnProductToSender(ctx context.Context, productId uuid.UUID, user *domain.User) error
+	GetInventory(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*domain.Inventory, error)
+	// GetPickupInfo показывает клиенту, где лежит его заказ. Авторизация — только код получения.
+	GetPickupInfo(ctx context.Context, productId uuid.UUID, pickupCode string) (*domain.PickupInfo, error)
 }
 
+// pickupCodeAttempts — сколько раз генерируется новый код, если он уже занят в ПВЗ.
+const pickupCodeAttempts = 5
+
 type productUseCase struct {
-	repo repository.ProductRepository
+	repo           repository.ProductRepository
+	cells          repository.StorageCellRepository
+	assignments    repository.AssignmentRepository
+	authorizer     authz.Authorizer
+	tx             repository.TxManager
+	outbox         repository.OutboxRepository
+	broker         EventBroker
+	capacityPolicy string
 }
 
-func NewProductUseCase(repo repository.ProductRepository) ProductUseCase {
-	return &productUseCase{repo: repo}
+// NewProductUseCase создает сценарии работы с товарами. capacityPolicy — constants.CapacityPolicyReject
+// или constants.CapacityPolicyWarn; пустое значение означает reject.
+func NewProductUseCase(
+	repo repository.ProductRepository,
+	cells repository.StorageCellRepository,
+	assignments repository.AssignmentRepository,
+	authorizer authz.Authorizer,
+	tx repository.TxManager,
+	outbox repository.OutboxRepository,
+	broker EventBroker,
+	capacityPolicy string,
+) ProductUseCase {
+	return &productUseCase{
+		repo:           repo,
+		cells:          cells,
+		assignments:    assignments,
+		authorizer:     authorizer,
+		tx:             tx,
+		outbox:         outbox,
+		broker:         broker,
+		capacityPolicy: capacityPolicy,
+	}
 }
 
-func (uc *productUseCase) AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType string, user *domain.User) error {
-	if user == nil {
-		return appErr.ErrUserRequired
+func (uc *productUseCase) AddProductToReception(ctx context.Context, pvzId uuid.UUID, productType, barcode, cellCode string, volume *float64, user *domain.User) (*domain.Product, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermProductAdd); err != nil {
+		return nil, err
 	}
 
-	if user.Role != constants.UserRoleEmployee {
-		return appErr.ErrOnlyEmployeeAllowed
+	if pvzId == uuid.Nil || productType == "" {
+		return nil, appErr.ErrPVZIdAndProductTypeRequired
 	}
 
-	if pvzId == uuid.Nil || productType == "" {
-		return appErr.ErrPVZIdAndProductTypeRequired
+	if !isProductType(productType) {
+		return nil, appErr.ErrInvalidProductType
+	}
+
+	if volume != nil && *volume <= 0 {
+		return nil, appErr.ErrInvalidVolume
+	}
+
+	if err := checkPVZAccess(ctx, uc.assignments, user, pvzId); err != nil {
+		return nil, err
 	}
 
-	if productType != constants.ProductTypeElectronics && productType != constants.ProductsTypeCloth && productType != constants.ProductTypeShoes {
-		return appErr.ErrInvalidProductType
+	var productVolume float64
+	if volume != nil {
+		productVolume = *volume
 	}
 
-	err := uc.repo.AddProductToReception(ctx, pvzId, productType)
+	for i := 0; i < pickupCodeAttempts; i++ {
+		pickupCode, err := generatePickupCode()
+		if err != nil {
+			return nil, appErr.ErrInternal
+		}
+
+		var product *domain.Product
+		var event *domain.Event
+		err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+			overCapacity, err := checkCapacity(ctx, uc.repo, uc.capacityPolicy, pvzId, 1, productVolume)
+			if err != nil {
+				return err
+			}
+
+			// Ячейка выбирается под блокировкой ПВЗ из checkCapacity, поэтому параллельные
+			// сканирования не переполняют одну и ту же ячейку.
+			cell, err := pickCell(ctx, uc.cells, pvzId, productType, cellCode)
+			if err != nil {
+				return err
+			}
+
+			product, err = uc.repo.AddProductToReception(ctx, pvzId, productType, barcode, pickupCode, volume, actorId(user))
+			if err != nil {
+				return err
+			}
+			product.OverCapacity = overCapacity
+
+			if cell != nil {
+				if err := uc.cells.AssignProduct(ctx, product.Id, cell.Id); err != nil {
+					return err
+				}
+				product.CellId = &cell.Id
+				product.CellCode = cell.Code
+			}
+
+			event, err = addEvent(ctx, uc.outbox, constants.EventProductAdded, pvzId, product.Id, productEventPayload(product))
+			return err
+		})
+		switch {
+		case errors.Is(err, repository.ErrPickupCodeTaken):
+			continue
+		case errors.Is(err, appErr.ErrPVZCapacityExceeded),
+			errors.Is(err, appErr.ErrStorageCellNotFound),
+			errors.Is(err, appErr.ErrStorageCellNotSuitable),
+			errors.Is(err, appErr.ErrStorageCellFull):
+			return nil, err
+		case err != nil:
+			return nil, appErr.ErrCreatingProduct
+		}
+
+		uc.broker.Publish(event)
+
+		return product, nil
+	}
+
+	return nil, appErr.ErrCreatingProduct
+}
+
+// checkCapacity блокирует ПВЗ до конца транзакции и проверяет, помещаются ли в него items товаров
+// общим объемом volume. По политике reject возвращает ErrPVZCapacityExceeded, по политике warn — true,
+// и товары принимаются. Вызывается на каждом пути поступления товаров в ПВЗ.
+func checkCapacity(ctx context.Context, products repository.ProductRepository, policy string, pvzId uuid.UUID, items int, volume float64) (bool, error) {
+	pvz, err := products.LockPVZCapacity(ctx, pvzId)
 	if err != nil {
-		return appErr.ErrCreatingProduct
+		return false, err
 	}
 
-	return nil
+	if !pvz.ExceedsCapacity(items, volume) {
+		return false, nil
+	}
+
+	if policy == constants.CapacityPolicyWarn {
+		return true, nil
+	}
+
+	return false, appErr.ErrPVZCapacityExceeded
 }
 
-func (uc *productUseCase) DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) error {
-	if user == nil {
-		return appErr.ErrUserRequired
+// pickCell выбирает ячейку для товара: отсканированную, если она подходит, иначе самую свободную
+// подходящую. Если подходящих ячеек нет или в ПВЗ они не заведены, товар принимается без ячейки.
+func pickCell(ctx context.Context, cells repository.StorageCellRepository, pvzId uuid.UUID, productType, cellCode string) (*domain.StorageCell, error) {
+	if cellCode == "" {
+		cell, err := cells.SuggestCell(ctx, pvzId, productType)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return cell, err
 	}
 
-	if user.Role != constants.UserRoleEmployee {
-		return appErr.ErrOnlyEmployeeAllowed
+	cell, err := cells.GetCellByCode(ctx, pvzId, normalizeCellCode(cellCode))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrStorageCellNotFound
+		}
+		return nil, err
+	}
+
+	if !cell.Accepts(productType) {
+		return nil, appErr.ErrStorageCellNotSuitable
+	}
+
+	if cell.IsFull() {
+		return nil, appErr.ErrStorageCellFull
+	}
+
+	return cell, nil
+}
+
+func (uc *productUseCase) DeleteLatProductFromReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) error {
+	if err := uc.authorizer.Authorize(user, authz.PermProductDelete); err != nil {
+		return err
 	}
 
 	if pvzId == uuid.Nil {
 		return appErr.ErrPVZIdRequired
 	}
 
-	err := uc.repo.DeleteLatProductFromReception(ctx, pvzId)
+	if err := checkPVZAccess(ctx, uc.assignments, user, pvzId); err != nil {
+		return err
+	}
+
+	var event *domain.Event
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		product, err := uc.repo.DeleteLatProductFromReception(ctx, pvzId, actorId(user))
+		if err != nil {
+			return err
+		}
+
+		event, err = addEvent(ctx, uc.outbox, constants.EventProductDeleted, pvzId, product.Id, productEventPayload(product))
+		return err
+	})
 	if err != nil {
 		return appErr.ErrDeletingLastProduct
 	}
 
+	uc.broker.Publish(event)
+
+	return nil
+}
+
+func (uc *productUseCase) IssueProduct(ctx context.Context, productId uuid.UUID, pickupCode string, user *domain.User) error {
+	if err := uc.authorizer.Authorize(user, authz.PermProductIssue); err != nil {
+		return err
+	}
+
+	if pickupCode == "" {
+		return appErr.ErrPickupCodeRequired
+	}
+
+	product, err := uc.getStoredProduct(ctx, productId, user)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(product.PickupCode), []byte(pickupCode)) != 1 {
+		return appErr.ErrInvalidPickupCode
+	}
+
+	return uc.changeStatus(ctx, productId, constants.ProductStatusIssued, user)
+}
+
+func (uc *productUseCase) ReturnProductToSender(ctx context.Context, productId uuid.UUID, user *domain.User) error {
+	if err := uc.authorizer.Authorize(user, authz.PermProductReturn); err != nil {
+		return err
+	}
+
+	if _, err := uc.getStoredProduct(ctx, productId, user); err != nil {
+		return err
+	}
+
+	return uc.changeStatus(ctx, productId, constants.ProductStatusReturnedToSender, user)
+}
+
+func (uc *productUseCase) GetInventory(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*domain.Inventory, error) {
+	if pvzId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	if err := checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, pvzId); err != nil {
+		return nil, err
+	}
+
+	products, err := uc.repo.GetInventory(ctx, pvzId)
+	if err != nil {
+		return nil, appErr.ErrGettingInventory
+	}
+
+	inventory := &domain.Inventory{
+		PVZId:    pvzId,
+		Total:    len(products),
+		ByType:   map[string]int{},
+		ByStatus: map[string]int{},
+		Products: products,
+	}
+
+	for _, product := range products {
+		inventory.ByType[product.Type]++
+		inventory.ByStatus[product.Status]++
+	}
+
+	return inventory, nil
+}
+
+func (uc *productUseCase) GetPickupInfo(ctx context.Context, productId uuid.UUID, pickupCode string) (*domain.PickupInfo, error) {
+	if productId == uuid.Nil {
+		return nil, appErr.ErrProductIdRequired
+	}
+
+	if pickupCode == "" {
+		return nil, appErr.ErrPickupCodeRequired
+	}
+
+	info, err := uc.repo.GetPickupInfo(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrOrderNotFound
+		}
+		return nil, appErr.ErrGettingProducts
+	}
+
+	// Неверный код неотличим от несуществующего заказа, чтобы не раскрывать номера заказов.
+	if info.PickupCode == "" || subtle.ConstantTimeCompare([]byte(info.PickupCode), []byte(pickupCode)) != 1 {
+		return nil, appErr.ErrOrderNotFound
+	}
+
+	return info, nil
+}
+
+// getStoredProduct загружает товар, проверяет доступ к его ПВЗ и что товар лежит на хранении.
+func (uc *productUseCase) getStoredProduct(ctx context.Context, productId uuid.UUID, user *domain.User) (*domain.Product, error) {
+	if productId == uuid.Nil {
+		return nil, appErr.ErrProductIdRequired
+	}
+
+	product, err := uc.repo.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrProductNotFound
+		}
+		return nil, appErr.ErrGettingProducts
+	}
+
+	if err = checkPVZAccess(ctx, uc.assignments, user, product.PVZId); err != nil {
+		return nil, err
+	}
+
+	if product.Status != constants.ProductStatusStored {
+		return nil, appErr.ErrProductNotStored
+	}
+
+	return product, nil
+}
+
+func (uc *productUseCase) changeStatus(ctx context.Context, productId uuid.UUID, status string, user *domain.User) error {
+	err := uc.repo.ChangeProductStatus(ctx, productId, constants.ProductStatusStored, status, actorId(user))
+	if err != nil {
+		// Товар успели выдать или вернуть параллельным запросом.
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErr.ErrProductNotStored
+		}
+		return appErr.ErrUpdatingProduct
+	}
+
 	return nil
 }
+
+// generatePickupCode создает шестизначный код получения, который сообщается клиенту.
+func generatePickupCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func productEventPayload(product *domain.Product) domain.ProductEventPayload {
+	return domain.ProductEventPayload{
+		ProductId:   product.Id,
+		ReceptionId: product.ReceptionId,
+		PVZId:       product.PVZId,
+		Type:        product.Type,
+		Barcode:     product.Barcode,
+		DateTime:    product.DateTime,
+	}
+}
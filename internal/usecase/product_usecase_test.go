@@ -5,18 +5,25 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	"github.com/aliskhannn/pvz-service/internal/repository"
 	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestProductUseCase_AddProductToReception(t *testing.T) {
 	productRepo := &repository_mocks.MockProductRepository{}
-	productUC := NewProductUseCase(productRepo)
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	assignments.On("IsAssigned", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	productRepo.On("LockPVZCapacity", mock.Anything, mock.Anything).Return(&domain.PVZ{}, nil).Maybe()
+	productUC := NewProductUseCase(productRepo, newStorageCellMock(), assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
 
 	tests := []struct {
 		name        string
@@ -46,13 +53,20 @@ func TestProductUseCase_AddProductToReception(t *testing.T) {
 			user:        &domain.User{Role: constants.UserRoleModerator},
 			pvzId:       uuid.New(),
 			productType: constants.ProductTypeElectronics,
-			expectErr:   appErr.ErrOnlyEmployeeAllowed,
+			expectErr:   appErr.ErrPermissionDenied,
 		},
 		{
-			name:        "Invalid product type",
+			name:        "Missing product type",
 			user:        &domain.User{Role: constants.UserRoleEmployee},
 			pvzId:       uuid.New(),
 			productType: "",
+			expectErr:   appErr.ErrPVZIdAndProductTypeRequired,
+		},
+		{
+			name:        "Invalid product type",
+			user:        &domain.User{Role: constants.UserRoleEmployee},
+			pvzId:       uuid.New(),
+			productType: "invalid",
 			expectErr:   appErr.ErrInvalidProductType,
 		},
 		{
@@ -67,18 +81,26 @@ func TestProductUseCase_AddProductToReception(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.user != nil && tt.user.Role == constants.UserRoleEmployee && tt.productType != "" {
-				productRepo.On("AddProductToReception", mock.Anything, tt.pvzId, tt.productType).
-					Return(tt.repoErr).
+			if tt.user != nil && tt.user.Role == constants.UserRoleEmployee && isProductType(tt.productType) {
+				var product *domain.Product
+				if tt.repoErr == nil {
+					product = &domain.Product{PVZId: tt.pvzId, Type: tt.productType, Status: constants.ProductStatusReceived}
+				}
+				productRepo.On("AddProductToReception", mock.Anything, tt.pvzId, tt.productType, "", mock.MatchedBy(func(code string) bool {
+					return len(code) == 6
+				}), (*float64)(nil), tt.user.Id).
+					Return(product, tt.repoErr).
 					Once()
 			}
 
-			err := productUC.AddProductToReception(context.Background(), tt.pvzId, tt.productType, tt.user)
+			product, err := productUC.AddProductToReception(context.Background(), tt.pvzId, tt.productType, "", "", nil, tt.user)
 
 			if tt.expectErr != nil {
 				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, product)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, constants.ProductStatusReceived, product.Status)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -88,7 +110,9 @@ func TestProductUseCase_AddProductToReception(t *testing.T) {
 
 func TestProductUseCase_DeleteLatProductFromReception(t *testing.T) {
 	productRepo := &repository_mocks.MockProductRepository{}
-	productUC := NewProductUseCase(productRepo)
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	assignments.On("IsAssigned", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	productUC := NewProductUseCase(productRepo, newStorageCellMock(), assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
 
 	tests := []struct {
 		name      string
@@ -114,7 +138,7 @@ func TestProductUseCase_DeleteLatProductFromReception(t *testing.T) {
 			name:      "Non-employee user",
 			user:      &domain.User{Role: constants.UserRoleModerator},
 			pvzId:     uuid.New(),
-			expectErr: appErr.ErrOnlyEmployeeAllowed,
+			expectErr: appErr.ErrPermissionDenied,
 		},
 		{
 			name:      "Repository error",
@@ -128,8 +152,12 @@ func TestProductUseCase_DeleteLatProductFromReception(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.user != nil && tt.user.Role == constants.UserRoleEmployee {
-				productRepo.On("DeleteLatProductFromReception", mock.Anything, tt.pvzId).
-					Return(tt.repoErr).
+				var product *domain.Product
+				if tt.repoErr == nil {
+					product = &domain.Product{Id: uuid.New(), PVZId: tt.pvzId}
+				}
+				productRepo.On("DeleteLatProductFromReception", mock.Anything, tt.pvzId, tt.user.Id).
+					Return(product, tt.repoErr).
 					Once()
 			}
 
@@ -145,3 +173,437 @@ func TestProductUseCase_DeleteLatProductFromReception(t *testing.T) {
 		})
 	}
 }
+
+func TestProductUseCase_NotAssignedEmployee(t *testing.T) {
+	productRepo := &repository_mocks.MockProductRepository{}
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	productUC := NewProductUseCase(productRepo, newStorageCellMock(), assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+	user := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	pvzId := uuid.New()
+
+	assignments.On("IsAssigned", mock.Anything, user.Id, pvzId).Return(false, nil).Twice()
+
+	_, err := productUC.AddProductToReception(context.Background(), pvzId, constants.ProductTypeShoes, "", "", nil, user)
+	assert.ErrorIs(t, err, appErr.ErrPVZAccessDenied)
+
+	err = productUC.DeleteLatProductFromReception(context.Background(), pvzId, user)
+	assert.ErrorIs(t, err, appErr.ErrPVZAccessDenied)
+
+	productRepo.AssertNotCalled(t, "AddProductToReception", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	productRepo.AssertNotCalled(t, "DeleteLatProductFromReception", mock.Anything, mock.Anything, mock.Anything)
+	assignments.AssertExpectations(t)
+}
+
+func TestProductUseCase_IssueProduct(t *testing.T) {
+	user := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	productId := uuid.New()
+	pvzId := uuid.New()
+
+	tests := []struct {
+		name       string
+		user       *domain.User
+		pickupCode string
+		product    *domain.Product
+		productErr error
+		mockChange bool
+		changeErr  error
+		expectErr  error
+	}{
+		{
+			name:       "Valid issue",
+			user:       user,
+			pickupCode: "123456",
+			product:    &domain.Product{Id: productId, PVZId: pvzId, Status: constants.ProductStatusStored, PickupCode: "123456"},
+			mockChange: true,
+		},
+		{
+			name:       "Auditor cannot issue",
+			user:       &domain.User{Role: constants.UserRoleAuditor},
+			pickupCode: "123456",
+			expectErr:  appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Missing pickup code",
+			user:      user,
+			expectErr: appErr.ErrPickupCodeRequired,
+		},
+		{
+			name:       "Product not found",
+			user:       user,
+			pickupCode: "123456",
+			productErr: pgx.ErrNoRows,
+			expectErr:  appErr.ErrProductNotFound,
+		},
+		{
+			name:       "Wrong pickup code",
+			user:       user,
+			pickupCode: "000000",
+			product:    &domain.Product{Id: productId, PVZId: pvzId, Status: constants.ProductStatusStored, PickupCode: "123456"},
+			expectErr:  appErr.ErrInvalidPickupCode,
+		},
+		{
+			name:       "Product still in open reception",
+			user:       user,
+			pickupCode: "123456",
+			product:    &domain.Product{Id: productId, PVZId: pvzId, Status: constants.ProductStatusReceived, PickupCode: "123456"},
+			expectErr:  appErr.ErrProductNotStored,
+		},
+		{
+			name:       "Product already issued concurrently",
+			user:       user,
+			pickupCode: "123456",
+			product:    &domain.Product{Id: productId, PVZId: pvzId, Status: constants.ProductStatusStored, PickupCode: "123456"},
+			mockChange: true,
+			changeErr:  pgx.ErrNoRows,
+			expectErr:  appErr.ErrProductNotStored,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := &repository_mocks.MockProductRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			assignments.On("IsAssigned", mock.Anything, user.Id, pvzId).Return(true, nil).Maybe()
+			productUC := NewProductUseCase(productRepo, newStorageCellMock(), assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+			if tt.product != nil || tt.productErr != nil {
+				productRepo.On("GetProductById", mock.Anything, productId).Return(tt.product, tt.productErr).Once()
+			}
+			if tt.mockChange {
+				productRepo.On("ChangeProductStatus", mock.Anything, productId, constants.ProductStatusStored, constants.ProductStatusIssued, user.Id).
+					Return(tt.changeErr).
+					Once()
+			}
+
+			err := productUC.IssueProduct(context.Background(), productId, tt.pickupCode, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			productRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductUseCase_GetInventory(t *testing.T) {
+	pvzId := uuid.New()
+	products := []*domain.Product{
+		{Id: uuid.New(), Type: constants.ProductTypeShoes, Status: constants.ProductStatusStored},
+		{Id: uuid.New(), Type: constants.ProductTypeShoes, Status: constants.ProductStatusReceived},
+		{Id: uuid.New(), Type: constants.ProductTypeElectronics, Status: constants.ProductStatusStored},
+	}
+
+	productRepo := &repository_mocks.MockProductRepository{}
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	productUC := NewProductUseCase(productRepo, newStorageCellMock(), assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+	productRepo.On("GetInventory", mock.Anything, pvzId).Return(products, nil).Once()
+
+	// Аудитор видит любой ПВЗ без закрепления.
+	inventory, err := productUC.GetInventory(context.Background(), pvzId, &domain.User{Role: constants.UserRoleAuditor})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inventory.Total)
+	assert.Equal(t, 2, inventory.ByType[constants.ProductTypeShoes])
+	assert.Equal(t, 2, inventory.ByStatus[constants.ProductStatusStored])
+	assert.Equal(t, 1, inventory.ByStatus[constants.ProductStatusReceived])
+
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	assignments.On("IsAssigned", mock.Anything, employee.Id, pvzId).Return(false, nil).Once()
+
+	_, err = productUC.GetInventory(context.Background(), pvzId, employee)
+
+	assert.ErrorIs(t, err, appErr.ErrPVZAccessDenied)
+	productRepo.AssertExpectations(t)
+	assignments.AssertExpectations(t)
+}
+
+func TestProductUseCase_AddProductToReception_PickupCodeTaken(t *testing.T) {
+	pvzId := uuid.New()
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+
+	productRepo := &repository_mocks.MockProductRepository{}
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	productUC := NewProductUseCase(productRepo, newStorageCellMock(), assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+	assignments.On("IsAssigned", mock.Anything, employee.Id, pvzId).Return(true, nil)
+	productRepo.On("LockPVZCapacity", mock.Anything, pvzId).Return(&domain.PVZ{Id: pvzId}, nil)
+	productRepo.On("AddProductToReception", mock.Anything, pvzId, constants.ProductTypeShoes, "", mock.Anything, (*float64)(nil), employee.Id).
+		Return(nil, repository.ErrPickupCodeTaken).Once()
+	productRepo.On("AddProductToReception", mock.Anything, pvzId, constants.ProductTypeShoes, "", mock.Anything, (*float64)(nil), employee.Id).
+		Return(&domain.Product{PVZId: pvzId}, nil).Once()
+
+	// Занятый код генерируется заново.
+	product, err := productUC.AddProductToReception(context.Background(), pvzId, constants.ProductTypeShoes, "", "", nil, employee)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, product)
+	productRepo.AssertExpectations(t)
+
+	productRepo.On("AddProductToReception", mock.Anything, pvzId, constants.ProductTypeShoes, "", mock.Anything, (*float64)(nil), employee.Id).
+		Return(nil, repository.ErrPickupCodeTaken).Times(pickupCodeAttempts)
+
+	_, err = productUC.AddProductToReception(context.Background(), pvzId, constants.ProductTypeShoes, "", "", nil, employee)
+
+	assert.ErrorIs(t, err, appErr.ErrCreatingProduct)
+}
+
+func TestProductUseCase_AddProductToReception_Capacity(t *testing.T) {
+	items := 10
+	litres := 50.0
+	small := 5.0
+	large := 20.0
+
+	tests := []struct {
+		name       string
+		policy     string
+		occupancy  domain.PVZOccupancy
+		volume     *float64
+		expectAdd  bool
+		expectOver bool
+		expectErr  error
+	}{
+		{
+			name:      "Fits",
+			policy:    constants.CapacityPolicyReject,
+			occupancy: domain.PVZOccupancy{Items: 9, Volume: 40},
+			volume:    &small,
+			expectAdd: true,
+		},
+		{
+			name:      "Item limit reached, reject",
+			policy:    constants.CapacityPolicyReject,
+			occupancy: domain.PVZOccupancy{Items: 10},
+			expectErr: appErr.ErrPVZCapacityExceeded,
+		},
+		{
+			name:      "Volume limit exceeded, reject",
+			policy:    constants.CapacityPolicyReject,
+			occupancy: domain.PVZOccupancy{Items: 3, Volume: 40},
+			volume:    &large,
+			expectErr: appErr.ErrPVZCapacityExceeded,
+		},
+		{
+			name:       "Item limit reached, warn",
+			policy:     constants.CapacityPolicyWarn,
+			occupancy:  domain.PVZOccupancy{Items: 10},
+			expectAdd:  true,
+			expectOver: true,
+		},
+		{
+			name:      "Empty policy rejects",
+			occupancy: domain.PVZOccupancy{Items: 10},
+			expectErr: appErr.ErrPVZCapacityExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvzId := uuid.New()
+			employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+			occupancy := tt.occupancy
+
+			productRepo := &repository_mocks.MockProductRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			productUC := NewProductUseCase(productRepo, newStorageCellMock(), assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), tt.policy)
+
+			assignments.On("IsAssigned", mock.Anything, employee.Id, pvzId).Return(true, nil)
+			productRepo.On("LockPVZCapacity", mock.Anything, pvzId).
+				Return(&domain.PVZ{Id: pvzId, CapacityItems: &items, CapacityVolume: &litres, Occupancy: &occupancy}, nil)
+			if tt.expectAdd {
+				productRepo.On("AddProductToReception", mock.Anything, pvzId, constants.ProductTypeShoes, "", mock.Anything, tt.volume, employee.Id).
+					Return(&domain.Product{PVZId: pvzId, Volume: tt.volume}, nil).Once()
+			}
+
+			product, err := productUC.AddProductToReception(context.Background(), pvzId, constants.ProductTypeShoes, "", "", tt.volume, employee)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				productRepo.AssertNotCalled(t, "AddProductToReception", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOver, product.OverCapacity)
+			productRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductUseCase_AddProductToReception_InvalidVolume(t *testing.T) {
+	productRepo := &repository_mocks.MockProductRepository{}
+	productUC := NewProductUseCase(productRepo, newStorageCellMock(), &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+	volume := 0.0
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+
+	_, err := productUC.AddProductToReception(context.Background(), uuid.New(), constants.ProductTypeShoes, "", "", &volume, employee)
+
+	assert.ErrorIs(t, err, appErr.ErrInvalidVolume)
+	productRepo.AssertNotCalled(t, "LockPVZCapacity", mock.Anything, mock.Anything)
+}
+
+func TestProductUseCase_AddProductToReception_StorageCell(t *testing.T) {
+	one := 1
+	shoesOnly := []string{constants.ProductTypeShoes}
+
+	tests := []struct {
+		name       string
+		cellCode   string
+		cell       *domain.StorageCell
+		cellErr    error
+		expectCode string
+		expectErr  error
+	}{
+		{
+			name:       "Suggested cell",
+			cell:       &domain.StorageCell{Id: uuid.New(), Code: "A-1-1"},
+			expectCode: "A-1-1",
+		},
+		{
+			name:    "No cells in pvz",
+			cellErr: pgx.ErrNoRows,
+		},
+		{
+			name:       "Scanned cell",
+			cellCode:   " b-2-3 ",
+			cell:       &domain.StorageCell{Id: uuid.New(), Code: "B-2-3", ProductTypes: shoesOnly},
+			expectCode: "B-2-3",
+		},
+		{
+			name:      "Scanned cell not found",
+			cellCode:  "Z-9-9",
+			cellErr:   pgx.ErrNoRows,
+			expectErr: appErr.ErrStorageCellNotFound,
+		},
+		{
+			name:      "Scanned cell for another type",
+			cellCode:  "C-1-1",
+			cell:      &domain.StorageCell{Id: uuid.New(), Code: "C-1-1", ProductTypes: []string{constants.ProductTypeElectronics}},
+			expectErr: appErr.ErrStorageCellNotSuitable,
+		},
+		{
+			name:      "Scanned cell is full",
+			cellCode:  "D-1-1",
+			cell:      &domain.StorageCell{Id: uuid.New(), Code: "D-1-1", Capacity: &one, Occupied: 1},
+			expectErr: appErr.ErrStorageCellFull,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvzId := uuid.New()
+			productId := uuid.New()
+			employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+
+			productRepo := &repository_mocks.MockProductRepository{}
+			cells := &repository_mocks.MockStorageCellRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			productUC := NewProductUseCase(productRepo, cells, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+			assignments.On("IsAssigned", mock.Anything, employee.Id, pvzId).Return(true, nil)
+			productRepo.On("LockPVZCapacity", mock.Anything, pvzId).Return(&domain.PVZ{Id: pvzId}, nil)
+			if tt.cellCode == "" {
+				cells.On("SuggestCell", mock.Anything, pvzId, constants.ProductTypeShoes).Return(tt.cell, tt.cellErr).Once()
+			} else {
+				cells.On("GetCellByCode", mock.Anything, pvzId, normalizeCellCode(tt.cellCode)).Return(tt.cell, tt.cellErr).Once()
+			}
+			if tt.expectErr == nil {
+				productRepo.On("AddProductToReception", mock.Anything, pvzId, constants.ProductTypeShoes, "", mock.Anything, (*float64)(nil), employee.Id).
+					Return(&domain.Product{Id: productId, PVZId: pvzId}, nil).Once()
+			}
+			if tt.expectCode != "" {
+				cells.On("AssignProduct", mock.Anything, productId, tt.cell.Id).Return(nil).Once()
+			}
+
+			product, err := productUC.AddProductToReception(context.Background(), pvzId, constants.ProductTypeShoes, "", tt.cellCode, nil, employee)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				productRepo.AssertNotCalled(t, "AddProductToReception", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectCode, product.CellCode)
+			if tt.expectCode == "" {
+				assert.Nil(t, product.CellId)
+			}
+			productRepo.AssertExpectations(t)
+			cells.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductUseCase_GetPickupInfo(t *testing.T) {
+	productId := uuid.New()
+	info := &domain.PickupInfo{ProductId: productId, PickupCode: "123456", City: constants.PVZCityKazan}
+
+	tests := []struct {
+		name       string
+		pickupCode string
+		mockRepo   bool
+		info       *domain.PickupInfo
+		repoErr    error
+		expectErr  error
+	}{
+		{
+			name:       "Valid code",
+			pickupCode: "123456",
+			mockRepo:   true,
+			info:       info,
+		},
+		{
+			name:       "Wrong code",
+			pickupCode: "654321",
+			mockRepo:   true,
+			info:       info,
+			expectErr:  appErr.ErrOrderNotFound,
+		},
+		{
+			name:       "Product not found",
+			pickupCode: "123456",
+			mockRepo:   true,
+			repoErr:    pgx.ErrNoRows,
+			expectErr:  appErr.ErrOrderNotFound,
+		},
+		{
+			name:       "Product without code",
+			pickupCode: "123456",
+			mockRepo:   true,
+			info:       &domain.PickupInfo{ProductId: productId},
+			expectErr:  appErr.ErrOrderNotFound,
+		},
+		{
+			name:      "Empty code",
+			expectErr: appErr.ErrPickupCodeRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := &repository_mocks.MockProductRepository{}
+			productUC := NewProductUseCase(productRepo, newStorageCellMock(), &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+			if tt.mockRepo {
+				productRepo.On("GetPickupInfo", mock.Anything, productId).Return(tt.info, tt.repoErr).Once()
+			}
+
+			result, err := productUC.GetPickupInfo(context.Background(), productId, tt.pickupCode)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.info, result)
+			}
+
+			productRepo.AssertExpectations(t)
+		})
+	}
+}
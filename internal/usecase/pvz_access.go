@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// checkPVZAccess проверяет, что сотрудник закреплен за ПВЗ.
+// Для dummy-пользователей список ПВЗ берется из токена, для остальных — из БД.
+func checkPVZAccess(ctx context.Context, assignments repository.AssignmentRepository, user *domain.User, pvzId uuid.UUID) error {
+	if user.Dummy {
+		for _, id := range user.PVZIds {
+			if id == pvzId {
+				return nil
+			}
+		}
+		return appErr.ErrPVZAccessDenied
+	}
+
+	assigned, err := assignments.IsAssigned(ctx, user.Id, pvzId)
+	if err != nil {
+		return appErr.ErrCheckingPVZAccess
+	}
+
+	if !assigned {
+		return appErr.ErrPVZAccessDenied
+	}
+
+	return nil
+}
+
+// checkPVZReadAccess ограничивает просмотр данных ПВЗ: роли, работающие на ПВЗ (открывают приемки),
+// видят только свои пункты, остальные роли с правом чтения — все.
+func checkPVZReadAccess(ctx context.Context, assignments repository.AssignmentRepository, authorizer authz.Authorizer, user *domain.User, pvzId uuid.UUID) error {
+	if err := authorizer.Authorize(user, authz.PermPVZRead); err != nil {
+		return err
+	}
+
+	if !authorizer.Can(user.Role, authz.PermReceptionOpen) {
+		return nil
+	}
+
+	return checkPVZAccess(ctx, assignments, user, pvzId)
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckPVZAccess(t *testing.T) {
+	pvzId := uuid.New()
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		mockRepo  bool
+		assigned  bool
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:     "Assigned employee",
+			user:     &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			mockRepo: true,
+			assigned: true,
+		},
+		{
+			name:      "Not assigned employee",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			mockRepo:  true,
+			assigned:  false,
+			expectErr: appErr.ErrPVZAccessDenied,
+		},
+		{
+			name:      "Repository error",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			mockRepo:  true,
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrCheckingPVZAccess,
+		},
+		{
+			name: "Dummy employee with pvz in token",
+			user: &domain.User{Role: constants.UserRoleEmployee, Dummy: true, PVZIds: []uuid.UUID{uuid.New(), pvzId}},
+		},
+		{
+			name:      "Dummy employee without pvz in token",
+			user:      &domain.User{Role: constants.UserRoleEmployee, Dummy: true},
+			expectErr: appErr.ErrPVZAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignments := &repository_mocks.MockAssignmentRepository{}
+
+			if tt.mockRepo {
+				assignments.On("IsAssigned", mock.Anything, tt.user.Id, pvzId).
+					Return(tt.assigned, tt.repoErr).
+					Once()
+			}
+
+			err := checkPVZAccess(context.Background(), assignments, tt.user, pvzId)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assignments.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"io"
+	"sort"
+	"strings"
+)
+
+// maxPVZImportRows ограничивает размер одного импорта, чтобы транзакция оставалась короткой.
+const maxPVZImportRows = 5000
+
+// pvzImportColumns — допустимые колонки CSV; hours — короткое имя opening_hours.
+var pvzImportColumns = map[string]string{
+	"city":          "city",
+	"address":       "address",
+	"opening_hours": "opening_hours",
+	"hours":         "opening_hours",
+}
+
+// PVZImporter создает ПВЗ из CSV одной транзакцией: либо все строки, либо ни одной.
+// Используется эндпоинтом импорта и командой pvz-import; права проверяет вызывающий.
+type PVZImporter struct {
+	repo repository.PVZRepository
+	tx   repository.TxManager
+}
+
+func NewPVZImporter(repo repository.PVZRepository, tx repository.TxManager) *PVZImporter {
+	return &PVZImporter{
+		repo: repo,
+		tx:   tx,
+	}
+}
+
+// Import читает CSV с заголовком (city, address, opening_hours или hours) и создает ПВЗ.
+// Ошибки в строках и совпадения с уже существующими ПВЗ возвращаются в результате, тогда
+// ничего не создается. При dryRun строки только проверяются.
+func (i *PVZImporter) Import(ctx context.Context, r io.Reader, dryRun bool) (*domain.PVZImportResult, error) {
+	pvzs, lines, rowErrors, err := parsePVZImport(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PVZImportResult{DryRun: dryRun, PVZs: pvzs, Errors: rowErrors}
+
+	err = i.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if len(pvzs) > 0 {
+			existing, err := i.repo.FindPVZsByAddress(ctx, pvzs)
+			if err != nil {
+				return err
+			}
+
+			for _, pvz := range existing {
+				result.Errors = append(result.Errors, domain.PVZImportError{
+					Line:    lines[pvzImportKey(pvz)],
+					Message: fmt.Sprintf("pvz with this address already exists: %s", pvz.Id),
+				})
+			}
+		}
+
+		if len(result.Errors) > 0 || dryRun {
+			return nil
+		}
+
+		for _, pvz := range pvzs {
+			if err := i.repo.CreatePVZ(ctx, pvz); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, appErr.ErrImportingPVZs
+	}
+
+	if len(result.Errors) > 0 {
+		sort.SliceStable(result.Errors, func(a, b int) bool {
+			return result.Errors[a].Line < result.Errors[b].Line
+		})
+		result.PVZs = []*domain.PVZ{}
+	}
+
+	return result, nil
+}
+
+// parsePVZImport разбирает CSV и проверяет каждую строку. Возвращает корректные ПВЗ,
+// номера их строк по ключу город+адрес и ошибки строк; err — если файл нельзя разобрать целиком.
+func parsePVZImport(r io.Reader) ([]*domain.PVZ, map[string]int, []domain.PVZImportError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil, appErr.ErrPVZImportEmpty
+		}
+		return nil, nil, nil, pvzImportReadError(err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for idx, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		column, ok := pvzImportColumns[name]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("%w: unknown column %q", appErr.ErrInvalidPVZImportHeader, name)
+		}
+		if _, dup := columns[column]; dup {
+			return nil, nil, nil, fmt.Errorf("%w: duplicate column %q", appErr.ErrInvalidPVZImportHeader, name)
+		}
+		columns[column] = idx
+	}
+
+	if _, ok := columns["city"]; !ok {
+		return nil, nil, nil, appErr.ErrInvalidPVZImportHeader
+	}
+	if _, ok := columns["address"]; !ok {
+		return nil, nil, nil, appErr.ErrInvalidPVZImportHeader
+	}
+
+	field := func(record []string, column string) string {
+		idx, ok := columns[column]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	var pvzs []*domain.PVZ
+	var rowErrors []domain.PVZImportError
+	lines := make(map[string]int)
+	rows := 0
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				// После ошибки кавычек разбор остальной части файла ненадежен.
+				rowErrors = append(rowErrors, domain.PVZImportError{Line: parseErr.Line, Message: parseErr.Err.Error()})
+				break
+			}
+			return nil, nil, nil, pvzImportReadError(err)
+		}
+
+		rows++
+		if rows > maxPVZImportRows {
+			return nil, nil, nil, fmt.Errorf("%w: at most %d rows are allowed", appErr.ErrPVZImportTooLarge, maxPVZImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if len(record) != len(header) {
+			rowErrors = append(rowErrors, domain.PVZImportError{
+				Line:    line,
+				Message: fmt.Sprintf("expected %d fields, got %d", len(header), len(record)),
+			})
+			continue
+		}
+
+		pvz := &domain.PVZ{
+			City:         field(record, "city"),
+			Address:      field(record, "address"),
+			OpeningHours: field(record, "opening_hours"),
+		}
+
+		var problems []string
+		if !isPVZCity(pvz.City) {
+			problems = append(problems, fmt.Sprintf("unknown city %q", pvz.City))
+		}
+		if pvz.Address == "" {
+			problems = append(problems, "address is required")
+		}
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, domain.PVZImportError{Line: line, Message: strings.Join(problems, "; ")})
+			continue
+		}
+
+		key := pvzImportKey(pvz)
+		if first, dup := lines[key]; dup {
+			rowErrors = append(rowErrors, domain.PVZImportError{
+				Line:    line,
+				Message: fmt.Sprintf("duplicate of line %d", first),
+			})
+			continue
+		}
+
+		lines[key] = line
+		pvzs = append(pvzs, pvz)
+	}
+
+	if rows == 0 && len(rowErrors) == 0 {
+		return nil, nil, nil, appErr.ErrPVZImportEmpty
+	}
+
+	return pvzs, lines, rowErrors, nil
+}
+
+// pvzImportReadError помечает как ошибку импорта все, кроме ошибок чтения тела запроса
+// (например, превышения размера), которые вызывающий обрабатывает сам.
+func pvzImportReadError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%w: %v", appErr.ErrInvalidPVZImportHeader, err)
+	}
+	return err
+}
+
+func pvzImportKey(pvz *domain.PVZ) string {
+	return pvz.City + "\x00" + strings.ToLower(pvz.Address)
+}
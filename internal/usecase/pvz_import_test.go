@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPVZImporter_Import(t *testing.T) {
+	tests := []struct {
+		name          string
+		csv           string
+		dryRun        bool
+		existing      []*domain.PVZ
+		createErr     error
+		expectCreated int
+		expectPVZs    int
+		expectErrors  []domain.PVZImportError
+		expectErr     error
+	}{
+		{
+			name:          "Valid file",
+			csv:           "city,address,hours\nКазань,ул. Баумана 1,09-21\nМосква,Тверская 7,\n",
+			expectCreated: 2,
+			expectPVZs:    2,
+		},
+		{
+			name:       "Dry run does not create",
+			csv:        "\uFEFFcity,address\nКазань,ул. Баумана 1\n",
+			dryRun:     true,
+			expectPVZs: 1,
+		},
+		{
+			name: "Errors are reported per line",
+			csv: "city,address,opening_hours\n" +
+				"Сочи,Морская 1,\n" +
+				"Казань,,\n" +
+				"Казань,ул. Баумана 1\n" +
+				"Москва,Тверская 7,\n" +
+				"Москва,тверская 7,\n",
+			expectErrors: []domain.PVZImportError{
+				{Line: 2, Message: `unknown city "Сочи"`},
+				{Line: 3, Message: "address is required"},
+				{Line: 4, Message: "expected 3 fields, got 2"},
+				{Line: 6, Message: "duplicate of line 5"},
+			},
+		},
+		{
+			name:     "Existing PVZ",
+			csv:      "city,address\nКазань,ул. Баумана 1\nМосква,Тверская 7\n",
+			existing: []*domain.PVZ{{Id: uuid.Nil, City: "Москва", Address: "Тверская 7"}},
+			expectErrors: []domain.PVZImportError{
+				{Line: 3, Message: "pvz with this address already exists: " + uuid.Nil.String()},
+			},
+		},
+		{
+			name:      "Missing address column",
+			csv:       "city,hours\nКазань,09-21\n",
+			expectErr: appErr.ErrInvalidPVZImportHeader,
+		},
+		{
+			name:      "Unknown column",
+			csv:       "city,address,region\nКазань,ул. Баумана 1,Татарстан\n",
+			expectErr: appErr.ErrInvalidPVZImportHeader,
+		},
+		{
+			name:      "Header only",
+			csv:       "city,address\n",
+			expectErr: appErr.ErrPVZImportEmpty,
+		},
+		{
+			name:      "Insert error rolls back everything",
+			csv:       "city,address\nКазань,ул. Баумана 1\n",
+			createErr: errors.New("db error"),
+			expectErr: appErr.ErrImportingPVZs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockPVZRepository{}
+			repo.On("FindPVZsByAddress", mock.Anything, mock.Anything).Return(tt.existing, nil)
+			repo.On("CreatePVZ", mock.Anything, mock.Anything).Return(tt.createErr)
+
+			importer := NewPVZImporter(repo, &repository_mocks.MockTxManager{})
+			result, err := importer.Import(context.Background(), strings.NewReader(tt.csv), tt.dryRun)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.dryRun, result.DryRun)
+			assert.Equal(t, tt.expectErrors, result.Errors)
+			assert.Len(t, result.PVZs, tt.expectPVZs)
+			repo.AssertNumberOfCalls(t, "CreatePVZ", tt.expectCreated)
+		})
+	}
+}
+
+func TestPVZImporter_ImportTooLarge(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("city,address\n")
+	for i := 0; i <= maxPVZImportRows; i++ {
+		sb.WriteString("Казань,ул. Баумана " + uuid.NewString() + "\n")
+	}
+
+	importer := NewPVZImporter(&repository_mocks.MockPVZRepository{}, &repository_mocks.MockTxManager{})
+	_, err := importer.Import(context.Background(), strings.NewReader(sb.String()), true)
+
+	assert.ErrorIs(t, err, appErr.ErrPVZImportTooLarge)
+}
+
+func TestPvzUseCase_ImportPVZs(t *testing.T) {
+	pvzUC := NewPvzUseCase(&repository_mocks.MockPVZRepository{}, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{})
+
+	_, err := pvzUC.ImportPVZs(context.Background(), strings.NewReader("city,address\n"), true,
+		&domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee})
+
+	assert.ErrorIs(t, err, appErr.ErrPermissionDenied)
+}
@@ -2,43 +2,58 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
 	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"io"
 	"time"
 )
 
 type PvzUseCase interface {
 	CreatePVZ(ctx context.Context, pvz *domain.PVZ, user *domain.User) error
 	GetAllPVZsWithReceptions(ctx context.Context, user *domain.User, startDate, endDate time.Time, page, limit int) ([]*domain.PVZ, error)
+	// SetPVZCapacity задает вместимость ПВЗ в товарах и литрах; nil снимает ограничение.
+	SetPVZCapacity(ctx context.Context, pvzId uuid.UUID, items *int, volume *float64, user *domain.User) (*domain.PVZ, error)
+	// ImportPVZs создает ПВЗ из CSV одной транзакцией, см. PVZImporter.Import.
+	ImportPVZs(ctx context.Context, r io.Reader, dryRun bool, user *domain.User) (*domain.PVZImportResult, error)
 }
 
 type pvzUseCase struct {
-	repo repository.PVZRepository
+	repo       repository.PVZRepository
+	authorizer authz.Authorizer
+	importer   *PVZImporter
 }
 
-func NewPvzUseCase(repo repository.PVZRepository) PvzUseCase {
-	return &pvzUseCase{repo: repo}
+func NewPvzUseCase(repo repository.PVZRepository, authorizer authz.Authorizer, tx repository.TxManager) PvzUseCase {
+	return &pvzUseCase{
+		repo:       repo,
+		authorizer: authorizer,
+		importer:   NewPVZImporter(repo, tx),
+	}
 }
 
 func (uc *pvzUseCase) CreatePVZ(ctx context.Context, pvz *domain.PVZ, user *domain.User) error {
-	if user == nil {
-		return appErr.ErrUserRequired
-	}
-
-	if user.Role != constants.UserRoleModerator {
-		return appErr.ErrOnlyModeratorAllowed
+	if err := uc.authorizer.Authorize(user, authz.PermPVZCreate); err != nil {
+		return err
 	}
 
 	if pvz == nil {
 		return appErr.ErrPVZIdRequired
 	}
 
-	if pvz.City != constants.PVZCityMoscow && pvz.City != constants.PVZCitySaintPetersburg && pvz.City != constants.PVZCityKazan {
+	if !isPVZCity(pvz.City) {
 		return appErr.ErrInvalidCity
 	}
 
+	if !validCapacity(pvz.CapacityItems, pvz.CapacityVolume) {
+		return appErr.ErrInvalidPVZCapacity
+	}
+
 	err := uc.repo.CreatePVZ(ctx, pvz)
 	if err != nil {
 		return appErr.ErrCreatingPVZ
@@ -47,13 +62,41 @@ func (uc *pvzUseCase) CreatePVZ(ctx context.Context, pvz *domain.PVZ, user *doma
 	return nil
 }
 
-func (uc *pvzUseCase) GetAllPVZsWithReceptions(ctx context.Context, user *domain.User, startDate, endDate time.Time, offset, limit int) ([]*domain.PVZ, error) {
-	if user == nil {
-		return nil, appErr.ErrUserRequired
+func (uc *pvzUseCase) ImportPVZs(ctx context.Context, r io.Reader, dryRun bool, user *domain.User) (*domain.PVZImportResult, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermPVZCreate); err != nil {
+		return nil, err
+	}
+
+	return uc.importer.Import(ctx, r, dryRun)
+}
+
+func (uc *pvzUseCase) SetPVZCapacity(ctx context.Context, pvzId uuid.UUID, items *int, volume *float64, user *domain.User) (*domain.PVZ, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermPVZCreate); err != nil {
+		return nil, err
 	}
 
-	if user.Role != constants.UserRoleModerator && user.Role != constants.UserRoleEmployee {
-		return nil, appErr.ErrInvalidRole
+	if pvzId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	if !validCapacity(items, volume) {
+		return nil, appErr.ErrInvalidPVZCapacity
+	}
+
+	pvz, err := uc.repo.UpdatePVZCapacity(ctx, pvzId, items, volume)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrPVZNotFound
+		}
+		return nil, appErr.ErrUpdatingPVZ
+	}
+
+	return pvz, nil
+}
+
+func (uc *pvzUseCase) GetAllPVZsWithReceptions(ctx context.Context, user *domain.User, startDate, endDate time.Time, offset, limit int) ([]*domain.PVZ, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermPVZRead); err != nil {
+		return nil, err
 	}
 
 	pvzs, err := uc.repo.GetAllPVZs(ctx, offset, limit)
@@ -77,7 +120,19 @@ func (uc *pvzUseCase) GetAllPVZsWithReceptions(ctx context.Context, user *domain
 		}
 
 		pvz.Receptions = receptions
+
+		if pvz.Occupancy != nil {
+			pvz.Occupancy.Utilization = pvz.Utilization()
+		}
 	}
 
 	return pvzs, nil
 }
+
+func validCapacity(items *int, volume *float64) bool {
+	return (items == nil || *items > 0) && (volume == nil || *volume > 0)
+}
+
+func isPVZCity(city string) bool {
+	return city == constants.PVZCityMoscow || city == constants.PVZCitySaintPetersburg || city == constants.PVZCityKazan
+}
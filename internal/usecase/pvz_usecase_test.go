@@ -6,18 +6,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
 	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestPvzUseCase_CreatePVZ(t *testing.T) {
 	repo := &repository_mocks.MockPVZRepository{}
-	pvzUC := NewPvzUseCase(repo)
+	pvzUC := NewPvzUseCase(repo, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{})
 
 	validUser := &domain.User{
 		Id:   uuid.New(),
@@ -53,7 +55,7 @@ func TestPvzUseCase_CreatePVZ(t *testing.T) {
 			name:      "Non-moderator user",
 			pvz:       validPVZ,
 			user:      &domain.User{Role: constants.UserRoleEmployee},
-			expectErr: appErr.ErrOnlyModeratorAllowed,
+			expectErr: appErr.ErrPermissionDenied,
 		},
 		{
 			name:      "Nil PVZ",
@@ -97,9 +99,102 @@ func TestPvzUseCase_CreatePVZ(t *testing.T) {
 	}
 }
 
+func TestPvzUseCase_SetPVZCapacity(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	pvzId := uuid.New()
+	items := 500
+	zero := 0
+	volume := 1200.5
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		pvzId     uuid.UUID
+		items     *int
+		volume    *float64
+		mockRepo  bool
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:     "Set items and volume",
+			user:     moderator,
+			pvzId:    pvzId,
+			items:    &items,
+			volume:   &volume,
+			mockRepo: true,
+		},
+		{
+			name:     "Remove limits",
+			user:     moderator,
+			pvzId:    pvzId,
+			mockRepo: true,
+		},
+		{
+			name:      "Zero capacity",
+			user:      moderator,
+			pvzId:     pvzId,
+			items:     &zero,
+			expectErr: appErr.ErrInvalidPVZCapacity,
+		},
+		{
+			name:      "Employee is not allowed",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			pvzId:     pvzId,
+			items:     &items,
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "PVZ not found",
+			user:      moderator,
+			pvzId:     pvzId,
+			items:     &items,
+			mockRepo:  true,
+			repoErr:   pgx.ErrNoRows,
+			expectErr: appErr.ErrPVZNotFound,
+		},
+		{
+			name:      "Repository error",
+			user:      moderator,
+			pvzId:     pvzId,
+			items:     &items,
+			mockRepo:  true,
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrUpdatingPVZ,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockPVZRepository{}
+			pvzUC := NewPvzUseCase(repo, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{})
+
+			if tt.mockRepo {
+				var pvz *domain.PVZ
+				if tt.repoErr == nil {
+					pvz = &domain.PVZ{Id: tt.pvzId, CapacityItems: tt.items, CapacityVolume: tt.volume}
+				}
+				repo.On("UpdatePVZCapacity", mock.Anything, tt.pvzId, tt.items, tt.volume).Return(pvz, tt.repoErr).Once()
+			}
+
+			pvz, err := pvzUC.SetPVZCapacity(context.Background(), tt.pvzId, tt.items, tt.volume, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, pvz)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.items, pvz.CapacityItems)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestPvzUseCase_GetAllPVZsWithReceptions(t *testing.T) {
 	repo := &repository_mocks.MockPVZRepository{}
-	pvzUC := NewPvzUseCase(repo)
+	pvzUC := NewPvzUseCase(repo, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{})
 
 	validModerator := &domain.User{
 		Id:   uuid.New(),
@@ -190,7 +285,7 @@ func TestPvzUseCase_GetAllPVZsWithReceptions(t *testing.T) {
 		{
 			name:      "Invalid role",
 			user:      &domain.User{Role: "invalid"},
-			expectErr: appErr.ErrInvalidRole,
+			expectErr: appErr.ErrPermissionDenied,
 		},
 		{
 			name:      "Error getting PVZs",
@@ -232,13 +327,16 @@ func TestPvzUseCase_GetAllPVZsWithReceptions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.pvzsErr != nil || (tt.expectErr == nil && tt.user != nil && (tt.user.Role == constants.UserRoleModerator || tt.user.Role == constants.UserRoleEmployee)) {
+			// Репозиторий вызывается только после проверки прав.
+			authorized := tt.user != nil && (tt.user.Role == constants.UserRoleModerator || tt.user.Role == constants.UserRoleEmployee)
+
+			if authorized {
 				repo.On("GetAllPVZs", mock.Anything, tt.offset, tt.limit).
 					Return(tt.pvzs, tt.pvzsErr).
 					Once()
 			}
 
-			if tt.receptionsErr != nil || (tt.expectErr == nil && tt.user != nil && tt.pvzsErr == nil) {
+			if authorized && tt.pvzsErr == nil {
 				for _, pvz := range tt.pvzs {
 					repo.On("GetReceptionsByPVZId", mock.Anything, pvz.Id, tt.startDate, tt.endDate).
 						Return(tt.receptions, tt.receptionsErr).
@@ -246,9 +344,9 @@ func TestPvzUseCase_GetAllPVZsWithReceptions(t *testing.T) {
 				}
 			}
 
-			if tt.productsErr != nil || (tt.expectErr == nil && tt.user != nil && tt.pvzsErr == nil && tt.receptionsErr == nil) {
+			if authorized && tt.pvzsErr == nil && tt.receptionsErr == nil {
 				for _, reception := range tt.receptions {
-					repo.On("GetProductsByReceptionId", mock.Anything, reception.Id).
+					repo.On("GetAllProductsFromReception", mock.Anything, reception.Id).
 						Return(tt.products, tt.productsErr).
 						Once()
 				}
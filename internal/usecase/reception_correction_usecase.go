@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"strings"
+)
+
+// ReceptionCorrectionUseCase позволяет модератору исправить закрытую приемку.
+// Каждое исправление требует причину, попадает в историю приемки и публикует то же событие,
+// что и обычная работа с приемкой.
+type ReceptionCorrectionUseCase interface {
+	// ReopenReception переоткрывает приемку, если в ПВЗ после нее не было других приемок.
+	ReopenReception(ctx context.Context, receptionId uuid.UUID, reason string, user *domain.User) error
+	AddProduct(ctx context.Context, receptionId uuid.UUID, productType, barcode, reason string, user *domain.User) (*domain.Product, error)
+	RemoveProduct(ctx context.Context, receptionId, productId uuid.UUID, reason string, user *domain.User) error
+	GetAudit(ctx context.Context, receptionId uuid.UUID, user *domain.User) ([]*domain.ReceptionAuditEntry, error)
+}
+
+type receptionCorrectionUseCase struct {
+	repo           repository.ReceptionCorrectionRepository
+	receptions     repository.ReceptionRepository
+	manifests      repository.ManifestRepository
+	products       repository.ProductRepository
+	cells          repository.StorageCellRepository
+	authorizer     authz.Authorizer
+	tx             repository.TxManager
+	outbox         repository.OutboxRepository
+	broker         EventBroker
+	capacityPolicy string
+}
+
+// NewReceptionCorrectionUseCase создает сценарии исправления приемок. capacityPolicy — как в NewProductUseCase.
+func NewReceptionCorrectionUseCase(
+	repo repository.ReceptionCorrectionRepository,
+	receptions repository.ReceptionRepository,
+	manifests repository.ManifestRepository,
+	products repository.ProductRepository,
+	cells repository.StorageCellRepository,
+	authorizer authz.Authorizer,
+	tx repository.TxManager,
+	outbox repository.OutboxRepository,
+	broker EventBroker,
+	capacityPolicy string,
+) ReceptionCorrectionUseCase {
+	return &receptionCorrectionUseCase{
+		repo:           repo,
+		receptions:     receptions,
+		manifests:      manifests,
+		products:       products,
+		cells:          cells,
+		authorizer:     authorizer,
+		tx:             tx,
+		outbox:         outbox,
+		broker:         broker,
+		capacityPolicy: capacityPolicy,
+	}
+}
+
+func (uc *receptionCorrectionUseCase) ReopenReception(ctx context.Context, receptionId uuid.UUID, reason string, user *domain.User) error {
+	reception, reason, err := uc.precheck(ctx, receptionId, reason, user)
+	if err != nil {
+		return err
+	}
+
+	var event *domain.Event
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repo.ReopenReception(ctx, receptionId, actorId(user), reason); err != nil {
+			return err
+		}
+
+		var err error
+		event, err = addEvent(ctx, uc.outbox, constants.EventReceptionReopened, reception.PVZId, receptionId, domain.ReceptionEventPayload{
+			ReceptionId:      receptionId,
+			PVZId:            reception.PVZId,
+			Direction:        reception.Direction,
+			CorrectionReason: reason,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNewerReceptionExists) {
+			return appErr.ErrNewerReceptionExists
+		}
+		return mapCorrectionError(err, appErr.ErrReopeningReception)
+	}
+
+	uc.broker.Publish(event)
+
+	return nil
+}
+
+func (uc *receptionCorrectionUseCase) AddProduct(ctx context.Context, receptionId uuid.UUID, productType, barcode, reason string, user *domain.User) (*domain.Product, error) {
+	reception, reason, err := uc.precheck(ctx, receptionId, reason, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isProductType(productType) {
+		return nil, appErr.ErrInvalidProductType
+	}
+
+	for i := 0; i < pickupCodeAttempts; i++ {
+		pickupCode, err := generatePickupCode()
+		if err != nil {
+			return nil, appErr.ErrInternal
+		}
+
+		var (
+			product *domain.Product
+			event   *domain.Event
+		)
+		err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+			// Объем при исправлении не указывается, поэтому проверяется только число товаров.
+			overCapacity, err := checkCapacity(ctx, uc.products, uc.capacityPolicy, reception.PVZId, 1, 0)
+			if err != nil {
+				return err
+			}
+
+			cell, err := pickCell(ctx, uc.cells, reception.PVZId, productType, "")
+			if err != nil {
+				return err
+			}
+
+			product, err = uc.repo.AddProduct(ctx, receptionId, productType, barcode, pickupCode, actorId(user), reason)
+			if err != nil {
+				return err
+			}
+			product.OverCapacity = overCapacity
+
+			if cell != nil {
+				if err := uc.cells.AssignProduct(ctx, product.Id, cell.Id); err != nil {
+					return err
+				}
+				product.CellId = &cell.Id
+				product.CellCode = cell.Code
+			}
+
+			payload := productEventPayload(product)
+			payload.CorrectionReason = reason
+
+			event, err = addEvent(ctx, uc.outbox, constants.EventProductAdded, product.PVZId, product.Id, payload)
+			return err
+		})
+		if errors.Is(err, repository.ErrPickupCodeTaken) {
+			continue
+		}
+		if errors.Is(err, appErr.ErrPVZCapacityExceeded) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, mapCorrectionError(err, appErr.ErrCorrectingReception)
+		}
+
+		uc.broker.Publish(event)
+
+		_, _ = generateDiscrepancyReport(ctx, uc.manifests, uc.receptions, receptionId)
+
+		return product, nil
+	}
+
+	return nil, appErr.ErrCreatingProduct
+}
+
+func (uc *receptionCorrectionUseCase) RemoveProduct(ctx context.Context, receptionId, productId uuid.UUID, reason string, user *domain.User) error {
+	_, reason, err := uc.precheck(ctx, receptionId, reason, user)
+	if err != nil {
+		return err
+	}
+
+	if productId == uuid.Nil {
+		return appErr.ErrProductIdRequired
+	}
+
+	var event *domain.Event
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		product, err := uc.repo.RemoveProduct(ctx, receptionId, productId, actorId(user), reason)
+		if err != nil {
+			return err
+		}
+
+		payload := productEventPayload(product)
+		payload.CorrectionReason = reason
+
+		event, err = addEvent(ctx, uc.outbox, constants.EventProductDeleted, product.PVZId, product.Id, payload)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrProductsUnavailable) {
+			return appErr.ErrProductNotInReception
+		}
+		return mapCorrectionError(err, appErr.ErrCorrectingReception)
+	}
+
+	uc.broker.Publish(event)
+
+	_, _ = generateDiscrepancyReport(ctx, uc.manifests, uc.receptions, receptionId)
+
+	return nil
+}
+
+func (uc *receptionCorrectionUseCase) GetAudit(ctx context.Context, receptionId uuid.UUID, user *domain.User) ([]*domain.ReceptionAuditEntry, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermReportRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.getReception(ctx, receptionId); err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.repo.GetAudit(ctx, receptionId)
+	if err != nil {
+		return nil, appErr.ErrGettingReceptionAudit
+	}
+
+	return entries, nil
+}
+
+// precheck проверяет право, причину и то, что приемка — закрытая входящая. Возвращает приемку и очищенную причину.
+func (uc *receptionCorrectionUseCase) precheck(ctx context.Context, receptionId uuid.UUID, reason string, user *domain.User) (*domain.Reception, string, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermReceptionCorrect); err != nil {
+		return nil, "", err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, "", appErr.ErrCorrectionReasonRequired
+	}
+
+	reception, err := uc.getReception(ctx, receptionId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if reception.Direction == constants.ReceptionDirectionReturn {
+		return nil, "", appErr.ErrReceptionNotCorrectable
+	}
+
+	if reception.Status != constants.ReceptionStatusClose {
+		return nil, "", appErr.ErrReceptionNotClosed
+	}
+
+	return reception, reason, nil
+}
+
+func (uc *receptionCorrectionUseCase) getReception(ctx context.Context, receptionId uuid.UUID) (*domain.Reception, error) {
+	reception, err := uc.receptions.GetReceptionById(ctx, receptionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrReceptionNotFound
+		}
+		return nil, appErr.ErrGettingReceptions
+	}
+
+	return reception, nil
+}
+
+// mapCorrectionError переводит ошибки, возможные при гонке с другим исправлением.
+func mapCorrectionError(err, fallback error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return appErr.ErrReceptionNotFound
+	case errors.Is(err, repository.ErrReceptionNotClosed):
+		return appErr.ErrReceptionNotClosed
+	default:
+		return fallback
+	}
+}
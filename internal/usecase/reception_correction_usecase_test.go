@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReceptionCorrectionUseCase_ReopenReception(t *testing.T) {
+	receptionId := uuid.New()
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	closed := &domain.Reception{Id: receptionId, PVZId: uuid.New(), Status: constants.ReceptionStatusClose, Direction: constants.ReceptionDirectionInbound}
+
+	tests := []struct {
+		name       string
+		user       *domain.User
+		reason     string
+		reception  *domain.Reception
+		getErr     error
+		reopenErr  error
+		callReopen bool
+		expectErr  error
+	}{
+		{
+			name:       "Valid reopen",
+			user:       moderator,
+			reason:     " wrong close ",
+			reception:  closed,
+			callReopen: true,
+		},
+		{
+			name:      "Employee is not allowed",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			reason:    "wrong close",
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Empty reason",
+			user:      moderator,
+			reason:    "  ",
+			expectErr: appErr.ErrCorrectionReasonRequired,
+		},
+		{
+			name:      "Reception not found",
+			user:      moderator,
+			reason:    "wrong close",
+			getErr:    pgx.ErrNoRows,
+			expectErr: appErr.ErrReceptionNotFound,
+		},
+		{
+			name:      "Reception is open",
+			user:      moderator,
+			reason:    "wrong close",
+			reception: &domain.Reception{Id: receptionId, Status: constants.ReceptionStatusInProgress, Direction: constants.ReceptionDirectionInbound},
+			expectErr: appErr.ErrReceptionNotClosed,
+		},
+		{
+			name:      "Return reception",
+			user:      moderator,
+			reason:    "wrong close",
+			reception: &domain.Reception{Id: receptionId, Status: constants.ReceptionStatusClose, Direction: constants.ReceptionDirectionReturn},
+			expectErr: appErr.ErrReceptionNotCorrectable,
+		},
+		{
+			name:       "Newer reception exists",
+			user:       moderator,
+			reason:     "wrong close",
+			reception:  closed,
+			reopenErr:  repository.ErrNewerReceptionExists,
+			callReopen: true,
+			expectErr:  appErr.ErrNewerReceptionExists,
+		},
+		{
+			name:       "Repository error",
+			user:       moderator,
+			reason:     "wrong close",
+			reception:  closed,
+			reopenErr:  errors.New("db error"),
+			callReopen: true,
+			expectErr:  appErr.ErrReopeningReception,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockReceptionCorrectionRepository{}
+			receptions := &repository_mocks.MockReceptionRepository{}
+			outbox := &repository_mocks.MockOutboxRepository{}
+			correctionUC := NewReceptionCorrectionUseCase(
+				repo, receptions, &repository_mocks.MockManifestRepository{}, &repository_mocks.MockProductRepository{}, newStorageCellMock(),
+				authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, outbox, broker.New(0), constants.CapacityPolicyReject,
+			)
+
+			if tt.reception != nil || tt.getErr != nil {
+				receptions.On("GetReceptionById", mock.Anything, receptionId).Return(tt.reception, tt.getErr).Once()
+			}
+			if tt.callReopen {
+				repo.On("ReopenReception", mock.Anything, receptionId, tt.user.Id, "wrong close").Return(tt.reopenErr).Once()
+			}
+			if tt.callReopen && tt.reopenErr == nil {
+				outbox.On("Add", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+					return event.Type == constants.EventReceptionReopened && event.PVZId == closed.PVZId && event.AggregateId == receptionId
+				})).Return(nil).Once()
+			}
+
+			err := correctionUC.ReopenReception(context.Background(), receptionId, tt.reason, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+			receptions.AssertExpectations(t)
+			outbox.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReceptionCorrectionUseCase_AddProduct(t *testing.T) {
+	receptionId := uuid.New()
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	closed := &domain.Reception{Id: receptionId, PVZId: uuid.New(), Status: constants.ReceptionStatusClose, Direction: constants.ReceptionDirectionInbound}
+	product := &domain.Product{Id: uuid.New(), ReceptionId: receptionId, PVZId: closed.PVZId, Type: constants.ProductTypeShoes, Status: constants.ProductStatusStored}
+	cell := &domain.StorageCell{Id: uuid.New(), Code: "A-1"}
+
+	repo := &repository_mocks.MockReceptionCorrectionRepository{}
+	receptions := &repository_mocks.MockReceptionRepository{}
+	manifests := &repository_mocks.MockManifestRepository{}
+	manifests.On("GetManifestByReceptionId", mock.Anything, receptionId).Return(nil, pgx.ErrNoRows)
+	cells := &repository_mocks.MockStorageCellRepository{}
+	outbox := &repository_mocks.MockOutboxRepository{}
+	products := &repository_mocks.MockProductRepository{}
+	correctionUC := NewReceptionCorrectionUseCase(
+		repo, receptions, manifests, products, cells, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, outbox, broker.New(0),
+		constants.CapacityPolicyReject,
+	)
+
+	receptions.On("GetReceptionById", mock.Anything, receptionId).Return(closed, nil)
+	repo.On("AddProduct", mock.Anything, receptionId, "обувь", "B-1", mock.AnythingOfType("string"), moderator.Id, "lost in scan").
+		Return(nil, repository.ErrPickupCodeTaken).Once()
+	repo.On("AddProduct", mock.Anything, receptionId, "обувь", "B-1", mock.AnythingOfType("string"), moderator.Id, "lost in scan").
+		Return(product, nil).Once()
+
+	// Вместимость проверяется, товар кладется в ячейку, как при обычной приемке, и публикуется событие с причиной.
+	capacity := 5
+	products.On("LockPVZCapacity", mock.Anything, closed.PVZId).
+		Return(&domain.PVZ{Id: closed.PVZId, CapacityItems: &capacity, Occupancy: &domain.PVZOccupancy{Items: 3}}, nil).Twice()
+	cells.On("SuggestCell", mock.Anything, closed.PVZId, "обувь").Return(cell, nil).Twice()
+	cells.On("AssignProduct", mock.Anything, product.Id, cell.Id).Return(nil).Once()
+
+	var event *domain.Event
+	outbox.On("Add", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(*domain.Event)
+	}).Return(nil).Once()
+
+	result, err := correctionUC.AddProduct(context.Background(), receptionId, "обувь", "B-1", "lost in scan", moderator)
+
+	assert.NoError(t, err)
+	assert.Equal(t, product, result)
+	assert.Equal(t, "A-1", result.CellCode)
+	assert.Equal(t, constants.EventProductAdded, event.Type)
+
+	var payload map[string]any
+	assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, "lost in scan", payload["correction_reason"])
+
+	repo.AssertExpectations(t)
+	cells.AssertExpectations(t)
+	manifests.AssertNumberOfCalls(t, "GetManifestByReceptionId", 1)
+
+	_, err = correctionUC.AddProduct(context.Background(), receptionId, "мебель", "", "lost in scan", moderator)
+	assert.ErrorIs(t, err, appErr.ErrInvalidProductType)
+
+	// ПВЗ заполнен: по политике reject товар не добавляется.
+	products.On("LockPVZCapacity", mock.Anything, closed.PVZId).
+		Return(&domain.PVZ{Id: closed.PVZId, CapacityItems: &capacity, Occupancy: &domain.PVZOccupancy{Items: capacity}}, nil).Once()
+
+	_, err = correctionUC.AddProduct(context.Background(), receptionId, "обувь", "B-2", "lost in scan", moderator)
+	assert.ErrorIs(t, err, appErr.ErrPVZCapacityExceeded)
+	products.AssertExpectations(t)
+}
+
+func TestReceptionCorrectionUseCase_RemoveProduct(t *testing.T) {
+	receptionId := uuid.New()
+	productId := uuid.New()
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	closed := &domain.Reception{Id: receptionId, PVZId: uuid.New(), Status: constants.ReceptionStatusClose, Direction: constants.ReceptionDirectionInbound}
+
+	tests := []struct {
+		name      string
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name: "Valid removal",
+		},
+		{
+			name:      "Product is not in reception",
+			repoErr:   repository.ErrProductsUnavailable,
+			expectErr: appErr.ErrProductNotInReception,
+		},
+		{
+			name:      "Reception reopened concurrently",
+			repoErr:   repository.ErrReceptionNotClosed,
+			expectErr: appErr.ErrReceptionNotClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockReceptionCorrectionRepository{}
+			receptions := &repository_mocks.MockReceptionRepository{}
+			manifests := &repository_mocks.MockManifestRepository{}
+			manifests.On("GetManifestByReceptionId", mock.Anything, receptionId).Return(nil, pgx.ErrNoRows)
+			outbox := &repository_mocks.MockOutboxRepository{}
+			correctionUC := NewReceptionCorrectionUseCase(
+				repo, receptions, manifests, &repository_mocks.MockProductRepository{}, newStorageCellMock(),
+				authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, outbox, broker.New(0), constants.CapacityPolicyReject,
+			)
+
+			var removed *domain.Product
+			if tt.repoErr == nil {
+				removed = &domain.Product{Id: productId, ReceptionId: receptionId, PVZId: closed.PVZId, Status: constants.ProductStatusRemovedByCorrection}
+				outbox.On("Add", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+					return event.Type == constants.EventProductDeleted && event.AggregateId == productId
+				})).Return(nil).Once()
+			}
+
+			receptions.On("GetReceptionById", mock.Anything, receptionId).Return(closed, nil).Once()
+			repo.On("RemoveProduct", mock.Anything, receptionId, productId, moderator.Id, "duplicate scan").Return(removed, tt.repoErr).Once()
+
+			err := correctionUC.RemoveProduct(context.Background(), receptionId, productId, "duplicate scan", moderator)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+			outbox.AssertExpectations(t)
+		})
+	}
+}
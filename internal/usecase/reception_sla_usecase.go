@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"sort"
+	"time"
+)
+
+// defaultReceptionSLA действует, если длительность приемки не задана в конфиге.
+const defaultReceptionSLA = 2 * time.Hour
+
+// ReceptionSLAUseCase следит за длительностью открытых входящих приемок.
+type ReceptionSLAUseCase interface {
+	// GetOpenReceptions возвращает все открытые приемки с SLA их города; вызывается фоновым сбором метрик.
+	GetOpenReceptions(ctx context.Context) ([]*domain.OpenReception, error)
+	// GetSLABreaches возвращает приемки, открытые дольше SLA, самые просроченные первыми.
+	// Пустой city — все города.
+	GetSLABreaches(ctx context.Context, city string, user *domain.User) ([]*domain.OpenReception, error)
+}
+
+type receptionSLAUseCase struct {
+	repo       repository.ReceptionRepository
+	authorizer authz.Authorizer
+	sla        domain.ReceptionSLA
+}
+
+func NewReceptionSLAUseCase(repo repository.ReceptionRepository, authorizer authz.Authorizer, sla domain.ReceptionSLA) ReceptionSLAUseCase {
+	if sla.Default <= 0 {
+		sla.Default = defaultReceptionSLA
+	}
+
+	return &receptionSLAUseCase{
+		repo:       repo,
+		authorizer: authorizer,
+		sla:        sla,
+	}
+}
+
+func (uc *receptionSLAUseCase) GetOpenReceptions(ctx context.Context) ([]*domain.OpenReception, error) {
+	receptions, err := uc.repo.GetOpenReceptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reception := range receptions {
+		reception.SLASeconds = int64(uc.sla.For(reception.City) / time.Second)
+		reception.OverdueSeconds = max(reception.OpenSeconds-reception.SLASeconds, 0)
+	}
+
+	return receptions, nil
+}
+
+func (uc *receptionSLAUseCase) GetSLABreaches(ctx context.Context, city string, user *domain.User) ([]*domain.OpenReception, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermReportRead); err != nil {
+		return nil, err
+	}
+
+	if city != "" && !isPVZCity(city) {
+		return nil, appErr.ErrInvalidCity
+	}
+
+	receptions, err := uc.GetOpenReceptions(ctx)
+	if err != nil {
+		return nil, appErr.ErrGettingSLABreaches
+	}
+
+	breaches := []*domain.OpenReception{}
+	for _, reception := range receptions {
+		if reception.OverdueSeconds > 0 && (city == "" || reception.City == city) {
+			breaches = append(breaches, reception)
+		}
+	}
+
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].OverdueSeconds > breaches[j].OverdueSeconds
+	})
+
+	return breaches, nil
+}
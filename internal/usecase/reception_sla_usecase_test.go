@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReceptionSLAUseCase_GetSLABreaches(t *testing.T) {
+	moscowLate := uuid.New()
+	kazanLate := uuid.New()
+	moscowOnTime := uuid.New()
+
+	openReceptions := func() []*domain.OpenReception {
+		return []*domain.OpenReception{
+			// SLA Москвы — 1 час: просрочка 30 минут.
+			{ReceptionId: moscowLate, City: constants.PVZCityMoscow, OpenSeconds: 5400},
+			// SLA по умолчанию — 2 часа: просрочка 1 час.
+			{ReceptionId: kazanLate, City: constants.PVZCityKazan, OpenSeconds: 10800},
+			{ReceptionId: moscowOnTime, City: constants.PVZCityMoscow, OpenSeconds: 1800},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		city      string
+		user      *domain.User
+		repoErr   error
+		expectIds []uuid.UUID
+		expectErr error
+	}{
+		{
+			name:      "All cities, most overdue first",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleAuditor},
+			expectIds: []uuid.UUID{kazanLate, moscowLate},
+		},
+		{
+			name:      "Filter by city",
+			city:      constants.PVZCityMoscow,
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			expectIds: []uuid.UUID{moscowLate},
+		},
+		{
+			name:      "Invalid city",
+			city:      "Новосибирск",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			expectErr: appErr.ErrInvalidCity,
+		},
+		{
+			name:      "Employee is not allowed",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Repository error",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrGettingSLABreaches,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockReceptionRepository{}
+			if tt.repoErr != nil {
+				repo.On("GetOpenReceptions", mock.Anything).Return(nil, tt.repoErr)
+			} else {
+				repo.On("GetOpenReceptions", mock.Anything).Return(openReceptions(), nil)
+			}
+
+			// Ключи карты из конфига приходят в нижнем регистре.
+			uc := NewReceptionSLAUseCase(repo, authz.New(authz.DefaultRoles()), domain.ReceptionSLA{
+				ByCity: map[string]time.Duration{"москва": time.Hour},
+			})
+
+			breaches, err := uc.GetSLABreaches(context.Background(), tt.city, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			ids := make([]uuid.UUID, 0, len(breaches))
+			for _, breach := range breaches {
+				ids = append(ids, breach.ReceptionId)
+				assert.Positive(t, breach.OverdueSeconds)
+			}
+			assert.Equal(t, tt.expectIds, ids)
+		})
+	}
+}
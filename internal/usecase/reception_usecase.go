@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
@@ -13,69 +16,234 @@ import (
 type ReceptionUseCase interface {
 	CreateReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*domain.Reception, error)
 	CloseLastReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) error
+	CreateReturnReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*domain.Reception, error)
+	AddProductToReturn(ctx context.Context, pvzId, productId uuid.UUID, user *domain.User) error
+	CloseLastReturn(ctx context.Context, pvzId uuid.UUID, user *domain.User) error
+	// CloseStaleReceptions закрывает входящие приемки без поступлений дольше idleFor; вызывается планировщиком.
+	CloseStaleReceptions(ctx context.Context, idleFor time.Duration) (int, error)
 }
 
 type receptionUseCase struct {
-	repo repository.ReceptionRepository
+	repo        repository.ReceptionRepository
+	manifests   repository.ManifestRepository
+	assignments repository.AssignmentRepository
+	authorizer  authz.Authorizer
+	tx          repository.TxManager
+	outbox      repository.OutboxRepository
+	broker      EventBroker
 }
 
-func NewReceptionUseCase(repo repository.ReceptionRepository) ReceptionUseCase {
-	return &receptionUseCase{repo: repo}
+func NewReceptionUseCase(
+	repo repository.ReceptionRepository,
+	manifests repository.ManifestRepository,
+	assignments repository.AssignmentRepository,
+	authorizer authz.Authorizer,
+	tx repository.TxManager,
+	outbox repository.OutboxRepository,
+	broker EventBroker,
+) ReceptionUseCase {
+	return &receptionUseCase{
+		repo:        repo,
+		manifests:   manifests,
+		assignments: assignments,
+		authorizer:  authorizer,
+		tx:          tx,
+		outbox:      outbox,
+		broker:      broker,
+	}
 }
 
 func (uc *receptionUseCase) CreateReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*domain.Reception, error) {
-	if user == nil {
-		return nil, appErr.ErrUserRequired
-	}
+	return uc.openReception(ctx, pvzId, constants.ReceptionDirectionInbound, user)
+}
 
-	if user.Role != constants.UserRoleEmployee {
-		return nil, appErr.ErrOnlyEmployeeAllowed
-	}
+func (uc *receptionUseCase) CreateReturnReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) (*domain.Reception, error) {
+	return uc.openReception(ctx, pvzId, constants.ReceptionDirectionReturn, user)
+}
 
-	if pvzId == uuid.Nil {
-		return nil, appErr.ErrPVZIdRequired
+// openReception открывает приемку заданного направления; у ПВЗ может быть по одной открытой приемке каждого направления.
+func (uc *receptionUseCase) openReception(ctx context.Context, pvzId uuid.UUID, direction string, user *domain.User) (*domain.Reception, error) {
+	if err := uc.checkAccess(ctx, pvzId, authz.PermReceptionOpen, user); err != nil {
+		return nil, err
 	}
 
-	hasOpen, err := uc.repo.HasOpenReception(ctx, pvzId)
+	hasOpen, err := uc.repo.HasOpenReception(ctx, pvzId, direction)
 	if err != nil {
 		return nil, err
 	}
 
 	if hasOpen {
+		if direction == constants.ReceptionDirectionReturn {
+			return nil, appErr.ErrPVZHasOpenReturn
+		}
 		return nil, appErr.ErrPVZHasOpenReception
 	}
 
 	reception := &domain.Reception{
-		PVZId:    pvzId,
-		Status:   constants.ReceptionStatusInProgress,
-		DateTime: time.Now(),
+		PVZId:     pvzId,
+		Status:    constants.ReceptionStatusInProgress,
+		Direction: direction,
+		DateTime:  time.Now(),
 	}
 
-	err = uc.repo.CreateReception(ctx, reception)
+	var event *domain.Event
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repo.CreateReception(ctx, reception); err != nil {
+			return err
+		}
+
+		event, err = addEvent(ctx, uc.outbox, constants.EventReceptionOpened, pvzId, reception.Id, domain.ReceptionEventPayload{
+			ReceptionId: reception.Id,
+			PVZId:       pvzId,
+			Direction:   direction,
+		})
+		return err
+	})
 	if err != nil {
 		return nil, appErr.ErrCreatingReception
 	}
 
+	uc.broker.Publish(event)
+
 	return reception, nil
 }
 
 func (uc *receptionUseCase) CloseLastReception(ctx context.Context, pvzId uuid.UUID, user *domain.User) error {
-	if user == nil {
-		return appErr.ErrUserRequired
+	if err := uc.checkAccess(ctx, pvzId, authz.PermReceptionClose, user); err != nil {
+		return err
 	}
 
-	if user.Role != constants.UserRoleEmployee {
-		return appErr.ErrOnlyEmployeeAllowed
+	var receptionId uuid.UUID
+	var event *domain.Event
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		receptionId, err = uc.repo.CloseLastReception(ctx, pvzId)
+		if err != nil {
+			return err
+		}
+
+		event, err = addEvent(ctx, uc.outbox, constants.EventReceptionClosed, pvzId, receptionId, domain.ReceptionEventPayload{
+			ReceptionId: receptionId,
+			PVZId:       pvzId,
+			Direction:   constants.ReceptionDirectionInbound,
+		})
+		return err
+	})
+	if err != nil {
+		return appErr.ErrClosingLastReception
 	}
 
-	if pvzId == uuid.Nil {
-		return appErr.ErrPVZIdRequired
+	uc.broker.Publish(event)
+
+	// Приемка уже закрыта, поэтому ошибка построения отчета не возвращается:
+	// несохраненный отчет будет построен при первом запросе.
+	_, _ = generateDiscrepancyReport(ctx, uc.manifests, uc.repo, receptionId)
+
+	return nil
+}
+
+func (uc *receptionUseCase) CloseStaleReceptions(ctx context.Context, idleFor time.Duration) (int, error) {
+	reason := fmt.Sprintf("no activity for %s", idleFor)
+
+	var closed []*domain.Reception
+	var events []*domain.Event
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		closed, err = uc.repo.CloseStaleReceptions(ctx, idleFor, reason)
+		if err != nil {
+			return err
+		}
+
+		events = make([]*domain.Event, 0, len(closed))
+		for _, reception := range closed {
+			event, err := addEvent(ctx, uc.outbox, constants.EventReceptionClosed, reception.PVZId, reception.Id, domain.ReceptionEventPayload{
+				ReceptionId: reception.Id,
+				PVZId:       reception.PVZId,
+				Direction:   constants.ReceptionDirectionInbound,
+				AutoClosed:  true,
+				CloseReason: reason,
+			})
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	err := uc.repo.CloseLastReception(ctx, pvzId)
+	uc.broker.Publish(events...)
+
+	for _, reception := range closed {
+		_, _ = generateDiscrepancyReport(ctx, uc.manifests, uc.repo, reception.Id)
+	}
+
+	return len(closed), nil
+}
+
+func (uc *receptionUseCase) AddProductToReturn(ctx context.Context, pvzId, productId uuid.UUID, user *domain.User) error {
+	if err := uc.checkAccess(ctx, pvzId, authz.PermProductReturn, user); err != nil {
+		return err
+	}
+
+	if productId == uuid.Nil {
+		return appErr.ErrProductIdRequired
+	}
+
+	err := uc.repo.AddProductToReturn(ctx, pvzId, productId, actorId(user))
 	if err != nil {
-		return appErr.ErrClosingLastReception
+		switch {
+		case errors.Is(err, repository.ErrNoOpenReception):
+			return appErr.ErrNoOpenReturn
+		case errors.Is(err, repository.ErrProductsUnavailable):
+			return appErr.ErrProductNotStored
+		default:
+			return appErr.ErrAddingProductToReturn
+		}
+	}
+
+	return nil
+}
+
+func (uc *receptionUseCase) CloseLastReturn(ctx context.Context, pvzId uuid.UUID, user *domain.User) error {
+	if err := uc.checkAccess(ctx, pvzId, authz.PermReceptionClose, user); err != nil {
+		return err
+	}
+
+	var event *domain.Event
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		receptionId, err := uc.repo.CloseLastReturn(ctx, pvzId)
+		if err != nil {
+			return err
+		}
+
+		event, err = addEvent(ctx, uc.outbox, constants.EventReceptionClosed, pvzId, receptionId, domain.ReceptionEventPayload{
+			ReceptionId: receptionId,
+			PVZId:       pvzId,
+			Direction:   constants.ReceptionDirectionReturn,
+		})
+		return err
+	})
+	if err != nil {
+		return appErr.ErrClosingLastReturn
 	}
 
+	uc.broker.Publish(event)
+
 	return nil
 }
+
+func (uc *receptionUseCase) checkAccess(ctx context.Context, pvzId uuid.UUID, perm authz.Permission, user *domain.User) error {
+	if err := uc.authorizer.Authorize(user, perm); err != nil {
+		return err
+	}
+
+	if pvzId == uuid.Nil {
+		return appErr.ErrPVZIdRequired
+	}
+
+	return checkPVZAccess(ctx, uc.assignments, user, pvzId)
+}
@@ -6,18 +6,24 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aliskhannn/pvz-service/internal/authz"
 	"github.com/aliskhannn/pvz-service/internal/constants"
 	"github.com/aliskhannn/pvz-service/internal/domain"
 	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	"github.com/aliskhannn/pvz-service/internal/repository"
 	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestReceptionUseCase_CreateReception(t *testing.T) {
 	repo := &repository_mocks.MockReceptionRepository{}
-	receptionUC := NewReceptionUseCase(repo)
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	assignments.On("IsAssigned", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	receptionUC := NewReceptionUseCase(repo, &repository_mocks.MockManifestRepository{}, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0))
 
 	validUser := &domain.User{
 		Id:   uuid.New(),
@@ -25,6 +31,7 @@ func TestReceptionUseCase_CreateReception(t *testing.T) {
 	}
 
 	validPVZID := uuid.New()
+	dbErr := errors.New("db error")
 	validReception := &domain.Reception{
 		PVZId:    validPVZID,
 		Status:   constants.ReceptionStatusInProgress,
@@ -61,7 +68,7 @@ func TestReceptionUseCase_CreateReception(t *testing.T) {
 			name:      "Non-employee user",
 			pvzId:     validPVZID,
 			user:      &domain.User{Role: constants.UserRoleModerator},
-			expectErr: appErr.ErrOnlyEmployeeAllowed,
+			expectErr: appErr.ErrPermissionDenied,
 		},
 		{
 			name:      "Nil PVZ ID",
@@ -81,8 +88,8 @@ func TestReceptionUseCase_CreateReception(t *testing.T) {
 			name:       "Error checking open reception",
 			pvzId:      validPVZID,
 			user:       validUser,
-			hasOpenErr: errors.New("db error"),
-			expectErr:  errors.New("db error"),
+			hasOpenErr: dbErr,
+			expectErr:  dbErr,
 		},
 		{
 			name:       "Error creating reception",
@@ -97,13 +104,16 @@ func TestReceptionUseCase_CreateReception(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.hasOpenErr != nil || (tt.expectErr == nil && tt.user != nil && tt.user.Role == constants.UserRoleEmployee && tt.pvzId != uuid.Nil) {
-				repo.On("HasOpenReception", mock.Anything, tt.pvzId).
+			// Репозиторий вызывается только после проверки прав и идентификатора ПВЗ.
+			checked := tt.user != nil && tt.user.Role == constants.UserRoleEmployee && tt.pvzId != uuid.Nil
+
+			if checked {
+				repo.On("HasOpenReception", mock.Anything, tt.pvzId, constants.ReceptionDirectionInbound).
 					Return(tt.hasOpen, tt.hasOpenErr).
 					Once()
 			}
 
-			if tt.createErr != nil || (tt.expectErr == nil && tt.user != nil && tt.user.Role == constants.UserRoleEmployee && tt.pvzId != uuid.Nil && !tt.hasOpen) {
+			if checked && tt.hasOpenErr == nil && !tt.hasOpen {
 				repo.On("CreateReception", mock.Anything, mock.MatchedBy(func(r *domain.Reception) bool {
 					return r.PVZId == tt.pvzId && r.Status == constants.ReceptionStatusInProgress
 				})).
@@ -130,7 +140,11 @@ func TestReceptionUseCase_CreateReception(t *testing.T) {
 
 func TestReceptionUseCase_CloseLastReception(t *testing.T) {
 	repo := &repository_mocks.MockReceptionRepository{}
-	receptionUC := NewReceptionUseCase(repo)
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	assignments.On("IsAssigned", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	manifests := &repository_mocks.MockManifestRepository{}
+	manifests.On("GetManifestByReceptionId", mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows)
+	receptionUC := NewReceptionUseCase(repo, manifests, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0))
 
 	validUser := &domain.User{
 		Id:   uuid.New(),
@@ -163,7 +177,7 @@ func TestReceptionUseCase_CloseLastReception(t *testing.T) {
 			name:      "Non-employee user",
 			pvzId:     validPVZID,
 			user:      &domain.User{Role: constants.UserRoleModerator},
-			expectErr: appErr.ErrOnlyEmployeeAllowed,
+			expectErr: appErr.ErrPermissionDenied,
 		},
 		{
 			name:      "Nil PVZ ID",
@@ -184,7 +198,7 @@ func TestReceptionUseCase_CloseLastReception(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.closeErr != nil || (tt.expectErr == nil && tt.user != nil && tt.user.Role == constants.UserRoleEmployee && tt.pvzId != uuid.Nil) {
 				repo.On("CloseLastReception", mock.Anything, tt.pvzId).
-					Return(tt.closeErr).
+					Return(uuid.New(), tt.closeErr).
 					Once()
 			}
 
@@ -200,3 +214,185 @@ func TestReceptionUseCase_CloseLastReception(t *testing.T) {
 		})
 	}
 }
+
+func TestReceptionUseCase_CreateReturnReception(t *testing.T) {
+	pvzId := uuid.New()
+	user := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+
+	tests := []struct {
+		name      string
+		hasOpen   bool
+		expectErr error
+	}{
+		{
+			name: "Valid return creation",
+		},
+		{
+			name:      "Has open return",
+			hasOpen:   true,
+			expectErr: appErr.ErrPVZHasOpenReturn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockReceptionRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			assignments.On("IsAssigned", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+			receptionUC := NewReceptionUseCase(repo, &repository_mocks.MockManifestRepository{}, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0))
+
+			repo.On("HasOpenReception", mock.Anything, pvzId, constants.ReceptionDirectionReturn).Return(tt.hasOpen, nil).Once()
+			if !tt.hasOpen {
+				repo.On("CreateReception", mock.Anything, mock.MatchedBy(func(r *domain.Reception) bool {
+					return r.PVZId == pvzId && r.Direction == constants.ReceptionDirectionReturn
+				})).Return(nil).Once()
+			}
+
+			result, err := receptionUC.CreateReturnReception(context.Background(), pvzId, user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, constants.ReceptionDirectionReturn, result.Direction)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReceptionUseCase_AddProductToReturn(t *testing.T) {
+	pvzId := uuid.New()
+	productId := uuid.New()
+	user := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+
+	tests := []struct {
+		name      string
+		productId uuid.UUID
+		user      *domain.User
+		repoErr   error
+		expectErr error
+	}{
+		{
+			name:      "Valid product",
+			productId: productId,
+			user:      user,
+		},
+		{
+			name:      "Moderator is not allowed",
+			productId: productId,
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Nil product ID",
+			productId: uuid.Nil,
+			user:      user,
+			expectErr: appErr.ErrProductIdRequired,
+		},
+		{
+			name:      "No open return",
+			productId: productId,
+			user:      user,
+			repoErr:   repository.ErrNoOpenReception,
+			expectErr: appErr.ErrNoOpenReturn,
+		},
+		{
+			name:      "Product is not stored",
+			productId: productId,
+			user:      user,
+			repoErr:   repository.ErrProductsUnavailable,
+			expectErr: appErr.ErrProductNotStored,
+		},
+		{
+			name:      "Repository error",
+			productId: productId,
+			user:      user,
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrAddingProductToReturn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockReceptionRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			assignments.On("IsAssigned", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+			receptionUC := NewReceptionUseCase(repo, &repository_mocks.MockManifestRepository{}, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0))
+
+			if tt.repoErr != nil || tt.expectErr == nil {
+				repo.On("AddProductToReturn", mock.Anything, pvzId, tt.productId, tt.user.Id).Return(tt.repoErr).Once()
+			}
+
+			err := receptionUC.AddProductToReturn(context.Background(), pvzId, tt.productId, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReceptionUseCase_CloseLastReturn(t *testing.T) {
+	pvzId := uuid.New()
+	user := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+
+	tests := []struct {
+		name      string
+		closeErr  error
+		expectErr error
+	}{
+		{
+			name: "Valid return closure",
+		},
+		{
+			name:      "Error closing return",
+			closeErr:  errors.New("no active return"),
+			expectErr: appErr.ErrClosingLastReturn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockReceptionRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			assignments.On("IsAssigned", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+			receptionUC := NewReceptionUseCase(repo, &repository_mocks.MockManifestRepository{}, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0))
+
+			repo.On("CloseLastReturn", mock.Anything, pvzId).Return(uuid.New(), tt.closeErr).Once()
+
+			err := receptionUC.CloseLastReturn(context.Background(), pvzId, user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReceptionUseCase_CloseStaleReceptions(t *testing.T) {
+	repo := &repository_mocks.MockReceptionRepository{}
+	manifests := &repository_mocks.MockManifestRepository{}
+	manifests.On("GetManifestByReceptionId", mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows)
+	receptionUC := NewReceptionUseCase(repo, manifests, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0))
+
+	closed := []*domain.Reception{{Id: uuid.New(), PVZId: uuid.New()}, {Id: uuid.New(), PVZId: uuid.New()}}
+	repo.On("CloseStaleReceptions", mock.Anything, 12*time.Hour, "no activity for 12h0m0s").Return(closed, nil).Once()
+
+	count, err := receptionUC.CloseStaleReceptions(context.Background(), 12*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	manifests.AssertNumberOfCalls(t, "GetManifestByReceptionId", 2)
+	repo.AssertExpectations(t)
+}
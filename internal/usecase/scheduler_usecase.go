@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+)
+
+// JobStatusProvider отдает состояние фоновых задач; реализуется планировщиком.
+type JobStatusProvider interface {
+	Statuses() []domain.JobStatus
+}
+
+type SchedulerUseCase interface {
+	GetJobs(ctx context.Context, user *domain.User) ([]domain.JobStatus, error)
+}
+
+type schedulerUseCase struct {
+	jobs       JobStatusProvider
+	authorizer authz.Authorizer
+}
+
+func NewSchedulerUseCase(jobs JobStatusProvider, authorizer authz.Authorizer) SchedulerUseCase {
+	return &schedulerUseCase{
+		jobs:       jobs,
+		authorizer: authorizer,
+	}
+}
+
+func (uc *schedulerUseCase) GetJobs(ctx context.Context, user *domain.User) ([]domain.JobStatus, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermSchedulerRead); err != nil {
+		return nil, err
+	}
+
+	return uc.jobs.Statuses(), nil
+}
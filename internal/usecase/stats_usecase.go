@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"math"
+	"sync"
+	"time"
+)
+
+// defaultReportPeriod — период отчетов и выгрузок, если даты не заданы.
+const defaultReportPeriod = 30 * 24 * time.Hour
+
+// maxStatsPeriod ограничивает период отчета, чтобы число строк ответа оставалось разумным.
+var maxStatsPeriod = map[string]time.Duration{
+	constants.StatsBucketDay:   366 * 24 * time.Hour,
+	constants.StatsBucketWeek:  2 * 366 * 24 * time.Hour,
+	constants.StatsBucketMonth: 5 * 366 * 24 * time.Hour,
+}
+
+// StatsUseCase отдает аналитику по поступлению товаров. Результаты кешируются на ttl,
+// так как отчеты строятся агрегатами по всем приемкам.
+type StatsUseCase interface {
+	// GetIntake считает приемки и товары за даты [from, to] включительно. Пустые значения —
+	// группировка по городу, разбивка по дням и последние 30 дней.
+	GetIntake(ctx context.Context, groupBy, bucket string, from, to time.Time, user *domain.User) ([]*domain.IntakeStat, error)
+	// GetProductivity возвращает работу сотрудников по ПВЗ за даты [from, to] включительно
+	// (по умолчанию последние 30 дней). Нулевой pvzId — все ПВЗ. Не кешируется.
+	GetProductivity(ctx context.Context, from, to time.Time, pvzId uuid.UUID, user *domain.User) ([]*domain.EmployeeProductivity, error)
+}
+
+type intakeCacheEntry struct {
+	stats     []*domain.IntakeStat
+	expiresAt time.Time
+}
+
+type statsUseCase struct {
+	repo       repository.StatsRepository
+	authorizer authz.Authorizer
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[domain.IntakeFilter]intakeCacheEntry
+}
+
+func NewStatsUseCase(repo repository.StatsRepository, authorizer authz.Authorizer, ttl time.Duration) StatsUseCase {
+	return &statsUseCase{
+		repo:       repo,
+		authorizer: authorizer,
+		ttl:        ttl,
+		now:        time.Now,
+		cache:      make(map[domain.IntakeFilter]intakeCacheEntry),
+	}
+}
+
+func (uc *statsUseCase) GetIntake(ctx context.Context, groupBy, bucket string, from, to time.Time, user *domain.User) ([]*domain.IntakeStat, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermStatsRead); err != nil {
+		return nil, err
+	}
+
+	filter, err := uc.intakeFilter(groupBy, bucket, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats, ok := uc.cached(filter); ok {
+		return stats, nil
+	}
+
+	stats, err := uc.repo.GetIntake(ctx, filter)
+	if err != nil {
+		return nil, appErr.ErrGettingStats
+	}
+
+	if stats == nil {
+		stats = []*domain.IntakeStat{}
+	}
+
+	uc.store(filter, stats)
+
+	return stats, nil
+}
+
+func (uc *statsUseCase) GetProductivity(ctx context.Context, from, to time.Time, pvzId uuid.UUID, user *domain.User) ([]*domain.EmployeeProductivity, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermReportRead); err != nil {
+		return nil, err
+	}
+
+	from, to = reportPeriod(from, to, uc.now())
+	if !from.Before(to) {
+		return nil, appErr.ErrInvalidStatsPeriod
+	}
+
+	report, err := uc.repo.GetProductivity(ctx, domain.ProductivityFilter{From: from, To: to, PVZId: pvzId})
+	if err != nil {
+		return nil, appErr.ErrGettingProductivity
+	}
+
+	if report == nil {
+		report = []*domain.EmployeeProductivity{}
+	}
+
+	for _, item := range report {
+		if item.Scanned > 0 {
+			item.DeletionRate = math.Round(float64(item.Deleted)/float64(item.Scanned)*1000) / 1000
+		}
+	}
+
+	return report, nil
+}
+
+// intakeFilter проверяет параметры отчета и приводит период к полуинтервалу дат в UTC.
+func (uc *statsUseCase) intakeFilter(groupBy, bucket string, from, to time.Time) (domain.IntakeFilter, error) {
+	if groupBy == "" {
+		groupBy = constants.StatsGroupByCity
+	}
+	if bucket == "" {
+		bucket = constants.StatsBucketDay
+	}
+
+	switch groupBy {
+	case constants.StatsGroupByCity, constants.StatsGroupByPVZ, constants.StatsGroupByType:
+	default:
+		return domain.IntakeFilter{}, appErr.ErrInvalidStatsGroupBy
+	}
+
+	maxPeriod, ok := maxStatsPeriod[bucket]
+	if !ok {
+		return domain.IntakeFilter{}, appErr.ErrInvalidStatsBucket
+	}
+
+	from, to = reportPeriod(from, to, uc.now())
+	if !from.Before(to) || to.Sub(from) > maxPeriod {
+		return domain.IntakeFilter{}, appErr.ErrInvalidStatsPeriod
+	}
+
+	return domain.IntakeFilter{GroupBy: groupBy, Bucket: bucket, From: from, To: to}, nil
+}
+
+func (uc *statsUseCase) cached(filter domain.IntakeFilter) ([]*domain.IntakeStat, bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	entry, ok := uc.cache[filter]
+	if !ok || !uc.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.stats, true
+}
+
+func (uc *statsUseCase) store(filter domain.IntakeFilter, stats []*domain.IntakeStat) {
+	if uc.ttl <= 0 {
+		return
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := uc.now()
+
+	// Просроченные записи удаляем при каждой записи, чтобы кеш не рос от разных периодов.
+	for key, entry := range uc.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(uc.cache, key)
+		}
+	}
+
+	uc.cache[filter] = intakeCacheEntry{stats: stats, expiresAt: now.Add(uc.ttl)}
+}
+
+// reportPeriod переводит даты [from, to] включительно в полуинтервал [from, to+1 день) в UTC.
+// Пустой to — сегодня, пустой from — defaultReportPeriod до to.
+func reportPeriod(from, to, now time.Time) (time.Time, time.Time) {
+	if to.IsZero() {
+		to = now
+	}
+	to = truncateToDay(to).AddDate(0, 0, 1)
+
+	if from.IsZero() {
+		from = to.Add(-defaultReportPeriod)
+	}
+
+	return truncateToDay(from), to
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatsUseCase_GetIntake(t *testing.T) {
+	now := time.Date(2025, 5, 5, 15, 30, 0, 0, time.UTC)
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+
+	tests := []struct {
+		name         string
+		groupBy      string
+		bucket       string
+		from         time.Time
+		to           time.Time
+		user         *domain.User
+		repoErr      error
+		expectFilter domain.IntakeFilter
+		expectErr    error
+	}{
+		{
+			name: "Defaults",
+			user: moderator,
+			expectFilter: domain.IntakeFilter{
+				GroupBy: constants.StatsGroupByCity,
+				Bucket:  constants.StatsBucketDay,
+				From:    time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2025, 5, 6, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "Explicit period includes end date",
+			groupBy: constants.StatsGroupByType,
+			bucket:  constants.StatsBucketMonth,
+			from:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+			user:    moderator,
+			expectFilter: domain.IntakeFilter{
+				GroupBy: constants.StatsGroupByType,
+				Bucket:  constants.StatsBucketMonth,
+				From:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:      "Auditor is not allowed",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleAuditor},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Invalid groupBy",
+			groupBy:   "region",
+			user:      moderator,
+			expectErr: appErr.ErrInvalidStatsGroupBy,
+		},
+		{
+			name:      "Invalid bucket",
+			bucket:    "year",
+			user:      moderator,
+			expectErr: appErr.ErrInvalidStatsBucket,
+		},
+		{
+			name:      "From after to",
+			from:      time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			to:        time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			user:      moderator,
+			expectErr: appErr.ErrInvalidStatsPeriod,
+		},
+		{
+			name:      "Period too long for daily buckets",
+			from:      time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			user:      moderator,
+			expectErr: appErr.ErrInvalidStatsPeriod,
+		},
+		{
+			name:      "Repository error",
+			user:      moderator,
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrGettingStats,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockStatsRepository{}
+			repo.On("GetIntake", mock.Anything, mock.Anything).Return([]*domain.IntakeStat{}, tt.repoErr)
+
+			uc := NewStatsUseCase(repo, authz.New(authz.DefaultRoles()), time.Minute).(*statsUseCase)
+			uc.now = func() time.Time { return now }
+
+			stats, err := uc.GetIntake(context.Background(), tt.groupBy, tt.bucket, tt.from, tt.to, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, stats)
+			repo.AssertCalled(t, "GetIntake", mock.Anything, tt.expectFilter)
+		})
+	}
+}
+
+func TestStatsUseCase_GetIntakeCache(t *testing.T) {
+	now := time.Date(2025, 5, 5, 15, 30, 0, 0, time.UTC)
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	stats := []*domain.IntakeStat{{Bucket: now, Key: constants.PVZCityKazan, Receptions: 2, Products: 10}}
+
+	repo := &repository_mocks.MockStatsRepository{}
+	repo.On("GetIntake", mock.Anything, mock.Anything).Return(stats, nil)
+
+	uc := NewStatsUseCase(repo, authz.New(authz.DefaultRoles()), time.Minute).(*statsUseCase)
+	uc.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		got, err := uc.GetIntake(context.Background(), "", "", time.Time{}, time.Time{}, moderator)
+		assert.NoError(t, err)
+		assert.Equal(t, stats, got)
+	}
+	repo.AssertNumberOfCalls(t, "GetIntake", 1)
+
+	_, err := uc.GetIntake(context.Background(), constants.StatsGroupByPVZ, "", time.Time{}, time.Time{}, moderator)
+	assert.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "GetIntake", 2)
+
+	now = now.Add(time.Minute)
+	_, err = uc.GetIntake(context.Background(), "", "", time.Time{}, time.Time{}, moderator)
+	assert.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "GetIntake", 3)
+}
+
+func TestStatsUseCase_GetProductivity(t *testing.T) {
+	now := time.Date(2025, 5, 5, 15, 30, 0, 0, time.UTC)
+	pvzId := uuid.New()
+	interval := 42.5
+
+	tests := []struct {
+		name         string
+		from         time.Time
+		to           time.Time
+		pvzId        uuid.UUID
+		user         *domain.User
+		report       []*domain.EmployeeProductivity
+		repoErr      error
+		expectFilter domain.ProductivityFilter
+		expectRates  []float64
+		expectErr    error
+	}{
+		{
+			name:  "Deletion rate is computed",
+			from:  time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			pvzId: pvzId,
+			user:  &domain.User{Id: uuid.New(), Role: constants.UserRoleAuditor},
+			report: []*domain.EmployeeProductivity{
+				{UserId: uuid.New(), PVZId: pvzId, Scanned: 30, Deleted: 2, AvgScanIntervalSeconds: &interval},
+				{UserId: uuid.New(), PVZId: pvzId, Deleted: 1},
+			},
+			expectFilter: domain.ProductivityFilter{
+				From:  time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+				To:    time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC),
+				PVZId: pvzId,
+			},
+			expectRates: []float64{0.067, 0},
+		},
+		{
+			name: "Default period for all PVZs",
+			user: &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			expectFilter: domain.ProductivityFilter{
+				From: time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2025, 5, 6, 0, 0, 0, 0, time.UTC),
+			},
+			expectRates: []float64{},
+		},
+		{
+			name:      "Employee is not allowed",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "From after to",
+			from:      time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC),
+			to:        time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			expectErr: appErr.ErrInvalidStatsPeriod,
+		},
+		{
+			name:      "Repository error",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrGettingProductivity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockStatsRepository{}
+			repo.On("GetProductivity", mock.Anything, mock.Anything).Return(tt.report, tt.repoErr)
+
+			uc := NewStatsUseCase(repo, authz.New(authz.DefaultRoles()), time.Minute).(*statsUseCase)
+			uc.now = func() time.Time { return now }
+
+			report, err := uc.GetProductivity(context.Background(), tt.from, tt.to, tt.pvzId, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			repo.AssertCalled(t, "GetProductivity", mock.Anything, tt.expectFilter)
+
+			rates := make([]float64, 0, len(report))
+			for _, item := range report {
+				rates = append(rates, item.DeletionRate)
+			}
+			assert.Equal(t, tt.expectRates, rates)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"strings"
+	"unicode"
+)
+
+// maxStorageCellsPerRequest ограничивает число ячеек, создаваемых одним запросом.
+const maxStorageCellsPerRequest = 1000
+
+// StorageCellUseCase управляет ячейками хранения ПВЗ и ищет, где лежит товар.
+type StorageCellUseCase interface {
+	// CreateCells создает ячейки ПВЗ одной транзакцией: либо все, либо ни одной.
+	// Адрес ячейки собирается из стеллажа, полки и ячейки в верхнем регистре, например A-02-07.
+	CreateCells(ctx context.Context, pvzId uuid.UUID, cells []*domain.StorageCell, user *domain.User) ([]*domain.StorageCell, error)
+	GetCells(ctx context.Context, pvzId uuid.UUID, user *domain.User) ([]*domain.StorageCell, error)
+	// DeleteCell удаляет ячейку, в которой нет товаров.
+	DeleteCell(ctx context.Context, pvzId, cellId uuid.UUID, user *domain.User) error
+	// LocateProduct возвращает ячейки товаров ПВЗ с заданным id или штрихкодом.
+	LocateProduct(ctx context.Context, pvzId, productId uuid.UUID, barcode string, user *domain.User) ([]*domain.ProductLocation, error)
+}
+
+type storageCellUseCase struct {
+	repo        repository.StorageCellRepository
+	assignments repository.AssignmentRepository
+	authorizer  authz.Authorizer
+	tx          repository.TxManager
+}
+
+func NewStorageCellUseCase(
+	repo repository.StorageCellRepository,
+	assignments repository.AssignmentRepository,
+	authorizer authz.Authorizer,
+	tx repository.TxManager,
+) StorageCellUseCase {
+	return &storageCellUseCase{
+		repo:        repo,
+		assignments: assignments,
+		authorizer:  authorizer,
+		tx:          tx,
+	}
+}
+
+func (uc *storageCellUseCase) CreateCells(ctx context.Context, pvzId uuid.UUID, cells []*domain.StorageCell, user *domain.User) ([]*domain.StorageCell, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermPVZCreate); err != nil {
+		return nil, err
+	}
+
+	if pvzId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	if len(cells) == 0 || len(cells) > maxStorageCellsPerRequest {
+		return nil, appErr.ErrStorageCellsRequired
+	}
+
+	codes := make(map[string]struct{}, len(cells))
+	for _, cell := range cells {
+		if cell == nil {
+			return nil, appErr.ErrInvalidStorageCell
+		}
+
+		cell.Rack = strings.ToUpper(strings.TrimSpace(cell.Rack))
+		cell.Shelf = strings.ToUpper(strings.TrimSpace(cell.Shelf))
+		cell.Cell = strings.ToUpper(strings.TrimSpace(cell.Cell))
+		if !isCellPart(cell.Rack) || !isCellPart(cell.Shelf) || !isCellPart(cell.Cell) {
+			return nil, appErr.ErrInvalidStorageCell
+		}
+
+		if cell.Capacity != nil && *cell.Capacity <= 0 {
+			return nil, appErr.ErrInvalidStorageCell
+		}
+
+		for _, productType := range cell.ProductTypes {
+			if !isProductType(productType) {
+				return nil, appErr.ErrInvalidProductType
+			}
+		}
+
+		cell.PVZId = pvzId
+		cell.Code = storageCellCode(cell.Rack, cell.Shelf, cell.Cell)
+		cell.ProductTypes = uniqueStrings(cell.ProductTypes)
+
+		if _, dup := codes[cell.Code]; dup {
+			return nil, appErr.ErrDuplicateStorageCell
+		}
+		codes[cell.Code] = struct{}{}
+	}
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return uc.repo.CreateCells(ctx, cells)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrStorageCellTaken) {
+			return nil, appErr.ErrDuplicateStorageCell
+		}
+		return nil, appErr.ErrCreatingStorageCells
+	}
+
+	return cells, nil
+}
+
+func (uc *storageCellUseCase) GetCells(ctx context.Context, pvzId uuid.UUID, user *domain.User) ([]*domain.StorageCell, error) {
+	if pvzId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	if err := checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, pvzId); err != nil {
+		return nil, err
+	}
+
+	cells, err := uc.repo.GetCells(ctx, pvzId)
+	if err != nil {
+		return nil, appErr.ErrGettingStorageCells
+	}
+
+	return cells, nil
+}
+
+func (uc *storageCellUseCase) DeleteCell(ctx context.Context, pvzId, cellId uuid.UUID, user *domain.User) error {
+	if err := uc.authorizer.Authorize(user, authz.PermPVZCreate); err != nil {
+		return err
+	}
+
+	err := uc.repo.DeleteCell(ctx, pvzId, cellId)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return appErr.ErrStorageCellNotFound
+		case errors.Is(err, repository.ErrStorageCellNotEmpty):
+			return appErr.ErrStorageCellNotEmpty
+		default:
+			return appErr.ErrDeletingStorageCell
+		}
+	}
+
+	return nil
+}
+
+func (uc *storageCellUseCase) LocateProduct(ctx context.Context, pvzId, productId uuid.UUID, barcode string, user *domain.User) ([]*domain.ProductLocation, error) {
+	if pvzId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	barcode = strings.TrimSpace(barcode)
+	if productId == uuid.Nil && barcode == "" {
+		return nil, appErr.ErrProductIdOrBarcodeRequired
+	}
+
+	if err := checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, pvzId); err != nil {
+		return nil, err
+	}
+
+	locations, err := uc.repo.LocateProducts(ctx, pvzId, productId, barcode)
+	if err != nil {
+		return nil, appErr.ErrLocatingProduct
+	}
+
+	if len(locations) == 0 {
+		return nil, appErr.ErrProductNotFound
+	}
+
+	return locations, nil
+}
+
+// storageCellCode собирает адрес ячейки. Части адреса не содержат '-', поэтому адрес однозначен.
+func storageCellCode(rack, shelf, cell string) string {
+	return rack + "-" + shelf + "-" + cell
+}
+
+// normalizeCellCode приводит отсканированный адрес ячейки к виду, в котором он хранится.
+func normalizeCellCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func isCellPart(part string) bool {
+	if part == "" || len(part) > 16 {
+		return false
+	}
+
+	for _, r := range part {
+		if r == '-' || unicode.IsSpace(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isProductType(productType string) bool {
+	return productType == constants.ProductTypeElectronics || productType == constants.ProductsTypeCloth || productType == constants.ProductTypeShoes
+}
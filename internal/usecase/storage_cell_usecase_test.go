@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStorageCellUseCase_CreateCells(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	pvzId := uuid.New()
+	zero := 0
+
+	tests := []struct {
+		name        string
+		user        *domain.User
+		cells       []*domain.StorageCell
+		repoErr     error
+		mockRepo    bool
+		expectCodes []string
+		expectErr   error
+	}{
+		{
+			name: "Codes are built from rack, shelf and cell",
+			user: moderator,
+			cells: []*domain.StorageCell{
+				{Rack: "a", Shelf: "02", Cell: "07"},
+				{Rack: " B ", Shelf: "1", Cell: "1", ProductTypes: []string{constants.ProductTypeShoes, constants.ProductTypeShoes}},
+			},
+			mockRepo:    true,
+			expectCodes: []string{"A-02-07", "B-1-1"},
+		},
+		{
+			name:      "No cells",
+			user:      moderator,
+			expectErr: appErr.ErrStorageCellsRequired,
+		},
+		{
+			name:      "Dash in part",
+			user:      moderator,
+			cells:     []*domain.StorageCell{{Rack: "A-1", Shelf: "1", Cell: "1"}},
+			expectErr: appErr.ErrInvalidStorageCell,
+		},
+		{
+			name:      "Zero capacity",
+			user:      moderator,
+			cells:     []*domain.StorageCell{{Rack: "A", Shelf: "1", Cell: "1", Capacity: &zero}},
+			expectErr: appErr.ErrInvalidStorageCell,
+		},
+		{
+			name:      "Unknown product type",
+			user:      moderator,
+			cells:     []*domain.StorageCell{{Rack: "A", Shelf: "1", Cell: "1", ProductTypes: []string{"мебель"}}},
+			expectErr: appErr.ErrInvalidProductType,
+		},
+		{
+			name:      "Duplicate in request",
+			user:      moderator,
+			cells:     []*domain.StorageCell{{Rack: "a", Shelf: "1", Cell: "1"}, {Rack: "A", Shelf: "1", Cell: "1"}},
+			expectErr: appErr.ErrDuplicateStorageCell,
+		},
+		{
+			name:      "Cell already exists",
+			user:      moderator,
+			cells:     []*domain.StorageCell{{Rack: "A", Shelf: "1", Cell: "1"}},
+			mockRepo:  true,
+			repoErr:   repository.ErrStorageCellTaken,
+			expectErr: appErr.ErrDuplicateStorageCell,
+		},
+		{
+			name:      "Employee is not allowed",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			cells:     []*domain.StorageCell{{Rack: "A", Shelf: "1", Cell: "1"}},
+			expectErr: appErr.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockStorageCellRepository{}
+			uc := NewStorageCellUseCase(repo, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{})
+
+			if tt.mockRepo {
+				repo.On("CreateCells", mock.Anything, mock.Anything).Return(tt.repoErr).Once()
+			}
+
+			cells, err := uc.CreateCells(context.Background(), pvzId, tt.cells, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, cells)
+			} else {
+				assert.NoError(t, err)
+
+				codes := make([]string, 0, len(cells))
+				for _, cell := range cells {
+					codes = append(codes, cell.Code)
+					assert.Equal(t, pvzId, cell.PVZId)
+				}
+				assert.Equal(t, tt.expectCodes, codes)
+				assert.Equal(t, []string{constants.ProductTypeShoes}, cells[1].ProductTypes)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStorageCellUseCase_DeleteCell(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+
+	tests := []struct {
+		name      string
+		repoErr   error
+		expectErr error
+	}{
+		{name: "Deleted"},
+		{name: "Not found", repoErr: pgx.ErrNoRows, expectErr: appErr.ErrStorageCellNotFound},
+		{name: "Not empty", repoErr: repository.ErrStorageCellNotEmpty, expectErr: appErr.ErrStorageCellNotEmpty},
+		{name: "Repository error", repoErr: errors.New("db error"), expectErr: appErr.ErrDeletingStorageCell},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvzId, cellId := uuid.New(), uuid.New()
+
+			repo := &repository_mocks.MockStorageCellRepository{}
+			repo.On("DeleteCell", mock.Anything, pvzId, cellId).Return(tt.repoErr).Once()
+			uc := NewStorageCellUseCase(repo, &repository_mocks.MockAssignmentRepository{}, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{})
+
+			err := uc.DeleteCell(context.Background(), pvzId, cellId, moderator)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStorageCellUseCase_LocateProduct(t *testing.T) {
+	pvzId := uuid.New()
+	productId := uuid.New()
+	cellId := uuid.New()
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	location := &domain.ProductLocation{ProductId: productId, PVZId: pvzId, CellId: &cellId, CellCode: "A-1-1"}
+
+	tests := []struct {
+		name      string
+		productId uuid.UUID
+		barcode   string
+		assigned  bool
+		locations []*domain.ProductLocation
+		repoErr   error
+		mockRepo  bool
+		expectErr error
+	}{
+		{
+			name:      "By id",
+			productId: productId,
+			assigned:  true,
+			locations: []*domain.ProductLocation{location},
+			mockRepo:  true,
+		},
+		{
+			name:      "By barcode",
+			barcode:   "4600000000001",
+			assigned:  true,
+			locations: []*domain.ProductLocation{location},
+			mockRepo:  true,
+		},
+		{
+			name:      "Nothing to search by",
+			assigned:  true,
+			expectErr: appErr.ErrProductIdOrBarcodeRequired,
+		},
+		{
+			name:      "Not in pvz",
+			productId: productId,
+			assigned:  true,
+			locations: []*domain.ProductLocation{},
+			mockRepo:  true,
+			expectErr: appErr.ErrProductNotFound,
+		},
+		{
+			name:      "Employee of another pvz",
+			productId: productId,
+			expectErr: appErr.ErrPVZAccessDenied,
+		},
+		{
+			name:      "Repository error",
+			productId: productId,
+			assigned:  true,
+			mockRepo:  true,
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrLocatingProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockStorageCellRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			assignments.On("IsAssigned", mock.Anything, employee.Id, pvzId).Return(tt.assigned, nil).Maybe()
+			if tt.mockRepo {
+				repo.On("LocateProducts", mock.Anything, pvzId, tt.productId, tt.barcode).Return(tt.locations, tt.repoErr).Once()
+			}
+			uc := NewStorageCellUseCase(repo, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{})
+
+			locations, err := uc.LocateProduct(context.Background(), pvzId, tt.productId, tt.barcode, employee)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, locations)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.locations, locations)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
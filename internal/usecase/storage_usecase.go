@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// StorageUseCase следит за сроками хранения товаров в ПВЗ.
+type StorageUseCase interface {
+	// FlagOverdueProducts отмечает товары с истекшим сроком хранения; вызывается фоновой проверкой.
+	FlagOverdueProducts(ctx context.Context) (int64, error)
+	CountOverdueProducts(ctx context.Context) ([]*domain.OverdueCount, error)
+	// GetOverdueProducts возвращает товары ПВЗ, которые нужно вернуть отправителю.
+	GetOverdueProducts(ctx context.Context, pvzId uuid.UUID, user *domain.User) ([]*domain.OverdueProduct, error)
+}
+
+type storageUseCase struct {
+	repo        repository.ProductRepository
+	assignments repository.AssignmentRepository
+	authorizer  authz.Authorizer
+	policy      domain.StoragePolicy
+}
+
+func NewStorageUseCase(
+	repo repository.ProductRepository,
+	assignments repository.AssignmentRepository,
+	authorizer authz.Authorizer,
+	policy domain.StoragePolicy,
+) StorageUseCase {
+	return &storageUseCase{
+		repo:        repo,
+		assignments: assignments,
+		authorizer:  authorizer,
+		policy:      policy,
+	}
+}
+
+func (uc *storageUseCase) FlagOverdueProducts(ctx context.Context) (int64, error) {
+	return uc.repo.FlagOverdueProducts(ctx, uc.policy)
+}
+
+func (uc *storageUseCase) CountOverdueProducts(ctx context.Context) ([]*domain.OverdueCount, error) {
+	return uc.repo.CountOverdueProducts(ctx)
+}
+
+func (uc *storageUseCase) GetOverdueProducts(ctx context.Context, pvzId uuid.UUID, user *domain.User) ([]*domain.OverdueProduct, error) {
+	if pvzId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	if err := checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, pvzId); err != nil {
+		return nil, err
+	}
+
+	products, err := uc.repo.GetOverdueProducts(ctx, pvzId)
+	if err != nil {
+		return nil, appErr.ErrGettingOverdueProducts
+	}
+
+	for _, product := range products {
+		product.DueAt = product.StoredSince.Add(uc.policy.PeriodFor(product.Type))
+	}
+
+	return products, nil
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStorageUseCase_GetOverdueProducts(t *testing.T) {
+	pvzId := uuid.New()
+	storedSince := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	policy := domain.StoragePolicy{
+		DefaultDays: 14,
+		Days:        map[string]int{"электроника": 7},
+	}
+
+	tests := []struct {
+		name       string
+		pvzId      uuid.UUID
+		user       *domain.User
+		assigned   bool
+		products   []*domain.OverdueProduct
+		repoErr    error
+		expectDues []time.Time
+		expectErr  error
+	}{
+		{
+			name:     "Due date depends on product type",
+			pvzId:    pvzId,
+			user:     &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			assigned: true,
+			products: []*domain.OverdueProduct{
+				{Id: uuid.New(), Type: "электроника", StoredSince: storedSince},
+				{Id: uuid.New(), Type: "обувь", StoredSince: storedSince},
+			},
+			expectDues: []time.Time{storedSince.AddDate(0, 0, 7), storedSince.AddDate(0, 0, 14)},
+		},
+		{
+			name:       "Auditor sees any PVZ",
+			pvzId:      pvzId,
+			user:       &domain.User{Id: uuid.New(), Role: constants.UserRoleAuditor},
+			products:   []*domain.OverdueProduct{},
+			expectDues: []time.Time{},
+		},
+		{
+			name:      "Employee of another PVZ",
+			pvzId:     pvzId,
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			expectErr: appErr.ErrPVZAccessDenied,
+		},
+		{
+			name:      "Nil PVZ ID",
+			pvzId:     uuid.Nil,
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			expectErr: appErr.ErrPVZIdRequired,
+		},
+		{
+			name:      "Repository error",
+			pvzId:     pvzId,
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			repoErr:   errors.New("db error"),
+			expectErr: appErr.ErrGettingOverdueProducts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockProductRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			assignments.On("IsAssigned", mock.Anything, mock.Anything, mock.Anything).Return(tt.assigned, nil)
+			storageUC := NewStorageUseCase(repo, assignments, authz.New(authz.DefaultRoles()), policy)
+
+			if tt.products != nil || tt.repoErr != nil {
+				repo.On("GetOverdueProducts", mock.Anything, tt.pvzId).Return(tt.products, tt.repoErr).Once()
+			}
+
+			products, err := storageUC.GetOverdueProducts(context.Background(), tt.pvzId, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, products)
+			} else {
+				assert.NoError(t, err)
+				dues := []time.Time{}
+				for _, product := range products {
+					dues = append(dues, product.DueAt)
+				}
+				assert.Equal(t, tt.expectDues, dues)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
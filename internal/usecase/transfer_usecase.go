@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type TransferUseCase interface {
+	// CreateTransfer отправляет товары на хранении из ПВЗ-источника в ПВЗ назначения и публикует
+	// событие transfer.dispatched ПВЗ-источника.
+	CreateTransfer(ctx context.Context, sourcePVZId, destinationPVZId uuid.UUID, productIds []uuid.UUID, user *domain.User) (*domain.Transfer, error)
+	// ReceiveTransfer принимает товары в открытую приемку ПВЗ назначения. Если код получения товара уже
+	// занят в ПВЗ назначения, товару выдается новый код; новые коды возвращаются в PickupCodeChanges
+	// и в событии transfer.received. Вместимость ПВЗ назначения проверяется, как при добавлении товара.
+	ReceiveTransfer(ctx context.Context, transferId uuid.UUID, user *domain.User) (*domain.Transfer, error)
+	GetTransfer(ctx context.Context, transferId uuid.UUID, user *domain.User) (*domain.Transfer, error)
+	GetPVZTransfers(ctx context.Context, pvzId uuid.UUID, page, limit int, user *domain.User) ([]*domain.Transfer, error)
+}
+
+type transferUseCase struct {
+	repo           repository.TransferRepository
+	products       repository.ProductRepository
+	assignments    repository.AssignmentRepository
+	authorizer     authz.Authorizer
+	tx             repository.TxManager
+	outbox         repository.OutboxRepository
+	broker         EventBroker
+	capacityPolicy string
+}
+
+// NewTransferUseCase создает сценарии перемещений. capacityPolicy — как в NewProductUseCase.
+func NewTransferUseCase(
+	repo repository.TransferRepository,
+	products repository.ProductRepository,
+	assignments repository.AssignmentRepository,
+	authorizer authz.Authorizer,
+	tx repository.TxManager,
+	outbox repository.OutboxRepository,
+	broker EventBroker,
+	capacityPolicy string,
+) TransferUseCase {
+	return &transferUseCase{
+		repo:           repo,
+		products:       products,
+		assignments:    assignments,
+		authorizer:     authorizer,
+		tx:             tx,
+		outbox:         outbox,
+		broker:         broker,
+		capacityPolicy: capacityPolicy,
+	}
+}
+
+func (uc *transferUseCase) CreateTransfer(ctx context.Context, sourcePVZId, destinationPVZId uuid.UUID, productIds []uuid.UUID, user *domain.User) (*domain.Transfer, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermTransferSend); err != nil {
+		return nil, err
+	}
+
+	if sourcePVZId == uuid.Nil || destinationPVZId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	if sourcePVZId == destinationPVZId {
+		return nil, appErr.ErrTransferSamePVZ
+	}
+
+	productIds = uniqueIds(productIds)
+	if len(productIds) == 0 {
+		return nil, appErr.ErrTransferProductsRequired
+	}
+
+	if err := checkPVZAccess(ctx, uc.assignments, user, sourcePVZId); err != nil {
+		return nil, err
+	}
+
+	transfer := &domain.Transfer{
+		SourcePVZId:      sourcePVZId,
+		DestinationPVZId: destinationPVZId,
+	}
+
+	var event *domain.Event
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repo.CreateTransfer(ctx, transfer, productIds, actorId(user)); err != nil {
+			return err
+		}
+
+		var err error
+		event, err = addEvent(ctx, uc.outbox, constants.EventTransferDispatched, transfer.SourcePVZId, transfer.Id, transferEventPayload(transfer))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrPVZNotFound
+		}
+		if errors.Is(err, repository.ErrProductsUnavailable) {
+			return nil, appErr.ErrTransferProductsUnavailable
+		}
+		return nil, appErr.ErrCreatingTransfer
+	}
+
+	uc.broker.Publish(event)
+
+	return transfer, nil
+}
+
+func (uc *transferUseCase) ReceiveTransfer(ctx context.Context, transferId uuid.UUID, user *domain.User) (*domain.Transfer, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermTransferAccept); err != nil {
+		return nil, err
+	}
+
+	transfer, err := uc.getTransfer(ctx, transferId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkPVZAccess(ctx, uc.assignments, user, transfer.DestinationPVZId); err != nil {
+		return nil, err
+	}
+
+	if transfer.Status == constants.TransferStatusReceived {
+		return nil, appErr.ErrTransferAlreadyReceived
+	}
+
+	// Как и при добавлении товара, совпавший код повторяем во всей транзакции заново.
+	for i := 0; i < pickupCodeAttempts; i++ {
+		var received *domain.Transfer
+		var event *domain.Event
+		err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+			// Состав перемещения после отправки не меняется, поэтому объем можно взять до блокировки.
+			volume, err := uc.repo.GetTransferVolume(ctx, transferId)
+			if err != nil {
+				return err
+			}
+
+			overCapacity, err := checkCapacity(ctx, uc.products, uc.capacityPolicy, transfer.DestinationPVZId, len(transfer.Items), volume)
+			if err != nil {
+				return err
+			}
+
+			received, err = uc.repo.LockDispatchedTransfer(ctx, transferId)
+			if err != nil {
+				return err
+			}
+			received.OverCapacity = overCapacity
+
+			received.PickupCodeChanges, err = uc.reissuePickupCodes(ctx, transferId)
+			if err != nil {
+				return err
+			}
+
+			if err = uc.repo.ReceiveTransfer(ctx, received, actorId(user)); err != nil {
+				return err
+			}
+
+			event, err = addEvent(ctx, uc.outbox, constants.EventTransferReceived, received.DestinationPVZId, received.Id, transferEventPayload(received))
+			return err
+		})
+		switch {
+		case errors.Is(err, repository.ErrPickupCodeTaken):
+			continue
+		// Перемещение успели принять параллельным запросом.
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, appErr.ErrTransferAlreadyReceived
+		case errors.Is(err, repository.ErrNoOpenReception):
+			return nil, appErr.ErrNoOpenReception
+		case errors.Is(err, appErr.ErrPVZCapacityExceeded):
+			return nil, err
+		case err != nil:
+			return nil, appErr.ErrReceivingTransfer
+		}
+
+		uc.broker.Publish(event)
+
+		return received, nil
+	}
+
+	return nil, appErr.ErrReceivingTransfer
+}
+
+// reissuePickupCodes выдает новые коды товарам перемещения, чьи коды уже заняты в ПВЗ назначения.
+func (uc *transferUseCase) reissuePickupCodes(ctx context.Context, transferId uuid.UUID) ([]*domain.PickupCodeChange, error) {
+	productIds, err := uc.repo.GetPickupCodeConflicts(ctx, transferId)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*domain.PickupCodeChange, 0, len(productIds))
+	for _, productId := range productIds {
+		pickupCode, err := uc.reissuePickupCode(ctx, transferId, productId)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, &domain.PickupCodeChange{ProductId: productId, PickupCode: pickupCode})
+	}
+
+	return changes, nil
+}
+
+func (uc *transferUseCase) reissuePickupCode(ctx context.Context, transferId, productId uuid.UUID) (string, error) {
+	for i := 0; i < pickupCodeAttempts; i++ {
+		pickupCode, err := generatePickupCode()
+		if err != nil {
+			return "", err
+		}
+
+		err = uc.repo.ReissuePickupCode(ctx, transferId, productId, pickupCode)
+		if errors.Is(err, repository.ErrPickupCodeTaken) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return pickupCode, nil
+	}
+
+	return "", repository.ErrPickupCodeTaken
+}
+
+func (uc *transferUseCase) GetTransfer(ctx context.Context, transferId uuid.UUID, user *domain.User) (*domain.Transfer, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermPVZRead); err != nil {
+		return nil, err
+	}
+
+	transfer, err := uc.getTransfer(ctx, transferId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Перемещение видно сотрудникам обоих ПВЗ.
+	err = checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, transfer.SourcePVZId)
+	if errors.Is(err, appErr.ErrPVZAccessDenied) {
+		err = checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, transfer.DestinationPVZId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (uc *transferUseCase) GetPVZTransfers(ctx context.Context, pvzId uuid.UUID, page, limit int, user *domain.User) ([]*domain.Transfer, error) {
+	if pvzId == uuid.Nil {
+		return nil, appErr.ErrPVZIdRequired
+	}
+
+	if page < 1 || limit < 1 {
+		return nil, appErr.ErrBadRequest
+	}
+
+	if err := checkPVZReadAccess(ctx, uc.assignments, uc.authorizer, user, pvzId); err != nil {
+		return nil, err
+	}
+
+	transfers, err := uc.repo.GetTransfersByPVZId(ctx, pvzId, (page-1)*limit, limit)
+	if err != nil {
+		return nil, appErr.ErrGettingTransfers
+	}
+
+	return transfers, nil
+}
+
+func (uc *transferUseCase) getTransfer(ctx context.Context, transferId uuid.UUID) (*domain.Transfer, error) {
+	transfer, err := uc.repo.GetTransferById(ctx, transferId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrTransferNotFound
+		}
+		return nil, appErr.ErrGettingTransfers
+	}
+
+	return transfer, nil
+}
+
+func uniqueIds(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || id == uuid.Nil {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	return unique
+}
+
+func transferEventPayload(transfer *domain.Transfer) domain.TransferEventPayload {
+	payload := domain.TransferEventPayload{
+		TransferId:        transfer.Id,
+		SourcePVZId:       transfer.SourcePVZId,
+		DestinationPVZId:  transfer.DestinationPVZId,
+		ProductIds:        make([]uuid.UUID, 0, len(transfer.Items)),
+		ReceptionId:       transfer.ReceptionId,
+		PickupCodeChanges: transfer.PickupCodeChanges,
+	}
+
+	for _, item := range transfer.Items {
+		payload.ProductIds = append(payload.ProductIds, item.ProductId)
+	}
+
+	return payload
+}
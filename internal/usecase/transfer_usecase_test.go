@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/broker"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferUseCase_CreateTransfer(t *testing.T) {
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	sourceId := uuid.New()
+	destinationId := uuid.New()
+	productId := uuid.New()
+
+	tests := []struct {
+		name          string
+		user          *domain.User
+		destinationId uuid.UUID
+		productIds    []uuid.UUID
+		mockRepo      bool
+		repoErr       error
+		expectErr     error
+	}{
+		{
+			name:          "Valid transfer",
+			user:          employee,
+			destinationId: destinationId,
+			productIds:    []uuid.UUID{productId, productId},
+			mockRepo:      true,
+		},
+		{
+			name:          "Moderator",
+			user:          &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator},
+			destinationId: destinationId,
+			productIds:    []uuid.UUID{productId},
+			expectErr:     appErr.ErrPermissionDenied,
+		},
+		{
+			name:          "Same PVZ",
+			user:          employee,
+			destinationId: sourceId,
+			productIds:    []uuid.UUID{productId},
+			expectErr:     appErr.ErrTransferSamePVZ,
+		},
+		{
+			name:          "No products",
+			user:          employee,
+			destinationId: destinationId,
+			expectErr:     appErr.ErrTransferProductsRequired,
+		},
+		{
+			name:          "Products not stored",
+			user:          employee,
+			destinationId: destinationId,
+			productIds:    []uuid.UUID{productId},
+			mockRepo:      true,
+			repoErr:       repository.ErrProductsUnavailable,
+			expectErr:     appErr.ErrTransferProductsUnavailable,
+		},
+		{
+			name:          "Destination not found",
+			user:          employee,
+			destinationId: destinationId,
+			productIds:    []uuid.UUID{productId},
+			mockRepo:      true,
+			repoErr:       pgx.ErrNoRows,
+			expectErr:     appErr.ErrPVZNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockTransferRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			assignments.On("IsAssigned", mock.Anything, employee.Id, sourceId).Return(true, nil)
+			var event *domain.Event
+			outbox := &repository_mocks.MockOutboxRepository{}
+			outbox.On("Add", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				event = args.Get(1).(*domain.Event)
+			}).Return(nil).Maybe()
+			transferUC := NewTransferUseCase(repo, &repository_mocks.MockProductRepository{}, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, outbox, broker.New(0), constants.CapacityPolicyReject)
+
+			if tt.mockRepo {
+				repo.On("CreateTransfer", mock.Anything, mock.Anything, []uuid.UUID{productId}, employee.Id).
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*domain.Transfer).Items = []*domain.TransferItem{{ProductId: productId}}
+					}).
+					Return(tt.repoErr).
+					Once()
+			}
+
+			transfer, err := transferUC.CreateTransfer(context.Background(), sourceId, tt.destinationId, tt.productIds, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, transfer)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, sourceId, transfer.SourcePVZId)
+				assert.Equal(t, tt.destinationId, transfer.DestinationPVZId)
+
+				// Отправка видна в событиях ПВЗ-источника.
+				if assert.NotNil(t, event) {
+					assert.Equal(t, constants.EventTransferDispatched, event.Type)
+					assert.Equal(t, sourceId, event.PVZId)
+
+					var payload domain.TransferEventPayload
+					assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+					assert.Equal(t, tt.destinationId, payload.DestinationPVZId)
+					assert.Nil(t, payload.ReceptionId)
+					assert.Equal(t, []uuid.UUID{productId}, payload.ProductIds)
+				}
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferUseCase_ReceiveTransfer(t *testing.T) {
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	transferId := uuid.New()
+	destinationId := uuid.New()
+
+	items := []*domain.TransferItem{{ProductId: uuid.New()}}
+	dispatched := &domain.Transfer{Id: transferId, DestinationPVZId: destinationId, Status: constants.TransferStatusDispatched, Items: items}
+	received := &domain.Transfer{Id: transferId, DestinationPVZId: destinationId, Status: constants.TransferStatusReceived, Items: items}
+	capacity := 5
+
+	tests := []struct {
+		name       string
+		transfer   *domain.Transfer
+		getErr     error
+		assigned   bool
+		mockRepo   bool
+		full       bool
+		lockErr    error
+		receiveErr error
+		expectErr  error
+	}{
+		{
+			name:     "Valid receive",
+			transfer: dispatched,
+			assigned: true,
+			mockRepo: true,
+		},
+		{
+			name:      "Transfer not found",
+			getErr:    pgx.ErrNoRows,
+			expectErr: appErr.ErrTransferNotFound,
+		},
+		{
+			name:      "Not assigned to destination",
+			transfer:  dispatched,
+			expectErr: appErr.ErrPVZAccessDenied,
+		},
+		{
+			name:      "Already received",
+			transfer:  received,
+			assigned:  true,
+			expectErr: appErr.ErrTransferAlreadyReceived,
+		},
+		{
+			name:      "Destination over capacity",
+			transfer:  dispatched,
+			assigned:  true,
+			mockRepo:  true,
+			full:      true,
+			expectErr: appErr.ErrPVZCapacityExceeded,
+		},
+		{
+			name:      "Received concurrently",
+			transfer:  dispatched,
+			assigned:  true,
+			mockRepo:  true,
+			lockErr:   pgx.ErrNoRows,
+			expectErr: appErr.ErrTransferAlreadyReceived,
+		},
+		{
+			name:       "No open reception",
+			transfer:   dispatched,
+			assigned:   true,
+			mockRepo:   true,
+			receiveErr: repository.ErrNoOpenReception,
+			expectErr:  appErr.ErrNoOpenReception,
+		},
+		{
+			name:       "Repository error",
+			transfer:   dispatched,
+			assigned:   true,
+			mockRepo:   true,
+			receiveErr: errors.New("db error"),
+			expectErr:  appErr.ErrReceivingTransfer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockTransferRepository{}
+			products := &repository_mocks.MockProductRepository{}
+			assignments := &repository_mocks.MockAssignmentRepository{}
+			transferUC := NewTransferUseCase(repo, products, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+			repo.On("GetTransferById", mock.Anything, transferId).Return(tt.transfer, tt.getErr).Once()
+			if tt.transfer != nil {
+				assignments.On("IsAssigned", mock.Anything, employee.Id, destinationId).Return(tt.assigned, nil).Once()
+			}
+			if tt.mockRepo {
+				pvz := &domain.PVZ{Id: destinationId, CapacityItems: &capacity, Occupancy: &domain.PVZOccupancy{Items: 3}}
+				if tt.full {
+					pvz.Occupancy.Items = capacity
+				}
+				repo.On("GetTransferVolume", mock.Anything, transferId).Return(0.0, nil).Once()
+				products.On("LockPVZCapacity", mock.Anything, destinationId).Return(pvz, nil).Once()
+			}
+			if tt.mockRepo && !tt.full {
+				locked := &domain.Transfer{Id: transferId, DestinationPVZId: destinationId, Status: constants.TransferStatusDispatched}
+				if tt.lockErr != nil {
+					locked = nil
+				}
+				repo.On("LockDispatchedTransfer", mock.Anything, transferId).Return(locked, tt.lockErr).Once()
+				if tt.lockErr == nil {
+					repo.On("GetPickupCodeConflicts", mock.Anything, transferId).Return([]uuid.UUID{}, nil).Once()
+					repo.On("ReceiveTransfer", mock.Anything, locked, employee.Id).Run(func(args mock.Arguments) {
+						args.Get(1).(*domain.Transfer).Status = constants.TransferStatusReceived
+					}).Return(tt.receiveErr).Once()
+				}
+			}
+
+			transfer, err := transferUC.ReceiveTransfer(context.Background(), transferId, employee)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, transfer)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, constants.TransferStatusReceived, transfer.Status)
+				assert.Empty(t, transfer.PickupCodeChanges)
+			}
+
+			repo.AssertExpectations(t)
+			products.AssertExpectations(t)
+			assignments.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferUseCase_ReceiveTransfer_ReissuesPickupCodes(t *testing.T) {
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	transferId := uuid.New()
+	destinationId := uuid.New()
+	receptionId := uuid.New()
+	conflicting := uuid.New()
+	other := uuid.New()
+
+	repo := &repository_mocks.MockTransferRepository{}
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	products := &repository_mocks.MockProductRepository{}
+	outbox := &repository_mocks.MockOutboxRepository{}
+	transferUC := NewTransferUseCase(repo, products, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, outbox, broker.New(0), constants.CapacityPolicyReject)
+
+	locked := &domain.Transfer{
+		Id:               transferId,
+		DestinationPVZId: destinationId,
+		Status:           constants.TransferStatusDispatched,
+		Items:            []*domain.TransferItem{{ProductId: conflicting}, {ProductId: other}},
+	}
+
+	repo.On("GetTransferById", mock.Anything, transferId).Return(locked, nil).Once()
+	assignments.On("IsAssigned", mock.Anything, employee.Id, destinationId).Return(true, nil).Once()
+	repo.On("GetTransferVolume", mock.Anything, transferId).Return(0.0, nil).Once()
+	products.On("LockPVZCapacity", mock.Anything, destinationId).Return(&domain.PVZ{Id: destinationId}, nil).Once()
+	repo.On("LockDispatchedTransfer", mock.Anything, transferId).Return(locked, nil).Once()
+	repo.On("GetPickupCodeConflicts", mock.Anything, transferId).Return([]uuid.UUID{conflicting}, nil).Once()
+	// Первый сгенерированный код тоже оказался занят.
+	repo.On("ReissuePickupCode", mock.Anything, transferId, conflicting, mock.AnythingOfType("string")).Return(repository.ErrPickupCodeTaken).Once()
+	repo.On("ReissuePickupCode", mock.Anything, transferId, conflicting, mock.AnythingOfType("string")).Return(nil).Once()
+	repo.On("ReceiveTransfer", mock.Anything, locked, employee.Id).Run(func(args mock.Arguments) {
+		transfer := args.Get(1).(*domain.Transfer)
+		transfer.Status = constants.TransferStatusReceived
+		transfer.ReceptionId = &receptionId
+	}).Return(nil).Once()
+
+	var event *domain.Event
+	outbox.On("Add", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(*domain.Event)
+	}).Return(nil).Once()
+
+	transfer, err := transferUC.ReceiveTransfer(context.Background(), transferId, employee)
+
+	assert.NoError(t, err)
+	if assert.Len(t, transfer.PickupCodeChanges, 1) {
+		assert.Equal(t, conflicting, transfer.PickupCodeChanges[0].ProductId)
+		assert.Len(t, transfer.PickupCodeChanges[0].PickupCode, 6)
+	}
+
+	if assert.NotNil(t, event) {
+		assert.Equal(t, constants.EventTransferReceived, event.Type)
+		assert.Equal(t, destinationId, event.PVZId)
+
+		var payload domain.TransferEventPayload
+		assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+		assert.Equal(t, &receptionId, payload.ReceptionId)
+		assert.Equal(t, []uuid.UUID{conflicting, other}, payload.ProductIds)
+		assert.Equal(t, transfer.PickupCodeChanges[0].PickupCode, payload.PickupCodeChanges[0].PickupCode)
+	}
+
+	repo.AssertExpectations(t)
+	outbox.AssertExpectations(t)
+}
+
+func TestTransferUseCase_GetTransfer(t *testing.T) {
+	employee := &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee}
+	transfer := &domain.Transfer{Id: uuid.New(), SourcePVZId: uuid.New(), DestinationPVZId: uuid.New()}
+
+	repo := &repository_mocks.MockTransferRepository{}
+	assignments := &repository_mocks.MockAssignmentRepository{}
+	transferUC := NewTransferUseCase(repo, &repository_mocks.MockProductRepository{}, assignments, authz.New(authz.DefaultRoles()), &repository_mocks.MockTxManager{}, newOutboxMock(), broker.New(0), constants.CapacityPolicyReject)
+
+	repo.On("GetTransferById", mock.Anything, transfer.Id).Return(transfer, nil)
+	assignments.On("IsAssigned", mock.Anything, employee.Id, transfer.SourcePVZId).Return(false, nil)
+	assignments.On("IsAssigned", mock.Anything, employee.Id, transfer.DestinationPVZId).Return(true, nil).Once()
+
+	// Сотрудник ПВЗ назначения видит перемещение.
+	result, err := transferUC.GetTransfer(context.Background(), transfer.Id, employee)
+
+	assert.NoError(t, err)
+	assert.Equal(t, transfer, result)
+
+	assignments.On("IsAssigned", mock.Anything, employee.Id, transfer.DestinationPVZId).Return(false, nil).Once()
+
+	_, err = transferUC.GetTransfer(context.Background(), transfer.Id, employee)
+
+	assert.ErrorIs(t, err, appErr.ErrPVZAccessDenied)
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"net/url"
+)
+
+type WebhookUseCase interface {
+	// CreateSubscription подписывает url на события. Пустые eventTypes и pvzIds — все события и все ПВЗ.
+	// Секрет для проверки подписи возвращается только здесь.
+	CreateSubscription(ctx context.Context, rawURL string, eventTypes []string, pvzIds []uuid.UUID, user *domain.User) (*domain.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context, user *domain.User) ([]*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionId uuid.UUID, user *domain.User) error
+	// GetDeliveries возвращает журнал доставок подписки, новые первыми; status фильтрует по статусу доставки.
+	GetDeliveries(ctx context.Context, subscriptionId uuid.UUID, status string, page, limit int, user *domain.User) ([]*domain.WebhookDelivery, error)
+}
+
+type webhookUseCase struct {
+	repo       repository.WebhookRepository
+	authorizer authz.Authorizer
+}
+
+func NewWebhookUseCase(repo repository.WebhookRepository, authorizer authz.Authorizer) WebhookUseCase {
+	return &webhookUseCase{
+		repo:       repo,
+		authorizer: authorizer,
+	}
+}
+
+func (uc *webhookUseCase) CreateSubscription(ctx context.Context, rawURL string, eventTypes []string, pvzIds []uuid.UUID, user *domain.User) (*domain.WebhookSubscription, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, appErr.ErrInvalidWebhookURL
+	}
+
+	for _, eventType := range eventTypes {
+		if !isEventType(eventType) {
+			return nil, appErr.ErrInvalidEventType
+		}
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, appErr.ErrInternal
+	}
+
+	subscription := &domain.WebhookSubscription{
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: uniqueStrings(eventTypes),
+		PVZIds:     uniqueIds(pvzIds),
+	}
+	if actor := actorId(user); actor != uuid.Nil {
+		subscription.CreatedBy = &actor
+	}
+
+	if err = uc.repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, appErr.ErrCreatingWebhook
+	}
+
+	return subscription, nil
+}
+
+func (uc *webhookUseCase) GetSubscriptions(ctx context.Context, user *domain.User) ([]*domain.WebhookSubscription, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := uc.repo.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, appErr.ErrGettingWebhooks
+	}
+
+	return subscriptions, nil
+}
+
+func (uc *webhookUseCase) DeleteSubscription(ctx context.Context, subscriptionId uuid.UUID, user *domain.User) error {
+	if err := uc.authorizer.Authorize(user, authz.PermWebhookManage); err != nil {
+		return err
+	}
+
+	err := uc.repo.DeleteSubscription(ctx, subscriptionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErr.ErrWebhookNotFound
+		}
+		return appErr.ErrDeletingWebhook
+	}
+
+	return nil
+}
+
+func (uc *webhookUseCase) GetDeliveries(ctx context.Context, subscriptionId uuid.UUID, status string, page, limit int, user *domain.User) ([]*domain.WebhookDelivery, error) {
+	if err := uc.authorizer.Authorize(user, authz.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	if page < 1 || limit < 1 {
+		return nil, appErr.ErrBadRequest
+	}
+
+	switch status {
+	case "", constants.WebhookDeliveryStatusPending, constants.WebhookDeliveryStatusDelivered, constants.WebhookDeliveryStatusDead:
+	default:
+		return nil, appErr.ErrInvalidDeliveryStatus
+	}
+
+	deliveries, err := uc.repo.GetDeliveries(ctx, subscriptionId, status, (page-1)*limit, limit)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErr.ErrWebhookNotFound
+		}
+		return nil, appErr.ErrGettingWebhookDeliveries
+	}
+
+	return deliveries, nil
+}
+
+func isEventType(eventType string) bool {
+	switch eventType {
+	case constants.EventReceptionOpened, constants.EventReceptionClosed, constants.EventReceptionReopened,
+		constants.EventProductAdded, constants.EventProductDeleted, constants.EventTransferDispatched, constants.EventTransferReceived:
+		return true
+	default:
+		return false
+	}
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		unique = append(unique, v)
+	}
+
+	return unique
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliskhannn/pvz-service/internal/authz"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	appErr "github.com/aliskhannn/pvz-service/internal/errors"
+	repository_mocks "github.com/aliskhannn/pvz-service/internal/usecase/mocks/repository-mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWebhookUseCase_CreateSubscription(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	pvzId := uuid.New()
+
+	tests := []struct {
+		name       string
+		user       *domain.User
+		url        string
+		eventTypes []string
+		expectErr  error
+	}{
+		{
+			name:       "Valid subscription",
+			user:       moderator,
+			url:        "https://partner.example.com/hooks",
+			eventTypes: []string{constants.EventProductAdded, constants.EventProductAdded},
+		},
+		{
+			name:      "Employee is not allowed",
+			user:      &domain.User{Id: uuid.New(), Role: constants.UserRoleEmployee},
+			url:       "https://partner.example.com/hooks",
+			expectErr: appErr.ErrPermissionDenied,
+		},
+		{
+			name:      "Relative url",
+			user:      moderator,
+			url:       "/hooks",
+			expectErr: appErr.ErrInvalidWebhookURL,
+		},
+		{
+			name:      "Unsupported scheme",
+			user:      moderator,
+			url:       "ftp://partner.example.com",
+			expectErr: appErr.ErrInvalidWebhookURL,
+		},
+		{
+			name:       "Unknown event type",
+			user:       moderator,
+			url:        "https://partner.example.com/hooks",
+			eventTypes: []string{"pvz.deleted"},
+			expectErr:  appErr.ErrInvalidEventType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockWebhookRepository{}
+			webhookUC := NewWebhookUseCase(repo, authz.New(authz.DefaultRoles()))
+
+			if tt.expectErr == nil {
+				repo.On("CreateSubscription", mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			subscription, err := webhookUC.CreateSubscription(context.Background(), tt.url, tt.eventTypes, []uuid.UUID{pvzId}, tt.user)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, subscription)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, subscription.Secret, 64)
+				assert.Equal(t, []string{constants.EventProductAdded}, subscription.EventTypes)
+				assert.Equal(t, moderator.Id, *subscription.CreatedBy)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookUseCase_GetDeliveries(t *testing.T) {
+	moderator := &domain.User{Id: uuid.New(), Role: constants.UserRoleModerator}
+	subscriptionId := uuid.New()
+
+	tests := []struct {
+		name      string
+		status    string
+		repoErr   error
+		expectErr error
+	}{
+		{name: "All deliveries"},
+		{name: "Dead letters", status: constants.WebhookDeliveryStatusDead},
+		{name: "Invalid status", status: "lost", expectErr: appErr.ErrInvalidDeliveryStatus},
+		{name: "Subscription not found", repoErr: pgx.ErrNoRows, expectErr: appErr.ErrWebhookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository_mocks.MockWebhookRepository{}
+			webhookUC := NewWebhookUseCase(repo, authz.New(authz.DefaultRoles()))
+
+			if tt.expectErr != appErr.ErrInvalidDeliveryStatus {
+				repo.On("GetDeliveries", mock.Anything, subscriptionId, tt.status, 20, 10).
+					Return([]*domain.WebhookDelivery{}, tt.repoErr).Once()
+			}
+
+			_, err := webhookUC.GetDeliveries(context.Background(), subscriptionId, tt.status, 3, 10, moderator)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"log"
+	"time"
+)
+
+const (
+	defaultAutoCloseInterval = 15 * time.Minute
+	defaultAutoCloseIdle     = 12 * time.Hour
+)
+
+// NewAutoCloseJob создает задачу, которая закрывает приемки без поступлений дольше idleFor:
+// забытая открытая приемка не дает открыть новую.
+func NewAutoCloseJob(receptions usecase.ReceptionUseCase, idleFor, interval time.Duration) Job {
+	if idleFor <= 0 {
+		idleFor = defaultAutoCloseIdle
+	}
+	if interval <= 0 {
+		interval = defaultAutoCloseInterval
+	}
+
+	return Job{
+		Name:     "auto_close_receptions",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			closed, err := receptions.CloseStaleReceptions(ctx, idleFor)
+			if err != nil {
+				return err
+			}
+			if closed > 0 {
+				log.Printf("auto close: %d stale receptions closed", closed)
+			}
+
+			return nil
+		},
+	}
+}
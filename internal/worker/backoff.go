@@ -0,0 +1,19 @@
+package worker
+
+import "time"
+
+const (
+	baseRetryBackoff = 5 * time.Second
+	maxRetryBackoff  = time.Hour
+)
+
+// retryBackoff — пауза перед следующей попыткой после attempts неудачных: удваивается
+// с каждой неудачей, но не больше часа.
+func retryBackoff(attempts int) time.Duration {
+	backoff := baseRetryBackoff
+	for i := 0; i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxRetryBackoff)
+}
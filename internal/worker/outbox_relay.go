@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/publisher"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"github.com/google/uuid"
+	"log"
+	"time"
+)
+
+const (
+	defaultRelayInterval  = 5 * time.Second
+	defaultRelayBatchSize = 100
+)
+
+// OutboxRelay публикует события из outbox. Событие помечается опубликованным только после
+// успешной отправки, поэтому при сбое между ними оно будет отправлено повторно.
+// События одного ПВЗ уходят в порядке записи: пока событие ждет повтора, следующие
+// события этого ПВЗ не отправляются и не попадают в пачку, не мешая другим ПВЗ.
+type OutboxRelay struct {
+	outbox    repository.OutboxRepository
+	publisher publisher.Publisher
+	batchSize int
+	now       func() time.Time
+}
+
+func NewOutboxRelay(outbox repository.OutboxRepository, publisher publisher.Publisher, batchSize int) *OutboxRelay {
+	if batchSize <= 0 {
+		batchSize = defaultRelayBatchSize
+	}
+
+	return &OutboxRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+// NewOutboxRelayJob создает задачу планировщика, которая публикует накопившиеся события.
+func NewOutboxRelayJob(relay *OutboxRelay, interval time.Duration) Job {
+	if interval <= 0 {
+		interval = defaultRelayInterval
+	}
+
+	return Job{
+		Name:     "outbox_relay",
+		Interval: interval,
+		Run:      relay.Run,
+	}
+}
+
+// Run публикует одну пачку событий.
+func (r *OutboxRelay) Run(ctx context.Context) error {
+	now := r.now()
+
+	events, err := r.outbox.GetPending(ctx, now, r.batchSize)
+	if err != nil {
+		return err
+	}
+
+	blocked := make(map[uuid.UUID]bool)
+	published := 0
+
+	for _, event := range events {
+		if blocked[event.PVZId] {
+			continue
+		}
+
+		if err = r.publisher.Publish(ctx, &event.Event); err != nil {
+			blocked[event.PVZId] = true
+			log.Printf("outbox relay: event %s failed: %v", event.Id, err)
+
+			if err = r.outbox.MarkFailed(ctx, event.Seq, err.Error(), now.Add(retryBackoff(event.Attempts))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = r.outbox.MarkPublished(ctx, event.Seq); err != nil {
+			return err
+		}
+		published++
+	}
+
+	if published > 0 {
+		log.Printf("outbox relay: %d events published", published)
+	}
+
+	return nil
+}
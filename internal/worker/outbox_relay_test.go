@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOutbox struct {
+	events    []*domain.OutboxEvent
+	published []int64
+	failed    map[int64]time.Time
+	pendingAt time.Time
+}
+
+func (o *fakeOutbox) Add(ctx context.Context, event *domain.Event) error {
+	return nil
+}
+
+func (o *fakeOutbox) GetPending(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEvent, error) {
+	o.pendingAt = now
+	return o.events, nil
+}
+
+func (o *fakeOutbox) MarkPublished(ctx context.Context, seq int64) error {
+	o.published = append(o.published, seq)
+	return nil
+}
+
+func (o *fakeOutbox) MarkFailed(ctx context.Context, seq int64, reason string, nextAttemptAt time.Time) error {
+	o.failed[seq] = nextAttemptAt
+	return nil
+}
+
+type fakePublisher struct {
+	fail map[uuid.UUID]bool
+	seen []uuid.UUID
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event *domain.Event) error {
+	p.seen = append(p.seen, event.Id)
+	if p.fail[event.Id] {
+		return errors.New("webhook unavailable")
+	}
+	return nil
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	now := time.Date(2025, 5, 3, 12, 0, 0, 0, time.UTC)
+	pvzA, pvzB := uuid.New(), uuid.New()
+
+	event := func(seq int64, pvzId uuid.UUID, attempts int, nextAttemptAt time.Time) *domain.OutboxEvent {
+		return &domain.OutboxEvent{
+			Event:         domain.Event{Id: uuid.New(), PVZId: pvzId},
+			Seq:           seq,
+			Attempts:      attempts,
+			NextAttemptAt: nextAttemptAt,
+		}
+	}
+
+	outbox := &fakeOutbox{
+		failed: map[int64]time.Time{},
+		events: []*domain.OutboxEvent{
+			event(1, pvzA, 0, now),
+			event(2, pvzB, 2, now),
+			event(3, pvzA, 0, now),
+			event(4, pvzB, 0, now),
+		},
+	}
+	events := outbox.events
+	pub := &fakePublisher{fail: map[uuid.UUID]bool{events[1].Id: true}}
+
+	relay := NewOutboxRelay(outbox, pub, 10)
+	relay.now = func() time.Time { return now }
+
+	err := relay.Run(context.Background())
+
+	assert.NoError(t, err)
+	// Отложенные события отбирает GetPending по переданному времени.
+	assert.Equal(t, now, outbox.pendingAt)
+	// Событие 4 ждет повтора события 2 того же ПВЗ.
+	assert.Equal(t, []uuid.UUID{events[0].Id, events[1].Id, events[2].Id}, pub.seen)
+	assert.Equal(t, []int64{1, 3}, outbox.published)
+	assert.Equal(t, map[int64]time.Time{2: now.Add(20 * time.Second)}, outbox.failed)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, retryBackoff(0))
+	assert.Equal(t, 40*time.Second, retryBackoff(3))
+	assert.Equal(t, time.Hour, retryBackoff(20))
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/metrics"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"log"
+	"time"
+)
+
+const (
+	defaultOverdueInterval        = 24 * time.Hour
+	defaultOverdueMetricsInterval = time.Minute
+)
+
+// NewOverdueJob создает задачу, которая отмечает товары с истекшим сроком хранения.
+func NewOverdueJob(storage usecase.StorageUseCase, interval time.Duration) Job {
+	if interval <= 0 {
+		interval = defaultOverdueInterval
+	}
+
+	return Job{
+		Name:     "overdue_products",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			flagged, err := storage.FlagOverdueProducts(ctx)
+			if err != nil {
+				return err
+			}
+			if flagged > 0 {
+				log.Printf("overdue check: %d products flagged", flagged)
+			}
+
+			return nil
+		},
+	}
+}
+
+// NewOverdueMetricsJob создает задачу, которая обновляет метрику числа просроченных товаров по ПВЗ.
+// Задача выполняется на каждой реплике, чтобы метрика любой из них была актуальной.
+func NewOverdueMetricsJob(storage usecase.StorageUseCase, registry *metrics.Registry, interval time.Duration) Job {
+	if interval <= 0 {
+		interval = defaultOverdueMetricsInterval
+	}
+
+	gauge := registry.NewGaugeVec("pvz_overdue_products", "Number of stored products with expired storage period.", "pvz_id", "city")
+
+	return Job{
+		Name:     "overdue_metrics",
+		Interval: interval,
+		Local:    true,
+		Run: func(ctx context.Context) error {
+			counts, err := storage.CountOverdueProducts(ctx)
+			if err != nil {
+				return err
+			}
+
+			gauge.Reset()
+			for _, count := range counts {
+				gauge.Set(float64(count.Count), count.PVZId.String(), count.City)
+			}
+
+			return nil
+		},
+	}
+}
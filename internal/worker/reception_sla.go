@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/metrics"
+	"github.com/aliskhannn/pvz-service/internal/usecase"
+	"time"
+)
+
+const defaultSLACheckInterval = time.Minute
+
+// slaCities — города, для которых метрики по городу выставляются всегда, в том числе нулем.
+var slaCities = []string{constants.PVZCityMoscow, constants.PVZCitySaintPetersburg, constants.PVZCityKazan}
+
+// NewReceptionSLAJob создает задачу, которая обновляет метрики открытых приемок:
+// их число и число нарушений SLA по городам, а также возраст каждой открытой приемки.
+// Задача выполняется на каждой реплике, чтобы метрики любой из них были актуальными.
+func NewReceptionSLAJob(sla usecase.ReceptionSLAUseCase, registry *metrics.Registry, interval time.Duration) Job {
+	if interval <= 0 {
+		interval = defaultSLACheckInterval
+	}
+
+	open := registry.NewGaugeVec("pvz_open_receptions", "Number of open inbound receptions.", "city")
+	breaches := registry.NewGaugeVec("pvz_reception_sla_breaches", "Number of open inbound receptions exceeding the city SLA.", "city")
+	age := registry.NewGaugeVec("pvz_open_reception_age_seconds", "Age of the open inbound reception in seconds.", "pvz_id", "city")
+
+	return Job{
+		Name:     "reception_sla",
+		Interval: interval,
+		Local:    true,
+		Run: func(ctx context.Context) error {
+			receptions, err := sla.GetOpenReceptions(ctx)
+			if err != nil {
+				return err
+			}
+
+			openByCity := make(map[string]int)
+			breachesByCity := make(map[string]int)
+			for _, city := range slaCities {
+				openByCity[city] = 0
+				breachesByCity[city] = 0
+			}
+
+			age.Reset()
+			for _, reception := range receptions {
+				openByCity[reception.City]++
+				if reception.OverdueSeconds > 0 {
+					breachesByCity[reception.City]++
+				}
+				// В ПВЗ открыта не больше одной приемки, поэтому pvz_id однозначно задает серию.
+				age.Set(float64(reception.OpenSeconds), reception.PVZId.String(), reception.City)
+			}
+
+			open.Reset()
+			breaches.Reset()
+			for city, count := range openByCity {
+				open.Set(float64(count), city)
+				breaches.Set(float64(breachesByCity[city]), city)
+			}
+
+			return nil
+		},
+	}
+}
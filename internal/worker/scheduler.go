@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"context"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Locker следит, чтобы задачу за интервал выполнял только один экземпляр сервиса.
+type Locker interface {
+	// TryRun вызывает fn, если в текущем интервале задачу name еще никто не запускал, и возвращает
+	// false, если ее уже запустил другой экземпляр или предыдущий запуск еще идет.
+	TryRun(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Job — периодическая задача планировщика. Local-задача только читает данные для метрик этой
+// реплики, поэтому выполняется на каждой реплике без согласования через Locker.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Local    bool
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler запускает задачи внутри процесса. Запуск согласуется через Locker, поэтому при
+// нескольких репликах за интервал задачу выполняет только одна.
+type Scheduler struct {
+	locker Locker
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	jobs     []Job
+	statuses map[string]*domain.JobStatus
+}
+
+func NewScheduler(locker Locker) *Scheduler {
+	return &Scheduler{
+		locker:   locker,
+		now:      time.Now,
+		statuses: make(map[string]*domain.JobStatus),
+	}
+}
+
+// Add регистрирует задачу; вызывается до Start.
+func (s *Scheduler) Add(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job)
+	s.statuses[job.Name] = &domain.JobStatus{Name: job.Name, Interval: job.Interval.String()}
+}
+
+// Start запускает задачи: каждая выполняется сразу и затем раз в свой интервал, пока не отменен ctx.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.RLock()
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.RUnlock()
+
+	for _, job := range jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+// Statuses возвращает состояние задач, отсортированное по имени.
+func (s *Scheduler) Statuses() []domain.JobStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]domain.JobStatus, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		statuses = append(statuses, *status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runJob(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	started := s.now()
+	s.update(job.Name, func(status *domain.JobStatus) {
+		status.Running = true
+		status.LastStartedAt = &started
+	})
+
+	var (
+		locked bool
+		err    error
+	)
+	if job.Local {
+		locked, err = true, job.Run(ctx)
+	} else {
+		locked, err = s.locker.TryRun(ctx, job.Name, job.Interval, job.Run)
+	}
+	if err != nil {
+		log.Printf("scheduler: job %s failed: %v", job.Name, err)
+	}
+
+	finished := s.now()
+	next := finished.Add(job.Interval)
+	s.update(job.Name, func(status *domain.JobStatus) {
+		status.Running = false
+		status.NextRunAt = &next
+
+		if !locked && err == nil {
+			status.Skipped++
+			return
+		}
+
+		status.Runs++
+		status.LastFinishedAt = &finished
+		status.LastError = ""
+		if err != nil {
+			status.LastError = err.Error()
+		}
+	})
+}
+
+func (s *Scheduler) update(name string, fn func(status *domain.JobStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s.statuses[name])
+}
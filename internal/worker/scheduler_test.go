@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLocker struct {
+	held bool
+}
+
+func (l *fakeLocker) TryRun(ctx context.Context, _ string, _ time.Duration, fn func(ctx context.Context) error) (bool, error) {
+	if l.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func TestScheduler_RunJob(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		held        bool
+		local       bool
+		jobErr      error
+		expectRuns  int
+		expectSkips int
+		expectError string
+	}{
+		{
+			name:       "Job runs under lock",
+			expectRuns: 1,
+		},
+		{
+			name:        "Job error is recorded",
+			jobErr:      errors.New("db error"),
+			expectRuns:  1,
+			expectError: "db error",
+		},
+		{
+			name:        "Already run by another replica",
+			held:        true,
+			expectSkips: 1,
+		},
+		{
+			name:       "Local job runs on every replica",
+			held:       true,
+			local:      true,
+			expectRuns: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := NewScheduler(&fakeLocker{held: tt.held})
+			scheduler.now = func() time.Time { return now }
+
+			called := 0
+			job := Job{
+				Name:     "test",
+				Interval: time.Minute,
+				Local:    tt.local,
+				Run: func(ctx context.Context) error {
+					called++
+					return tt.jobErr
+				},
+			}
+			scheduler.Add(job)
+
+			scheduler.runJob(context.Background(), job)
+
+			statuses := scheduler.Statuses()
+			assert.Len(t, statuses, 1)
+
+			status := statuses[0]
+			assert.Equal(t, tt.expectRuns, called)
+			assert.Equal(t, tt.expectRuns, status.Runs)
+			assert.Equal(t, tt.expectSkips, status.Skipped)
+			assert.Equal(t, tt.expectError, status.LastError)
+			assert.False(t, status.Running)
+			assert.Equal(t, now.Add(time.Minute), *status.NextRunAt)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/publisher"
+	"github.com/aliskhannn/pvz-service/internal/repository"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultWebhookInterval    = 10 * time.Second
+	defaultWebhookBatchSize   = 50
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookTimeout     = 10 * time.Second
+)
+
+type webhookFanout struct {
+	repo repository.WebhookRepository
+}
+
+// NewWebhookFanout возвращает Publisher, который ставит событие в очередь доставки
+// каждой подходящей подписке. Сама отправка выполняется WebhookDeliverer.
+func NewWebhookFanout(repo repository.WebhookRepository) publisher.Publisher {
+	return &webhookFanout{repo: repo}
+}
+
+func (f *webhookFanout) Publish(ctx context.Context, event *domain.Event) error {
+	return f.repo.EnqueueDeliveries(ctx, event)
+}
+
+// WebhookDeliverer отправляет доставки подписчикам. Неудачная доставка повторяется с
+// экспоненциальной задержкой, после maxAttempts неудач переходит в статус dead.
+type WebhookDeliverer struct {
+	repo        repository.WebhookRepository
+	client      *http.Client
+	batchSize   int
+	maxAttempts int
+	now         func() time.Time
+}
+
+func NewWebhookDeliverer(repo repository.WebhookRepository, timeout time.Duration, batchSize, maxAttempts int) *WebhookDeliverer {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	if batchSize <= 0 {
+		batchSize = defaultWebhookBatchSize
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+
+	return &WebhookDeliverer{
+		repo:        repo,
+		client:      &http.Client{Timeout: timeout},
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		now:         time.Now,
+	}
+}
+
+// NewWebhookDeliveryJob создает задачу планировщика, которая отправляет ожидающие доставки.
+func NewWebhookDeliveryJob(deliverer *WebhookDeliverer, interval time.Duration) Job {
+	if interval <= 0 {
+		interval = defaultWebhookInterval
+	}
+
+	return Job{
+		Name:     "webhook_delivery",
+		Interval: interval,
+		Run:      deliverer.Run,
+	}
+}
+
+// Run отправляет одну пачку доставок.
+func (d *WebhookDeliverer) Run(ctx context.Context) error {
+	deliveries, err := d.repo.GetDueDeliveries(ctx, d.batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		status, err := d.send(ctx, delivery)
+		now := d.now()
+
+		delivery.Attempts++
+		delivery.ResponseStatus = nil
+		if status > 0 {
+			delivery.ResponseStatus = &status
+		}
+
+		switch {
+		case err == nil:
+			delivery.Status = constants.WebhookDeliveryStatusDelivered
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+		case delivery.Attempts >= d.maxAttempts:
+			delivery.Status = constants.WebhookDeliveryStatusDead
+			delivery.LastError = err.Error()
+			log.Printf("webhook delivery %s is dead after %d attempts: %v", delivery.Id, delivery.Attempts, err)
+		default:
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = now.Add(retryBackoff(delivery.Attempts - 1))
+		}
+
+		if err = d.repo.SaveAttempt(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send отправляет подписанное событие и возвращает HTTP-статус ответа (0, если ответа нет).
+func (d *WebhookDeliverer) send(ctx context.Context, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", delivery.EventId.String())
+	req.Header.Set("X-Event-Type", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery-Id", delivery.Id.String())
+	req.Header.Set(publisher.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(publisher.HeaderSignature, publisher.Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
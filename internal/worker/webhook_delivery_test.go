@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aliskhannn/pvz-service/internal/constants"
+	"github.com/aliskhannn/pvz-service/internal/domain"
+	"github.com/aliskhannn/pvz-service/internal/infrastructure/publisher"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWebhookRepository struct {
+	due   []*domain.WebhookDelivery
+	saved []domain.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) GetSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (r *fakeWebhookRepository) DeleteSubscription(ctx context.Context, subscriptionId uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) EnqueueDeliveries(ctx context.Context, event *domain.Event) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) GetDueDeliveries(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	return r.due, nil
+}
+
+func (r *fakeWebhookRepository) SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.saved = append(r.saved, *delivery)
+	return nil
+}
+
+func (r *fakeWebhookRepository) GetDeliveries(ctx context.Context, subscriptionId uuid.UUID, status string, offset, limit int) ([]*domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func TestWebhookDeliverer_Run(t *testing.T) {
+	now := time.Date(2025, 5, 4, 12, 0, 0, 0, time.UTC)
+	const secret = "test-secret"
+	payload := []byte(`{"type":"product.added"}`)
+
+	tests := []struct {
+		name          string
+		status        int
+		attempts      int
+		expectStatus  string
+		expectNext    time.Time
+		expectError   bool
+		expectAttempt int
+	}{
+		{
+			name:          "Delivered",
+			status:        http.StatusOK,
+			expectStatus:  constants.WebhookDeliveryStatusDelivered,
+			expectAttempt: 1,
+		},
+		{
+			name:          "Failure is retried with backoff",
+			status:        http.StatusServiceUnavailable,
+			attempts:      1,
+			expectStatus:  constants.WebhookDeliveryStatusPending,
+			expectNext:    now.Add(10 * time.Second),
+			expectError:   true,
+			expectAttempt: 2,
+		},
+		{
+			name:          "Last attempt moves delivery to dead letter",
+			status:        http.StatusInternalServerError,
+			attempts:      2,
+			expectStatus:  constants.WebhookDeliveryStatusDead,
+			expectError:   true,
+			expectAttempt: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				timestamp, err := strconv.ParseInt(r.Header.Get(publisher.HeaderTimestamp), 10, 64)
+				assert.NoError(t, err)
+				assert.True(t, publisher.Verify(secret, timestamp, body, r.Header.Get(publisher.HeaderSignature)))
+				assert.Equal(t, payload, body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			delivery := &domain.WebhookDelivery{
+				Id:        uuid.New(),
+				EventId:   uuid.New(),
+				EventType: constants.EventProductAdded,
+				Payload:   payload,
+				Status:    constants.WebhookDeliveryStatusPending,
+				Attempts:  tt.attempts,
+				URL:       server.URL,
+				Secret:    secret,
+			}
+			repo := &fakeWebhookRepository{due: []*domain.WebhookDelivery{delivery}}
+
+			deliverer := NewWebhookDeliverer(repo, time.Second, 10, 3)
+			deliverer.now = func() time.Time { return now }
+
+			assert.NoError(t, deliverer.Run(context.Background()))
+
+			assert.Len(t, repo.saved, 1)
+			saved := repo.saved[0]
+			assert.Equal(t, tt.expectStatus, saved.Status)
+			assert.Equal(t, tt.expectAttempt, saved.Attempts)
+			assert.Equal(t, tt.status, *saved.ResponseStatus)
+			assert.Equal(t, tt.expectError, saved.LastError != "")
+			if !tt.expectNext.IsZero() {
+				assert.Equal(t, tt.expectNext, saved.NextAttemptAt)
+			}
+		})
+	}
+}
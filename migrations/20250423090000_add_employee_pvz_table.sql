@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS employee_pvz
+(
+    user_id     UUID      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    pvz_id      UUID      NOT NULL REFERENCES pvz (id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, pvz_id)
+);
+
+CREATE INDEX IF NOT EXISTS employee_pvz_pvz_id_idx ON employee_pvz (pvz_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS employee_pvz;
+-- +goose StatementEnd
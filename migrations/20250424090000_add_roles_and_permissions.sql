@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS roles
+(
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS permissions
+(
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role       TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission TEXT NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description)
+VALUES ('employee', 'Сотрудник ПВЗ'),
+       ('moderator', 'Модератор'),
+       ('auditor', 'Аудитор, только чтение'),
+       ('regional_manager', 'Региональный менеджер')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description)
+VALUES ('pvz:create', 'Создание ПВЗ'),
+       ('pvz:read', 'Просмотр ПВЗ, приемок и товаров'),
+       ('pvz:assign', 'Закрепление сотрудников за ПВЗ'),
+       ('reception:open', 'Открытие приемки'),
+       ('reception:close', 'Закрытие приемки'),
+       ('product:add', 'Добавление товара в приемку'),
+       ('product:delete', 'Удаление товара из приемки'),
+       ('report:read', 'Просмотр отчетов'),
+       ('user:manage', 'Управление пользователями')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('employee', 'pvz:read'),
+       ('employee', 'reception:open'),
+       ('employee', 'reception:close'),
+       ('employee', 'product:add'),
+       ('employee', 'product:delete'),
+       ('moderator', 'pvz:create'),
+       ('moderator', 'pvz:read'),
+       ('moderator', 'pvz:assign'),
+       ('moderator', 'report:read'),
+       ('moderator', 'user:manage'),
+       ('auditor', 'pvz:read'),
+       ('auditor', 'report:read'),
+       ('regional_manager', 'pvz:read'),
+       ('regional_manager', 'pvz:assign'),
+       ('regional_manager', 'report:read')
+ON CONFLICT (role, permission) DO NOTHING;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_role_check,
+    ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles (name) ON UPDATE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_role_fkey;
+
+DELETE FROM users WHERE role NOT IN ('employee', 'moderator');
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK ( role IN ('employee', 'moderator') );
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_type WHERE typname = 'product_status'
+    ) THEN
+        CREATE TYPE product_status AS ENUM ('received', 'stored', 'issued', 'returned_to_sender');
+    END IF;
+END$$;
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS status            product_status NOT NULL DEFAULT 'received',
+    ADD COLUMN IF NOT EXISTS pickup_code       TEXT,
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS status_changed_by UUID REFERENCES users (id) ON DELETE SET NULL;
+
+-- Товары из уже закрытых приемок лежат на складе.
+UPDATE products p
+SET status = 'stored'
+FROM receptions r
+WHERE r.id = p.reception_id AND r.status = 'close';
+
+CREATE INDEX IF NOT EXISTS products_status_idx ON products (status);
+
+INSERT INTO permissions (name, description)
+VALUES ('product:issue', 'Выдача товара клиенту'),
+       ('product:return', 'Возврат товара отправителю')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('employee', 'product:issue'),
+       ('employee', 'product:return')
+ON CONFLICT (role, permission) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name IN ('product:issue', 'product:return');
+
+DROP INDEX IF EXISTS products_status_idx;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS status_changed_by,
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS pickup_code,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS product_status;
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS address       TEXT,
+    ADD COLUMN IF NOT EXISTS opening_hours TEXT;
+
+-- ПВЗ товара дублируется из приемки, чтобы код получения был уникален в пределах ПВЗ.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS pvz_id UUID REFERENCES pvz (id) ON DELETE CASCADE;
+
+UPDATE products p
+SET pvz_id = r.pvz_id
+FROM receptions r
+WHERE r.id = p.reception_id AND p.pvz_id IS NULL;
+
+ALTER TABLE products
+    ALTER COLUMN pvz_id SET NOT NULL;
+
+-- Товары, принятые до появления кодов, получают код сейчас.
+UPDATE products
+SET pickup_code = lpad(floor(random() * 1000000)::int::text, 6, '0')
+WHERE pickup_code IS NULL AND status IN ('received', 'stored');
+
+-- Перегенерируем совпавшие коды, пока в каждом ПВЗ они не станут уникальными.
+DO $$
+BEGIN
+    LOOP
+        UPDATE products
+        SET pickup_code = lpad(floor(random() * 1000000)::int::text, 6, '0')
+        WHERE id IN (
+            SELECT id
+            FROM (
+                SELECT id, row_number() OVER (PARTITION BY pvz_id, pickup_code ORDER BY date_time) AS n
+                FROM products
+                WHERE status IN ('received', 'stored')
+            ) d
+            WHERE d.n > 1
+        );
+        EXIT WHEN NOT FOUND;
+    END LOOP;
+END$$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_pvz_pickup_code_key
+    ON products (pvz_id, pickup_code)
+    WHERE status IN ('received', 'stored');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_pvz_pickup_code_key;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS pvz_id;
+
+ALTER TABLE pvz
+    DROP COLUMN IF EXISTS opening_hours,
+    DROP COLUMN IF EXISTS address;
+-- +goose StatementEnd
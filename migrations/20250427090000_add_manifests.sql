@@ -0,0 +1,78 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS barcode TEXT;
+
+CREATE INDEX IF NOT EXISTS products_barcode_idx ON products (barcode);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_type WHERE typname = 'manifest_status'
+    ) THEN
+        CREATE TYPE manifest_status AS ENUM ('pending', 'linked');
+    END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS manifests
+(
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    pvz_id       UUID            NOT NULL REFERENCES pvz (id) ON DELETE CASCADE,
+    external_id  TEXT,
+    reception_id UUID UNIQUE REFERENCES receptions (id) ON DELETE SET NULL,
+    status       manifest_status NOT NULL DEFAULT 'pending',
+    created_by   UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS manifests_pvz_pending_idx ON manifests (pvz_id, created_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS manifest_items
+(
+    manifest_id UUID         NOT NULL REFERENCES manifests (id) ON DELETE CASCADE,
+    barcode     TEXT         NOT NULL,
+    type        product_type NOT NULL,
+    PRIMARY KEY (manifest_id, barcode)
+);
+
+CREATE TABLE IF NOT EXISTS discrepancy_reports
+(
+    reception_id    UUID PRIMARY KEY REFERENCES receptions (id) ON DELETE CASCADE,
+    manifest_id     UUID      NOT NULL REFERENCES manifests (id) ON DELETE CASCADE,
+    expected        INT       NOT NULL,
+    received        INT       NOT NULL,
+    missing         TEXT[]    NOT NULL DEFAULT '{}',
+    unexpected      TEXT[]    NOT NULL DEFAULT '{}',
+    duplicates      TEXT[]    NOT NULL DEFAULT '{}',
+    without_barcode INT       NOT NULL DEFAULT 0,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO permissions (name, description)
+VALUES ('manifest:create', 'Регистрация ожидаемых поставок'),
+       ('manifest:read', 'Просмотр манифестов и отчетов о расхождениях')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('moderator', 'manifest:create'),
+       ('moderator', 'manifest:read'),
+       ('employee', 'manifest:read'),
+       ('auditor', 'manifest:read'),
+       ('regional_manager', 'manifest:read')
+ON CONFLICT (role, permission) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name IN ('manifest:create', 'manifest:read');
+
+DROP TABLE IF EXISTS discrepancy_reports;
+DROP TABLE IF EXISTS manifest_items;
+DROP TABLE IF EXISTS manifests;
+DROP TYPE IF EXISTS manifest_status;
+
+DROP INDEX IF EXISTS products_barcode_idx;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS barcode;
+-- +goose StatementEnd
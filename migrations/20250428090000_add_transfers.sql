@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE product_status ADD VALUE IF NOT EXISTS 'in_transit';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_type WHERE typname = 'transfer_status'
+    ) THEN
+        CREATE TYPE transfer_status AS ENUM ('dispatched', 'received');
+    END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS transfers
+(
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_pvz_id      UUID            NOT NULL REFERENCES pvz (id) ON DELETE CASCADE,
+    destination_pvz_id UUID            NOT NULL REFERENCES pvz (id) ON DELETE CASCADE,
+    status             transfer_status NOT NULL DEFAULT 'dispatched',
+    dispatched_at      TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_by      UUID REFERENCES users (id) ON DELETE SET NULL,
+    received_at        TIMESTAMP,
+    received_by        UUID REFERENCES users (id) ON DELETE SET NULL,
+    reception_id       UUID REFERENCES receptions (id) ON DELETE SET NULL,
+    CHECK (source_pvz_id <> destination_pvz_id)
+);
+
+CREATE INDEX IF NOT EXISTS transfers_source_pvz_idx ON transfers (source_pvz_id, dispatched_at);
+CREATE INDEX IF NOT EXISTS transfers_destination_pvz_idx ON transfers (destination_pvz_id, dispatched_at);
+
+CREATE TABLE IF NOT EXISTS transfer_items
+(
+    transfer_id         UUID NOT NULL REFERENCES transfers (id) ON DELETE CASCADE,
+    product_id          UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    source_reception_id UUID NOT NULL REFERENCES receptions (id) ON DELETE CASCADE,
+    PRIMARY KEY (transfer_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS transfer_items_product_idx ON transfer_items (product_id);
+
+INSERT INTO permissions (name, description)
+VALUES ('transfer:send', 'Отправка товаров в другой ПВЗ'),
+       ('transfer:accept', 'Прием товаров из другого ПВЗ')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('employee', 'transfer:send'),
+       ('employee', 'transfer:accept')
+ON CONFLICT (role, permission) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name IN ('transfer:send', 'transfer:accept');
+
+DROP TABLE IF EXISTS transfer_items;
+DROP TABLE IF EXISTS transfers;
+DROP TYPE IF EXISTS transfer_status;
+
+-- Значение enum удалить нельзя; товары в пути возвращаются на хранение.
+UPDATE products SET status = 'stored' WHERE status = 'in_transit';
+-- +goose StatementEnd
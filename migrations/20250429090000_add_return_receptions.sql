@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE product_status ADD VALUE IF NOT EXISTS 'returning';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_type WHERE typname = 'reception_direction'
+    ) THEN
+        CREATE TYPE reception_direction AS ENUM ('inbound', 'return');
+    END IF;
+END$$;
+
+ALTER TABLE receptions
+    ADD COLUMN IF NOT EXISTS direction reception_direction NOT NULL DEFAULT 'inbound';
+
+CREATE INDEX IF NOT EXISTS receptions_pvz_direction_status_idx ON receptions (pvz_id, direction, status);
+
+-- Товар попадает в возвратную партию, сохраняя исходную приемку.
+CREATE TABLE IF NOT EXISTS return_items
+(
+    reception_id UUID      NOT NULL REFERENCES receptions (id) ON DELETE CASCADE,
+    product_id   UUID      NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    added_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (reception_id, product_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS return_items_product_key ON return_items (product_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE products SET status = 'stored' WHERE status = 'returning';
+
+DROP TABLE IF EXISTS return_items;
+
+DELETE FROM receptions WHERE direction = 'return';
+
+DROP INDEX IF EXISTS receptions_pvz_direction_status_idx;
+
+ALTER TABLE receptions
+    DROP COLUMN IF EXISTS direction;
+
+DROP TYPE IF EXISTS reception_direction;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Время, когда у товара на хранении истек срок хранения; отмечается фоновой проверкой.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS overdue_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS products_overdue_idx ON products (pvz_id, overdue_at)
+    WHERE overdue_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_overdue_idx;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS overdue_at;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE receptions
+    ADD COLUMN IF NOT EXISTS closed_at    TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS auto_closed  BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS close_reason TEXT;
+
+INSERT INTO permissions (name, description)
+VALUES ('scheduler:read', 'Просмотр состояния фоновых задач')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('moderator', 'scheduler:read')
+ON CONFLICT (role, permission) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'scheduler:read';
+
+ALTER TABLE receptions
+    DROP COLUMN IF EXISTS close_reason,
+    DROP COLUMN IF EXISTS auto_closed,
+    DROP COLUMN IF EXISTS closed_at;
+-- +goose StatementEnd
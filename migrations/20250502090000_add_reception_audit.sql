@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_type WHERE typname = 'reception_audit_action'
+    ) THEN
+        CREATE TYPE reception_audit_action AS ENUM ('reopen', 'add_product', 'remove_product');
+    END IF;
+END$$;
+
+-- История исправлений закрытых приемок. Удаленный товар не сохраняется в products,
+-- поэтому его тип и штрихкод копируются в запись.
+CREATE TABLE IF NOT EXISTS reception_audit
+(
+    id           UUID PRIMARY KEY                DEFAULT gen_random_uuid(),
+    reception_id UUID                   NOT NULL REFERENCES receptions (id) ON DELETE CASCADE,
+    action       reception_audit_action NOT NULL,
+    product_id   UUID,
+    product_type product_type,
+    barcode      TEXT,
+    reason       TEXT                   NOT NULL,
+    user_id      UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMP              NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS reception_audit_reception_idx ON reception_audit (reception_id, created_at);
+
+INSERT INTO permissions (name, description)
+VALUES ('reception:correct', 'Переоткрытие и исправление закрытых приемок')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('moderator', 'reception:correct')
+ON CONFLICT (role, permission) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'reception:correct';
+
+DROP TABLE IF EXISTS reception_audit;
+
+DROP TYPE IF EXISTS reception_audit_action;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- События записываются в одной транзакции с изменением состояния и публикуются relay-воркером.
+-- Порядок публикации внутри ПВЗ определяется id.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    event_id        UUID      NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    event_type      TEXT      NOT NULL,
+    pvz_id          UUID      NOT NULL,
+    aggregate_id    UUID      NOT NULL,
+    payload         JSONB     NOT NULL,
+    occurred_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at    TIMESTAMP,
+    attempts        INT       NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error      TEXT
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE published_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_type WHERE typname = 'webhook_delivery_status'
+    ) THEN
+        CREATE TYPE webhook_delivery_status AS ENUM ('pending', 'delivered', 'dead');
+    END IF;
+END$$;
+
+-- Пустые event_types и pvz_ids означают подписку на все события и все ПВЗ.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id          UUID PRIMARY KEY   DEFAULT gen_random_uuid(),
+    url         TEXT      NOT NULL,
+    secret      TEXT      NOT NULL,
+    event_types TEXT[]    NOT NULL DEFAULT '{}',
+    pvz_ids     UUID[]    NOT NULL DEFAULT '{}',
+    created_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Доставка события подписчику. Одно событие попадает к подписчику один раз, даже если relay
+-- опубликовал его повторно.
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              UUID PRIMARY KEY                 DEFAULT gen_random_uuid(),
+    subscription_id UUID                    NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        UUID                    NOT NULL,
+    event_type      TEXT                    NOT NULL,
+    payload         JSONB                   NOT NULL,
+    status          webhook_delivery_status NOT NULL DEFAULT 'pending',
+    attempts        INT                     NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP               NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error      TEXT,
+    response_status INT,
+    created_at      TIMESTAMP               NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at    TIMESTAMP,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, created_at);
+
+INSERT INTO permissions (name, description)
+VALUES ('webhook:manage', 'Управление подписками на вебхуки')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('moderator', 'webhook:manage')
+ON CONFLICT (role, permission) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'webhook:manage';
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+
+DROP TYPE IF EXISTS webhook_delivery_status;
+-- +goose StatementEnd
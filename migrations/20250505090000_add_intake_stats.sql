@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS receptions_date_time_idx ON receptions (date_time) WHERE direction = 'inbound';
+
+INSERT INTO permissions (name, description)
+VALUES ('stats:read', 'Просмотр аналитики по поступлению товаров')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('moderator', 'stats:read')
+ON CONFLICT (role, permission) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'stats:read';
+
+DROP INDEX IF EXISTS receptions_date_time_idx;
+-- +goose StatementEnd
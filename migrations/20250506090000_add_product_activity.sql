@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS created_by UUID REFERENCES users (id) ON DELETE SET NULL;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_type WHERE typname = 'product_action'
+    ) THEN
+        CREATE TYPE product_action AS ENUM ('added', 'deleted');
+    END IF;
+END$$;
+
+-- Журнал сканирований и удалений товаров сотрудниками. Товар удаляется из products физически,
+-- поэтому ссылки на него нет: запись остается для отчета о производительности.
+CREATE TABLE IF NOT EXISTS product_activity
+(
+    id         BIGSERIAL PRIMARY KEY,
+    product_id UUID           NOT NULL,
+    pvz_id     UUID           NOT NULL REFERENCES pvz (id) ON DELETE CASCADE,
+    user_id    UUID           REFERENCES users (id) ON DELETE SET NULL,
+    action     product_action NOT NULL,
+    created_at TIMESTAMP      NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS product_activity_created_at_idx ON product_activity (created_at);
+CREATE INDEX IF NOT EXISTS product_activity_user_pvz_idx ON product_activity (user_id, pvz_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_activity;
+
+DROP TYPE IF EXISTS product_action;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS created_by;
+-- +goose StatementEnd
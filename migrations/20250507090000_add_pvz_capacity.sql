@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Вместимость ПВЗ: число товаров и, при необходимости, объем в литрах. NULL — без ограничения.
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS capacity_items  INTEGER CHECK (capacity_items > 0),
+    ADD COLUMN IF NOT EXISTS capacity_volume NUMERIC(12, 3) CHECK (capacity_volume > 0);
+
+-- Объем товара в литрах; товары без объема не учитываются в заполненности по объему.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS volume NUMERIC(10, 3) CHECK (volume > 0);
+
+CREATE INDEX IF NOT EXISTS products_pvz_status_idx ON products (pvz_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_pvz_status_idx;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS volume;
+
+ALTER TABLE pvz
+    DROP COLUMN IF EXISTS capacity_items,
+    DROP COLUMN IF EXISTS capacity_volume;
+-- +goose StatementEnd
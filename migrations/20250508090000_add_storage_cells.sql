@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ячейки хранения в ПВЗ. code — адрес «стеллаж-полка-ячейка», уникален внутри ПВЗ.
+-- Пустой product_types — ячейка принимает товары любого типа, capacity NULL — без ограничения.
+CREATE TABLE IF NOT EXISTS storage_cells
+(
+    id            UUID PRIMARY KEY   DEFAULT gen_random_uuid(),
+    pvz_id        UUID      NOT NULL REFERENCES pvz (id) ON DELETE CASCADE,
+    code          TEXT      NOT NULL,
+    rack          TEXT      NOT NULL,
+    shelf         TEXT      NOT NULL,
+    cell          TEXT      NOT NULL,
+    capacity      INTEGER CHECK (capacity > 0),
+    product_types TEXT[]    NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT storage_cells_pvz_code_key UNIQUE (pvz_id, code)
+);
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS cell_id UUID REFERENCES storage_cells (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS products_cell_id_idx ON products (cell_id) WHERE cell_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_cell_id_idx;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS cell_id;
+
+DROP TABLE IF EXISTS storage_cells;
+-- +goose StatementEnd
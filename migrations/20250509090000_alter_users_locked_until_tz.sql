@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- locked_until сравнивается со временем приложения, поэтому храним момент времени с часовым поясом.
+-- Старые значения записаны через CURRENT_TIMESTAMP и трактуются в часовом поясе сессии.
+ALTER TABLE users
+    ALTER COLUMN locked_until TYPE TIMESTAMPTZ USING locked_until AT TIME ZONE current_setting('TimeZone');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    ALTER COLUMN locked_until TYPE TIMESTAMP USING locked_until AT TIME ZONE current_setting('TimeZone');
+-- +goose StatementEnd
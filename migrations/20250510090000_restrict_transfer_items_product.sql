@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Товар из перемещения нельзя удалить отдельно, иначе вместе с ним пропадет история перемещения.
+-- NO ACTION, а не RESTRICT: проверка идет в конце запроса, поэтому удаление ПВЗ, которое каскадом
+-- удаляет и товары, и перемещения, по-прежнему проходит.
+ALTER TABLE transfer_items
+    DROP CONSTRAINT IF EXISTS transfer_items_product_id_fkey,
+    ADD CONSTRAINT transfer_items_product_id_fkey
+        FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE NO ACTION;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transfer_items
+    DROP CONSTRAINT IF EXISTS transfer_items_product_id_fkey,
+    ADD CONSTRAINT transfer_items_product_id_fkey
+        FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE;
+-- +goose StatementEnd
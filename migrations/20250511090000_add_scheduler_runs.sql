@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Последний интервал, в котором запускалась задача планировщика. Интервалы отсчитываются по часам БД
+-- от начала эпохи, поэтому у всех реплик совпадают, и за интервал задачу запускает только одна из них.
+CREATE TABLE IF NOT EXISTS scheduler_runs
+(
+    name       TEXT PRIMARY KEY,
+    slot       BIGINT      NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduler_runs;
+-- +goose StatementEnd
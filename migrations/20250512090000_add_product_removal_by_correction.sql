@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Товар, удаленный исправлением закрытой приемки, остается в products с отдельным статусом,
+-- чтобы не потерять его перемещения, возвраты и историю в выгрузках.
+ALTER TYPE product_status ADD VALUE IF NOT EXISTS 'removed_by_correction';
+
+-- Исправления пишутся в журнал действий отдельно, чтобы не попадать в отчет о производительности.
+ALTER TYPE product_action ADD VALUE IF NOT EXISTS 'correction_added';
+ALTER TYPE product_action ADD VALUE IF NOT EXISTS 'correction_removed';
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS removal_reason TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Значения перечислений не удаляются: в PostgreSQL для этого нужно пересоздать тип.
+ALTER TABLE products
+    DROP COLUMN IF EXISTS removal_reason;
+-- +goose StatementEnd
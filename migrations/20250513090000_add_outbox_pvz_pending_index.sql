@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Relay ищет первое неопубликованное событие каждого ПВЗ.
+CREATE INDEX IF NOT EXISTS outbox_pvz_pending_idx ON outbox (pvz_id, id) WHERE published_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS outbox_pvz_pending_idx;
+-- +goose StatementEnd
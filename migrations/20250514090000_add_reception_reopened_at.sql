@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Время переоткрытия приемки модератором: от него, как и от последнего товара, считается простой
+-- для автозакрытия, иначе старая приемка закрывалась бы снова на следующем запуске.
+ALTER TABLE receptions
+    ADD COLUMN IF NOT EXISTS reopened_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE receptions
+    DROP COLUMN IF EXISTS reopened_at;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Время событий и повторов пишется и сравнивается со временем приложения, поэтому храним моменты
+-- времени с часовым поясом. Старые значения трактуются в часовом поясе сессии.
+ALTER TABLE outbox
+    ALTER COLUMN occurred_at TYPE TIMESTAMPTZ USING occurred_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN published_at TYPE TIMESTAMPTZ USING published_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN next_attempt_at TYPE TIMESTAMPTZ USING next_attempt_at AT TIME ZONE current_setting('TimeZone');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox
+    ALTER COLUMN occurred_at TYPE TIMESTAMP USING occurred_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN published_at TYPE TIMESTAMP USING published_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN next_attempt_at TYPE TIMESTAMP USING next_attempt_at AT TIME ZONE current_setting('TimeZone');
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Время повтора и доставки пишется из приложения и сравнивается с CURRENT_TIMESTAMP, поэтому храним
+-- моменты времени с часовым поясом. Старые значения трактуются в часовом поясе сессии.
+ALTER TABLE webhook_deliveries
+    ALTER COLUMN next_attempt_at TYPE TIMESTAMPTZ USING next_attempt_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN delivered_at TYPE TIMESTAMPTZ USING delivered_at AT TIME ZONE current_setting('TimeZone');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries
+    ALTER COLUMN next_attempt_at TYPE TIMESTAMP USING next_attempt_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN delivered_at TYPE TIMESTAMP USING delivered_at AT TIME ZONE current_setting('TimeZone');
+-- +goose StatementEnd